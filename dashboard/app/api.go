@@ -34,6 +34,7 @@ var apiHandlers = map[string]APIHandler{
 	"builder_poll":        apiBuilderPoll,
 	"report_crash":        apiReportCrash,
 	"report_failed_repro": apiReportFailedRepro,
+	"report_retest":       apiReportRetest,
 	"need_repro":          apiNeedRepro,
 	"reporting_poll":      apiReportingPoll,
 	"reporting_update":    apiReportingUpdate,
@@ -334,6 +335,7 @@ func apiReportCrash(c context.Context, ns string, r *http.Request) (interface{},
 		Manager:     build.Manager,
 		BuildID:     req.BuildID,
 		Time:        timeNow(c),
+		Signature:   req.Signature,
 		Maintainers: req.Maintainers,
 		ReproOpts:   req.ReproOpts,
 		ReportLen:   len(req.Report),
@@ -512,6 +514,88 @@ func apiReportFailedRepro(c context.Context, ns string, r *http.Request) (interf
 	return nil, nil
 }
 
+func apiReportRetest(c context.Context, ns string, r *http.Request) (interface{}, error) {
+	req := new(dashapi.Retest)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal request: %v", err)
+	}
+	req.Title = limitLength(req.Title, maxTextLen)
+	if req.Status == dashapi.RetestFlaky {
+		return nil, nil
+	}
+	build, err := loadBuild(c, ns, req.BuildID)
+	if err != nil {
+		return nil, err
+	}
+	managers, err := managerList(c, ns)
+	if err != nil {
+		return nil, err
+	}
+	now := timeNow(c)
+	tx := func(c context.Context) error {
+		var bugKey *datastore.Key
+		bug := new(Bug)
+		for seq := int64(0); ; seq++ {
+			bugHash := bugKeyHash(ns, req.Title, seq)
+			bugKey = datastore.NewKey(c, "Bug", bugHash, 0, nil)
+			if err := datastore.Get(c, bugKey, bug); err != nil {
+				if err == datastore.ErrNoSuchEntity {
+					return nil
+				}
+				return fmt.Errorf("failed to get bug: %v", err)
+			}
+			if bug.Status == BugStatusOpen || bug.Status == BugStatusDup {
+				break
+			}
+		}
+		if bug.Status != BugStatusOpen {
+			return nil
+		}
+		if req.Status == dashapi.RetestReproducing {
+			if !stringInList(bug.NoReproOn, build.Manager) {
+				return nil
+			}
+			var noRepro []string
+			for _, mgr := range bug.NoReproOn {
+				if mgr != build.Manager {
+					noRepro = append(noRepro, mgr)
+				}
+			}
+			bug.NoReproOn = noRepro
+			bug.NoReproSince = time.Time{}
+		} else {
+			if !stringInList(bug.NoReproOn, build.Manager) {
+				bug.NoReproOn = append(bug.NoReproOn, build.Manager)
+			}
+			noRepro := true
+			for _, mgr := range managers {
+				if !stringInList(bug.NoReproOn, mgr) {
+					noRepro = false
+					break
+				}
+			}
+			if noRepro && bug.NoReproSince.IsZero() {
+				bug.NoReproSince = now
+			}
+			// A single retest round can be flaky, so the bug is closed only if
+			// the repro does not crash on any manager for a long time.
+			closeAfter := config.Namespaces[ns].CloseNoRepro
+			if noRepro && closeAfter != 0 && now.Sub(bug.NoReproSince) >= closeAfter {
+				bug.Status = BugStatusFixed
+				bug.Closed = now
+			}
+		}
+		if _, err := datastore.Put(c, bugKey, bug); err != nil {
+			return fmt.Errorf("failed to put bug: %v", err)
+		}
+		return nil
+	}
+	if err := datastore.RunInTransaction(c, tx, &datastore.TransactionOptions{XG: true}); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
 func apiNeedRepro(c context.Context, ns string, r *http.Request) (interface{}, error) {
 	req := new(dashapi.CrashID)
 	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
@@ -8,6 +8,7 @@ package dash
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/syzkaller/dashboard/dashapi"
 )
@@ -20,7 +21,8 @@ var config = GlobalConfig{
 	},
 	Namespaces: map[string]*Config{
 		"test1": &Config{
-			Key: "test1keytest1keytest1key",
+			Key:          "test1keytest1keytest1key",
+			CloseNoRepro: 7 * 24 * time.Hour,
 			Clients: map[string]string{
 				client1: key1,
 			},
@@ -36,6 +36,9 @@ type Config struct {
 	MailWithoutReport bool
 	// How long should we wait for a C repro before reporting a bug.
 	WaitForRepro time.Duration
+	// Close bugs as fixed if their repro does not crash on retest on any manager
+	// for this long (0 disables closing, such bugs are only marked as not reproducible).
+	CloseNoRepro time.Duration
 	// Reporting config.
 	Reporting []Reporting
 }
@@ -22,6 +22,7 @@ var example = GlobalConfig{
 			},
 			MailWithoutReport: false,
 			WaitForRepro:      12 * time.Hour,
+			CloseNoRepro:      7 * 24 * time.Hour,
 			Reporting: []Reporting{
 				Reporting{
 					Name:       "upstream",
@@ -55,6 +55,10 @@ type Bug struct {
 	Reporting  []BugReporting
 	Commits    []string
 	PatchedOn  []string
+	NoReproOn  []string // managers where the repro did not crash on retest
+	// Time since the repro does not crash on any manager, zero if it crashes somewhere.
+	// The bug is closed as fixed once this is older than Config.CloseNoRepro.
+	NoReproSince time.Time
 }
 
 type BugReporting struct {
@@ -72,6 +76,7 @@ type Crash struct {
 	Manager     string
 	BuildID     string
 	Time        time.Time
+	Signature   string   // hash of the top stack frames, crashes with different signatures can be different bugs
 	Maintainers []string `datastore:",noindex"`
 	Log         int64    // reference to CrashLog text entity
 	Report      int64    // reference to CrashReport text entity
@@ -7,8 +7,11 @@ package dash
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/syzkaller/dashboard/dashapi"
+	"golang.org/x/net/context"
+	"google.golang.org/appengine/datastore"
 )
 
 func reportAllBugs(c *Ctx, expect int) []*dashapi.BugReport {
@@ -438,3 +441,128 @@ func TestReFixedTwoManagers(t *testing.T) {
 	reports = reportAllBugs(c, 1)
 	c.expectEQ(reports[0].Title, "title1 (2)")
 }
+
+func loadTestBug(c context.Context, ns, title string) *Bug {
+	bug := new(Bug)
+	key := datastore.NewKey(c, "Bug", bugKeyHash(ns, title, 0), 0, nil)
+	if err := datastore.Get(c, key, bug); err != nil {
+		panic(err)
+	}
+	return bug
+}
+
+// Test that a bug is marked as not reproducible once its repro does not crash on all managers,
+// but is not closed right away.
+func TestNoReproByRetest(t *testing.T) {
+	c := NewCtx(t)
+	defer c.Close()
+
+	build1 := testBuild(1)
+	c.expectOK(c.API(client1, key1, "upload_build", build1, nil))
+	build2 := testBuild(2)
+	c.expectOK(c.API(client1, key1, "upload_build", build2, nil))
+
+	crash1 := testCrash(build1, 1)
+	c.expectOK(c.API(client1, key1, "report_crash", crash1, nil))
+	reportAllBugs(c, 1)
+
+	// The first manager does not reproduce the crash anymore, flaky results are ignored.
+	retest := &dashapi.Retest{
+		BuildID: build1.ID,
+		Title:   crash1.Title,
+		Status:  dashapi.RetestFixed,
+	}
+	c.expectOK(c.API(client1, key1, "report_retest", retest, nil))
+	retest = &dashapi.Retest{
+		BuildID: build2.ID,
+		Title:   crash1.Title,
+		Status:  dashapi.RetestFlaky,
+	}
+	c.expectOK(c.API(client1, key1, "report_retest", retest, nil))
+
+	// The bug is still open.
+	c.expectOK(c.API(client1, key1, "report_crash", crash1, nil))
+	reportAllBugs(c, 0)
+
+	// The crash reproduces on the first manager again, and then stops reproducing on the second one.
+	retest = &dashapi.Retest{
+		BuildID: build1.ID,
+		Title:   crash1.Title,
+		Status:  dashapi.RetestReproducing,
+	}
+	c.expectOK(c.API(client1, key1, "report_retest", retest, nil))
+	retest = &dashapi.Retest{
+		BuildID: build2.ID,
+		Title:   crash1.Title,
+		Status:  dashapi.RetestFixed,
+	}
+	c.expectOK(c.API(client1, key1, "report_retest", retest, nil))
+	c.expectOK(c.API(client1, key1, "report_crash", crash1, nil))
+	reportAllBugs(c, 0)
+
+	c.expectEQ(loadTestBug(c.ctx, "test1", crash1.Title).NoReproSince.IsZero(), true)
+
+	// Now the first manager does not reproduce it as well, the bug is marked, but still open.
+	retest = &dashapi.Retest{
+		BuildID: build1.ID,
+		Title:   crash1.Title,
+		Status:  dashapi.RetestFixed,
+	}
+	c.expectOK(c.API(client1, key1, "report_retest", retest, nil))
+	bug := loadTestBug(c.ctx, "test1", crash1.Title)
+	c.expectEQ(bug.Status, BugStatusOpen)
+	c.expectEQ(bug.NoReproSince.IsZero(), false)
+	c.expectOK(c.API(client1, key1, "report_crash", crash1, nil))
+	reportAllBugs(c, 0)
+
+	// The crash reproduces again, the mark is cleared.
+	retest = &dashapi.Retest{
+		BuildID: build2.ID,
+		Title:   crash1.Title,
+		Status:  dashapi.RetestReproducing,
+	}
+	c.expectOK(c.API(client1, key1, "report_retest", retest, nil))
+	c.expectEQ(loadTestBug(c.ctx, "test1", crash1.Title).NoReproSince.IsZero(), true)
+}
+
+// Test that a bug is closed as fixed if its repro does not crash on all managers for CloseNoRepro.
+func TestFixedByRetest(t *testing.T) {
+	c := NewCtx(t)
+	defer c.Close()
+
+	build1 := testBuild(1)
+	c.expectOK(c.API(client1, key1, "upload_build", build1, nil))
+	build2 := testBuild(2)
+	c.expectOK(c.API(client1, key1, "upload_build", build2, nil))
+
+	crash1 := testCrash(build1, 1)
+	c.expectOK(c.API(client1, key1, "report_crash", crash1, nil))
+	reportAllBugs(c, 1)
+
+	retest1 := &dashapi.Retest{
+		BuildID: build1.ID,
+		Title:   crash1.Title,
+		Status:  dashapi.RetestFixed,
+	}
+	retest2 := &dashapi.Retest{
+		BuildID: build2.ID,
+		Title:   crash1.Title,
+		Status:  dashapi.RetestFixed,
+	}
+	c.expectOK(c.API(client1, key1, "report_retest", retest1, nil))
+	c.expectOK(c.API(client1, key1, "report_retest", retest2, nil))
+
+	// The period has not passed yet, the bug is still open.
+	c.advanceTime(24 * time.Hour)
+	c.expectOK(c.API(client1, key1, "report_retest", retest1, nil))
+	c.expectEQ(loadTestBug(c.ctx, "test1", crash1.Title).Status, BugStatusOpen)
+
+	// The repro still does not crash after the period, the bug is closed.
+	c.advanceTime(7 * 24 * time.Hour)
+	c.expectOK(c.API(client1, key1, "report_retest", retest1, nil))
+	c.expectEQ(loadTestBug(c.ctx, "test1", crash1.Title).Status, BugStatusFixed)
+
+	c.expectOK(c.API(client1, key1, "report_crash", crash1, nil))
+	reports := reportAllBugs(c, 1)
+	c.expectEQ(reports[0].Title, "title1 (2)")
+}
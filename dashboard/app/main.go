@@ -55,6 +55,7 @@ type uiBug struct {
 	Status         string
 	Link           string
 	Commits        string
+	NoReproSince   time.Time
 }
 
 type uiCrash struct {
@@ -186,6 +187,7 @@ func createUIBug(c context.Context, bug *Bug, state *ReportingState) *uiBug {
 		Status:         status,
 		Link:           link,
 		Commits:        fmt.Sprintf("%q", bug.Commits),
+		NoReproSince:   bug.NoReproSince,
 	}
 	return uiBug
 }
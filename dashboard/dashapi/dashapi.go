@@ -79,6 +79,7 @@ func (dash *Dashboard) BuilderPoll(manager string) (*BuilderPollResp, error) {
 type Crash struct {
 	BuildID     string // refers to Build.ID
 	Title       string
+	Signature   string // hash of the top stack frames, see report.StackSignature
 	Maintainers []string
 	Log         []byte
 	Report      []byte
@@ -120,6 +121,20 @@ func (dash *Dashboard) ReportFailedRepro(crash *CrashID) error {
 	return dash.query("report_failed_repro", crash, nil)
 }
 
+// Retest is a result of re-running an existing reproducer on a new kernel build.
+type Retest struct {
+	BuildID string
+	Title   string
+	Status  RetestStatus
+}
+
+// ReportRetest notifies dashboard whether the crash still reproduces on the build.
+// Bugs that do not reproduce on all managers anymore are marked as not reproducible
+// and are closed as fixed if they still do not reproduce after the namespace CloseNoRepro period.
+func (dash *Dashboard) ReportRetest(retest *Retest) error {
+	return dash.query("report_retest", retest, nil)
+}
+
 type LogEntry struct {
 	Name string
 	Text string
@@ -183,8 +198,9 @@ type PollResponse struct {
 }
 
 type (
-	BugStatus  int
-	ReproLevel int
+	BugStatus    int
+	ReproLevel   int
+	RetestStatus int
 )
 
 const (
@@ -201,6 +217,12 @@ const (
 	ReproLevelC
 )
 
+const (
+	RetestReproducing RetestStatus = iota // crashed in all attempts
+	RetestFixed                           // did not crash in any attempt
+	RetestFlaky                           // crashed in some attempts
+)
+
 func (dash *Dashboard) query(method string, req, reply interface{}) error {
 	return Query(dash.Client, dash.Addr, dash.Key, method,
 		http.NewRequest, http.DefaultClient.Do, req, reply)
@@ -42,18 +42,30 @@ extern unsigned syscall_count;
 
 #if defined(SYZ_EXECUTOR) || (defined(SYZ_REPEAT) && defined(SYZ_WAIT_REPEAT)) ||            \
     defined(SYZ_USE_TMP_DIR) || defined(SYZ_TUN_ENABLE) || defined(SYZ_SANDBOX_NAMESPACE) || \
-    defined(SYZ_SANDBOX_SETUID) || defined(SYZ_FAULT_INJECTION) || defined(__NR_syz_kvm_setup_cpu)
+    defined(SYZ_SANDBOX_SETUID) || defined(SYZ_FAULT_INJECTION) || defined(SYZ_LEAK) ||      \
+    defined(__NR_syz_kvm_setup_cpu)
 const int kFailStatus = 67;
 const int kRetryStatus = 69;
 #endif
 
 #if defined(SYZ_EXECUTOR)
 const int kErrorStatus = 68;
+
+// Exit statuses for failures in different executor setup phases,
+// they allow fuzzer to distinguish infrastructure problems from kernel bugs.
+const int kSetupFailStatus = 70;
+const int kSandboxFailStatus = 71;
+const int kKcovFailStatus = 72;
+const int kShmemFailStatus = 73;
+
+// Exit status used by fail, executor changes it depending on the current phase.
+static int fail_status = kFailStatus;
 #endif
 
 #if defined(SYZ_EXECUTOR) || (defined(SYZ_REPEAT) && defined(SYZ_WAIT_REPEAT)) ||            \
     defined(SYZ_USE_TMP_DIR) || defined(SYZ_TUN_ENABLE) || defined(SYZ_SANDBOX_NAMESPACE) || \
-    defined(SYZ_SANDBOX_SETUID) || defined(SYZ_FAULT_INJECTION) || defined(__NR_syz_kvm_setup_cpu)
+    defined(SYZ_SANDBOX_SETUID) || defined(SYZ_FAULT_INJECTION) || defined(SYZ_LEAK) ||      \
+    defined(__NR_syz_kvm_setup_cpu)
 // logical error (e.g. invalid input program), use as an assert() alernative
 NORETURN static void fail(const char* msg, ...)
 {
@@ -66,7 +78,11 @@ NORETURN static void fail(const char* msg, ...)
 	fprintf(stderr, " (errno %d)\n", e);
 	// ENOMEM/EAGAIN is frequent cause of failures in fuzzing context,
 	// so handle it here as non-fatal error.
+#if defined(SYZ_EXECUTOR)
+	doexit((e == ENOMEM || e == EAGAIN) ? kRetryStatus : fail_status);
+#else
 	doexit((e == ENOMEM || e == EAGAIN) ? kRetryStatus : kFailStatus);
+#endif
 }
 #endif
 
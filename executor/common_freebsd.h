@@ -98,3 +98,14 @@ static int fault_injected(int fail_fd)
 	return 0;
 }
 #endif
+
+#if defined(SYZ_EXECUTOR)
+static void race_affinity(int cpu)
+{
+}
+
+static void race_delay(uint64_t us)
+{
+	usleep(us);
+}
+#endif
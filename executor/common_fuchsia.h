@@ -101,6 +101,17 @@ static int fault_injected(int fail_fd)
 }
 #endif
 
+#if defined(SYZ_EXECUTOR)
+static void race_affinity(int cpu)
+{
+}
+
+static void race_delay(uint64_t us)
+{
+	usleep(us);
+}
+#endif
+
 #if defined(SYZ_EXECUTOR) || defined(__NR_syz_mmap)
 long syz_mmap(size_t addr, size_t size)
 {
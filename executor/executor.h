@@ -45,6 +45,39 @@ sandbox_type flag_sandbox;
 bool flag_enable_tun;
 bool flag_enable_fault_injection;
 
+// If true, then executor does not compute signal and instead returns
+// raw (not deduplicated) coverage traces for all calls, signal is computed by fuzzer.
+bool flag_raw_cover;
+
+// If true, then executor accounts (and limits) memory and pids with cgroups.
+bool flag_cgroups;
+
+// If true, then executor writes a marker with the call index to the trace marker fd
+// before every call when comparisons are collected (see trace_call).
+bool flag_call_markers;
+
+// Per-program resource limits (0 means no limit).
+uint64_t limit_memory; // in bytes
+uint64_t limit_pids;
+uint64_t limit_fds;
+uint64_t limit_cpu; // in seconds
+
+// Resource usage of a program is written to output right after the number of completed calls.
+enum {
+	kUsageMaxRSS, // peak resident set size of the test process (in KB)
+	kUsageCPUTime, // user+system CPU time of the test process (in ms)
+	kUsageFds, // number of open fds at the end of the program
+	kUsagePids, // peak number of tasks in the pids cgroup
+	kUsageMemory, // peak memory usage of the memory cgroup (in KB)
+	kUsageLimits, // bitmask of limits hit by the program (kLimit* constants)
+	kUsageWords,
+};
+
+const uint32_t kLimitMemory = 1 << 0;
+const uint32_t kLimitPids = 1 << 1;
+const uint32_t kLimitFds = 1 << 2;
+const uint32_t kLimitCPU = 1 << 3;
+
 bool flag_collect_cover;
 bool flag_dedup_cover;
 
@@ -56,6 +89,15 @@ bool flag_inject_fault;
 int flag_fault_call;
 int flag_fault_nth;
 
+// Execute flag_race_call1-th and flag_race_call2-th syscalls concurrently:
+// the first one is not waited for and the second one is started after flag_race_delay microseconds.
+// With flag_race_affinity the two calls are pinned to different CPUs.
+bool flag_race;
+bool flag_race_affinity;
+int flag_race_call1;
+int flag_race_call2;
+uint64_t flag_race_delay;
+
 int running;
 uint32_t completed;
 bool collide;
@@ -145,6 +187,7 @@ void cover_open();
 void cover_enable(thread_t* th);
 void cover_reset(thread_t* th);
 uint64_t read_cover_size(thread_t* th);
+void trace_call(thread_t* th, bool start);
 static uint32_t hash(uint32_t a);
 static bool dedup(uint32_t sig);
 
@@ -153,6 +196,8 @@ void execute_one(uint64_t* input_data)
 retry:
 	uint64_t* input_pos = input_data;
 	write_output(0); // Number of executed syscalls (updated later).
+	for (int i = 0; i < kUsageWords; i++)
+		write_output(0); // Resource usage (filled in by the loop process).
 
 	if (!collide && !flag_threaded)
 		cover_enable(&threads[0]);
@@ -256,11 +301,16 @@ retry:
 			args[i] = read_arg(&input_pos);
 		for (uint64_t i = num_args; i < 6; i++)
 			args[i] = 0;
+		if (flag_race && call_index == flag_race_call2)
+			race_delay(flag_race_delay);
 		thread_t* th = schedule_call(n, call_index++, call_num, num_args, args, input_pos);
 
 		if (collide && (call_index % 2) == 0) {
 			// Don't wait for every other call.
 			// We already have results from the previous execution.
+		} else if (flag_race && th->call_index == flag_race_call1 && flag_race_call1 < flag_race_call2) {
+			// Don't wait for the first racing call, it will be handled
+			// when any of the subsequent calls completes.
 		} else if (flag_threaded) {
 			// Wait for call completion.
 			// Note: sys knows about this 20ms timeout when it generates
@@ -291,7 +341,7 @@ retry:
 		}
 	}
 
-	if (flag_collide && !flag_inject_fault && !collide) {
+	if (flag_collide && !flag_inject_fault && !flag_race && !collide) {
 		debug("enabling collider\n");
 		collide = true;
 		goto retry;
@@ -368,7 +418,7 @@ void handle_completion(thread_t* th)
 	if (!collide) {
 		write_output(th->call_index);
 		write_output(th->call_num);
-		uint32_t reserrno = th->res != (uint32_t)-1 ? 0 : th->reserrno;
+		uint32_t reserrno = th->res != -1 ? 0 : th->reserrno;
 		write_output(reserrno);
 		write_output(th->fault_injected);
 		uint32_t* signal_count_pos = write_output(0); // filled in later
@@ -390,7 +440,7 @@ void handle_completion(thread_t* th)
 			// Currently it is code edges computed as xor of
 			// two subsequent basic block PCs.
 			uint32_t prev = 0;
-			for (uint32_t i = 0; i < th->cover_size; i++) {
+			for (uint32_t i = 0; i < th->cover_size && !flag_raw_cover; i++) {
 				uint32_t pc = (uint32_t)th->cover_data[i];
 				uint32_t sig = pc ^ prev;
 				prev = hash(pc);
@@ -399,10 +449,10 @@ void handle_completion(thread_t* th)
 				write_output(sig);
 				nsig++;
 			}
-			if (flag_collect_cover) {
+			if (flag_collect_cover || flag_raw_cover) {
 				// Write out real coverage (basic block PCs).
 				cover_size = th->cover_size;
-				if (flag_dedup_cover) {
+				if (flag_dedup_cover && !flag_raw_cover) {
 					uint64_t* start = (uint64_t*)th->cover_data;
 					uint64_t* end = start + cover_size;
 					std::sort(start, end);
@@ -474,20 +524,30 @@ void execute_call(thread_t* th)
 		fail_fd = inject_fault(flag_fault_nth);
 	}
 
+	bool race_call = flag_race && flag_race_affinity &&
+			 (th->call_index == flag_race_call1 || th->call_index == flag_race_call2);
+	if (race_call)
+		race_affinity(th->call_index == flag_race_call1 ? 0 : 1);
+
 	cover_reset(th);
+	trace_call(th, true);
 	th->res = execute_syscall(call, th->args[0], th->args[1], th->args[2],
 				  th->args[3], th->args[4], th->args[5],
 				  th->args[6], th->args[7], th->args[8]);
 	th->reserrno = errno;
+	trace_call(th, false);
 	th->cover_size = read_cover_size(th);
 	th->fault_injected = false;
 
+	if (race_call)
+		race_affinity(-1);
+
 	if (flag_inject_fault && th->call_index == flag_fault_call) {
 		th->fault_injected = fault_injected(fail_fd);
 		debug("fault injected: %d\n", th->fault_injected);
 	}
 
-	if (th->res == (uint32_t)-1)
+	if (th->res == -1)
 		debug("#%d: %s = errno(%d)\n", th->id, call->name, th->reserrno);
 	else
 		debug("#%d: %s = 0x%lx\n", th->id, call->name, th->res);
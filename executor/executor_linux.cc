@@ -7,7 +7,9 @@
 #include <limits.h>
 #include <linux/futex.h>
 #include <sys/ioctl.h>
+#include <dirent.h>
 #include <sys/prctl.h>
+#include <sys/resource.h>
 #include <sys/stat.h>
 #include <sys/syscall.h>
 #include <sys/time.h>
@@ -36,8 +38,24 @@ const int kInFd = 3;
 const int kOutFd = 4;
 const int kInPipeFd = 5;
 const int kOutPipeFd = 6;
+const int kCallMarkerFd = 7;
 const int kCoverSize = 64 << 10;
 const int kPageSize = 4 << 10;
+const int kInputHeaderWords = 6; // flags, pid and 4 resource limits
+
+static int cgroup_memory_usage_fd = -1;
+static int cgroup_memory_failcnt_fd = -1;
+static int cgroup_pids_current_fd = -1;
+static int cgroup_pids_events_fd = -1;
+
+static void cgroups_setup(uint64_t executor_pid);
+static uint64_t cgroup_read(int fd, const char* key);
+static void cgroups_close();
+static void limits_setup();
+static void usage_reset();
+static uint64_t usage_sample_pids();
+static void usage_collect(int status, struct rusage* rusage, uint64_t pids_events, uint64_t pids_peak);
+static void usage_count_fds();
 
 __attribute__((aligned(64 << 10))) char input_data[kMaxInput];
 uint32_t* output_data;
@@ -51,6 +69,7 @@ int main(int argc, char** argv)
 	}
 
 	prctl(PR_SET_PDEATHSIG, SIGKILL, 0, 0, 0);
+	fail_status = kShmemFailStatus;
 	if (mmap(&input_data[0], kMaxInput, PROT_READ, MAP_PRIVATE | MAP_FIXED, kInFd, 0) != &input_data[0])
 		fail("mmap of input file failed");
 	// The output region is the only thing in executor process for which consistency matters.
@@ -67,6 +86,7 @@ int main(int argc, char** argv)
 	// That's also the reason why we close kInPipeFd/kOutPipeFd below.
 	close(kInFd);
 	close(kOutFd);
+	fail_status = kSetupFailStatus;
 
 	uint64_t flags = *(uint64_t*)input_data;
 	flag_debug = flags & (1 << 0);
@@ -82,11 +102,24 @@ int main(int argc, char** argv)
 		flag_collide = false;
 	flag_enable_tun = flags & (1 << 6);
 	flag_enable_fault_injection = flags & (1 << 7);
+	flag_raw_cover = flags & (1 << 8);
+	flag_cgroups = flags & (1 << 9);
+	flag_call_markers = flags & (1 << 10);
 
 	uint64_t executor_pid = *((uint64_t*)input_data + 1);
+	limit_memory = *((uint64_t*)input_data + 2);
+	limit_pids = *((uint64_t*)input_data + 3);
+	limit_fds = *((uint64_t*)input_data + 4);
+	limit_cpu = *((uint64_t*)input_data + 5);
+	fail_status = kKcovFailStatus;
 	cover_open();
+	fail_status = kSetupFailStatus;
 	install_segv_handler();
 	use_temporary_dir();
+	// Cgroups are set up while we are still root, the accounting files stay open
+	// so that the loop process can reset and read them after dropping privileges.
+	if (flag_cgroups)
+		cgroups_setup(executor_pid);
 
 #if defined(__i386__) || defined(__arm__)
 	// mmap syscall on i386/arm is translated to old_mmap and has different signature.
@@ -98,6 +131,8 @@ int main(int argc, char** argv)
 	}
 #endif
 
+	// Sandbox setup happens in the loop process, it resets fail_status once it's done.
+	fail_status = kSandboxFailStatus;
 	int pid = -1;
 	switch (flag_sandbox) {
 	case sandbox_none:
@@ -129,10 +164,13 @@ int main(int argc, char** argv)
 		// Not much we can do, but gcc wants us to check the return value.
 	}
 	errno = 0;
-	if (status == kFailStatus)
-		fail("loop failed");
 	if (status == kErrorStatus)
 		error("loop errored");
+	if (status == kFailStatus || (status >= kSetupFailStatus && status <= kShmemFailStatus)) {
+		// Preserve the failure kind detected by the loop process.
+		fail_status = status;
+		fail("loop failed");
+	}
 	// Loop can be killed by a test process with e.g.:
 	// ptrace(PTRACE_SEIZE, 1, 0, 0x100040)
 	// This is unfortunate, but I don't have a better solution than ignoring it for now.
@@ -143,6 +181,8 @@ int main(int argc, char** argv)
 
 void loop()
 {
+	// Sandbox is set up, from now on all failures are internal asserts.
+	fail_status = kFailStatus;
 	// Tell parent that we are ready to serve.
 	char tmp = 0;
 	if (write(kOutPipeFd, &tmp, 1) != 1)
@@ -158,7 +198,7 @@ void loop()
 		// TODO: consider moving the read into the child.
 		// Potentially it can speed up things a bit -- when the read finishes
 		// we already have a forked worker process.
-		uint64_t in_cmd[3] = {};
+		uint64_t in_cmd[6] = {};
 		if (read(kInPipeFd, &in_cmd[0], sizeof(in_cmd)) != (ssize_t)sizeof(in_cmd))
 			fail("control pipe read failed");
 		flag_collect_cover = in_cmd[0] & (1 << 0);
@@ -167,9 +207,22 @@ void loop()
 		flag_collect_comps = in_cmd[0] & (1 << 3);
 		flag_fault_call = in_cmd[1];
 		flag_fault_nth = in_cmd[2];
-		debug("exec opts: cover=%d comps=%d dedup=%d fault=%d/%d/%d\n", flag_collect_cover,
+		flag_race = in_cmd[0] & (1 << 4);
+		flag_race_affinity = in_cmd[0] & (1 << 5);
+		flag_race_call1 = in_cmd[3];
+		flag_race_call2 = in_cmd[4];
+		flag_race_delay = in_cmd[5];
+		if (!flag_threaded)
+			flag_race = false;
+		debug("exec opts: cover=%d comps=%d dedup=%d fault=%d/%d/%d race=%d/%d/%d/%llu/%d\n", flag_collect_cover,
 		      flag_collect_comps, flag_dedup_cover,
-		      flag_inject_fault, flag_fault_call, flag_fault_nth);
+		      flag_inject_fault, flag_fault_call, flag_fault_nth,
+		      flag_race, flag_race_call1, flag_race_call2, flag_race_delay, flag_race_affinity);
+
+		usage_reset();
+		uint64_t pids_events = 0;
+		if (cgroup_pids_events_fd != -1)
+			pids_events = cgroup_read(cgroup_pids_events_fd, "max ");
 
 		int pid = fork();
 		if (pid < 0)
@@ -181,14 +234,17 @@ void loop()
 				fail("failed to chdir");
 			close(kInPipeFd);
 			close(kOutPipeFd);
+			cgroups_close();
+			limits_setup();
 			if (flag_enable_tun) {
 				// Read all remaining packets from tun to better
 				// isolate consequently executing programs.
 				flush_tun();
 			}
-			uint64_t* input_pos = ((uint64_t*)&input_data[0]) + 2; // skip flags and pid
+			uint64_t* input_pos = ((uint64_t*)&input_data[0]) + kInputHeaderWords;
 			output_pos = output_data;
 			execute_one(input_pos);
+			usage_count_fds();
 			debug("worker exiting\n");
 			doexit(0);
 		}
@@ -200,16 +256,19 @@ void loop()
 		// SIGCHLD should also unblock the usleep below, so the spin loop
 		// should be as efficient as sigtimedwait.
 		int status = 0;
+		struct rusage rusage = {};
+		uint64_t pids_peak = 0;
 		uint64_t start = current_time_ms();
 		uint64_t last_executed = start;
 		uint32_t executed_calls = __atomic_load_n(output_data, __ATOMIC_RELAXED);
 		for (;;) {
-			int res = waitpid(-1, &status, __WALL | WNOHANG);
+			int res = wait4(-1, &status, __WALL | WNOHANG, &rusage);
 			int errno0 = errno;
 			if (res == pid) {
 				debug("waitpid(%d)=%d (%d)\n", pid, res, errno0);
 				break;
 			}
+			pids_peak = std::max(pids_peak, usage_sample_pids());
 			usleep(1000);
 			// Even though the test process executes exit at the end
 			// and execution time of each syscall is bounded by 20ms,
@@ -234,13 +293,14 @@ void loop()
 			kill(-pid, SIGKILL);
 			kill(pid, SIGKILL);
 			for (;;) {
-				int res = waitpid(-1, &status, __WALL);
+				int res = wait4(-1, &status, __WALL, &rusage);
 				debug("waitpid(%d)=%d (%d)\n", pid, res, errno);
 				if (res == pid)
 					break;
 			}
 			break;
 		}
+		usage_collect(status, &rusage, pids_events, pids_peak);
 		status = WEXITSTATUS(status);
 		if (status == kFailStatus)
 			fail("child failed");
@@ -252,6 +312,181 @@ void loop()
 	}
 }
 
+static void cgroup_enter(const char* dir)
+{
+	if (mkdir(dir, 0777) && errno != EEXIST)
+		fail("failed to create cgroup %s", dir);
+	char file[256];
+	snprintf(file, sizeof(file), "%s/cgroup.procs", dir);
+	if (!write_file(file, "%d", getpid()))
+		fail("failed to enter cgroup %s", dir);
+}
+
+static int cgroup_open(const char* dir, const char* name, int flags)
+{
+	char file[256];
+	snprintf(file, sizeof(file), "%s/%s", dir, name);
+	int fd = open(file, flags);
+	if (fd == -1)
+		fail("failed to open %s", file);
+	return fd;
+}
+
+// cgroup_read reads a number from the cgroup file,
+// if key is not NULL the number is looked up after the key.
+static uint64_t cgroup_read(int fd, const char* key)
+{
+	char buf[256];
+	ssize_t n = pread(fd, buf, sizeof(buf) - 1, 0);
+	if (n <= 0)
+		return 0;
+	buf[n] = 0;
+	const char* pos = buf;
+	if (key) {
+		pos = strstr(buf, key);
+		if (!pos)
+			return 0;
+		pos += strlen(key);
+	}
+	return strtoull(pos, NULL, 10);
+}
+
+static void cgroup_reset(int fd)
+{
+	if (pwrite(fd, "0", 1, 0) != 1)
+		debug("failed to reset cgroup counter: %d\n", errno);
+}
+
+static void cgroups_setup(uint64_t executor_pid)
+{
+	// Note: executor and loop processes also belong to the cgroups,
+	// so the usage includes their (small) footprint.
+	char dir[128];
+	snprintf(dir, sizeof(dir), "/sys/fs/cgroup/memory/syz%llu", (unsigned long long)executor_pid);
+	cgroup_enter(dir);
+	if (limit_memory) {
+		char file[256];
+		snprintf(file, sizeof(file), "%s/memory.limit_in_bytes", dir);
+		if (!write_file(file, "%llu", (unsigned long long)limit_memory))
+			fail("failed to set memory limit");
+	}
+	cgroup_memory_usage_fd = cgroup_open(dir, "memory.max_usage_in_bytes", O_RDWR);
+	cgroup_memory_failcnt_fd = cgroup_open(dir, "memory.failcnt", O_RDWR);
+
+	snprintf(dir, sizeof(dir), "/sys/fs/cgroup/pids/syz%llu", (unsigned long long)executor_pid);
+	cgroup_enter(dir);
+	if (limit_pids) {
+		char file[256];
+		snprintf(file, sizeof(file), "%s/pids.max", dir);
+		if (!write_file(file, "%llu", (unsigned long long)limit_pids))
+			fail("failed to set pids limit");
+	}
+	cgroup_pids_current_fd = cgroup_open(dir, "pids.current", O_RDONLY);
+	cgroup_pids_events_fd = cgroup_open(dir, "pids.events", O_RDONLY);
+}
+
+// cgroups_close closes accounting files in the test process,
+// so that test programs can't mess with them.
+static void cgroups_close()
+{
+	if (!flag_cgroups)
+		return;
+	close(cgroup_memory_usage_fd);
+	close(cgroup_memory_failcnt_fd);
+	close(cgroup_pids_current_fd);
+	close(cgroup_pids_events_fd);
+}
+
+// fds_limit returns RLIMIT_NOFILE for the test process: limit_fds is meant for fds
+// opened by the program, so kcov and call marker fds inherited by the test process are added on top.
+static uint64_t fds_limit()
+{
+	return limit_fds + (flag_cover ? kMaxThreads : 0) + (flag_call_markers ? 1 : 0);
+}
+
+static void limits_setup()
+{
+	struct rlimit rlim;
+	if (limit_fds) {
+		rlim.rlim_cur = rlim.rlim_max = fds_limit();
+		setrlimit(RLIMIT_NOFILE, &rlim);
+	}
+	if (limit_cpu) {
+		// The soft limit delivers SIGXCPU, the hard one SIGKILL.
+		rlim.rlim_cur = limit_cpu;
+		rlim.rlim_max = limit_cpu + 1;
+		setrlimit(RLIMIT_CPU, &rlim);
+	}
+	if (limit_memory && !flag_cgroups) {
+		// Without cgroups we can only lower the address space limit set by sandbox.
+		if (getrlimit(RLIMIT_AS, &rlim) == 0 && limit_memory < rlim.rlim_max) {
+			rlim.rlim_cur = rlim.rlim_max = limit_memory;
+			setrlimit(RLIMIT_AS, &rlim);
+		}
+	}
+}
+
+static void usage_reset()
+{
+	for (int i = 0; i < kUsageWords; i++)
+		output_data[1 + i] = 0;
+	if (!flag_cgroups)
+		return;
+	cgroup_reset(cgroup_memory_usage_fd);
+	cgroup_reset(cgroup_memory_failcnt_fd);
+}
+
+static uint64_t usage_sample_pids()
+{
+	if (!flag_cgroups)
+		return 0;
+	return cgroup_read(cgroup_pids_current_fd, NULL);
+}
+
+static void usage_collect(int status, struct rusage* rusage, uint64_t pids_events, uint64_t pids_peak)
+{
+	uint32_t limits = output_data[1 + kUsageLimits];
+	uint64_t cpu = (rusage->ru_utime.tv_sec + rusage->ru_stime.tv_sec) * 1000 +
+		       (rusage->ru_utime.tv_usec + rusage->ru_stime.tv_usec) / 1000;
+	output_data[1 + kUsageMaxRSS] = rusage->ru_maxrss;
+	output_data[1 + kUsageCPUTime] = cpu;
+	if (limit_cpu && ((WIFSIGNALED(status) && WTERMSIG(status) == SIGXCPU) || cpu >= limit_cpu * 1000))
+		limits |= kLimitCPU;
+	if (flag_cgroups) {
+		output_data[1 + kUsagePids] = pids_peak;
+		output_data[1 + kUsageMemory] = cgroup_read(cgroup_memory_usage_fd, NULL) >> 10;
+		if (cgroup_read(cgroup_memory_failcnt_fd, NULL))
+			limits |= kLimitMemory;
+		if (cgroup_read(cgroup_pids_events_fd, "max ") != pids_events)
+			limits |= kLimitPids;
+	}
+	output_data[1 + kUsageLimits] = limits;
+	debug("usage: rss=%uKB cpu=%ums fds=%u pids=%u mem=%uKB limits=0x%x\n",
+	      output_data[1 + kUsageMaxRSS], output_data[1 + kUsageCPUTime], output_data[1 + kUsageFds],
+	      output_data[1 + kUsagePids], output_data[1 + kUsageMemory], limits);
+}
+
+// usage_count_fds is executed in the test process after the program has finished.
+static void usage_count_fds()
+{
+	DIR* dir = opendir("/proc/self/fd");
+	if (!dir) {
+		if (errno == EMFILE)
+			output_data[1 + kUsageLimits] |= kLimitFds;
+		return;
+	}
+	uint32_t n = 0;
+	while (struct dirent* ent = readdir(dir)) {
+		if (ent->d_name[0] != '.')
+			n++;
+	}
+	closedir(dir);
+	// Don't count the fd of the directory itself.
+	output_data[1 + kUsageFds] = n ? n - 1 : 0;
+	if (limit_fds && n >= fds_limit())
+		output_data[1 + kUsageLimits] |= kLimitFds;
+}
+
 long execute_syscall(call_t* c, long a0, long a1, long a2, long a3, long a4, long a5, long a6, long a7, long a8)
 {
 	if (c->call)
@@ -312,6 +547,24 @@ uint64_t read_cover_size(thread_t* th)
 	return n;
 }
 
+// trace_call writes a marker to the trace of the kprobe string comparisons source
+// (see pkg/ipc/strcomps_linux.go) before and after the call, so that comparisons
+// are attributed to the call that runs in the same thread.
+void trace_call(thread_t* th, bool start)
+{
+	if (!flag_call_markers || !flag_collect_comps)
+		return;
+	char buf[32];
+	int n;
+	if (start)
+		n = snprintf(buf, sizeof(buf), "syz-call %d", th->call_index);
+	else
+		n = snprintf(buf, sizeof(buf), "syz-call end");
+	if (write(kCallMarkerFd, buf, n) != n) {
+		// The program can close or replace the fd, then comparisons are dropped.
+	}
+}
+
 uint32_t* write_output(uint32_t v)
 {
 	if (collide)
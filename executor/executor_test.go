@@ -0,0 +1,316 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// +build linux
+
+package executor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/pkg/csource"
+	"github.com/google/syzkaller/pkg/ipc"
+	"github.com/google/syzkaller/prog"
+	_ "github.com/google/syzkaller/sys"
+)
+
+// Executor tests run programs from sys/linux/test.txt through the real executor binary.
+// Every program ends with syz_compare calls that check that the executor laid out
+// arguments in memory as expected. syz_compare fails with EINVAL on mismatch,
+// then the test simulates copyin instructions produced by prog/encodingexec.go
+// to point to the argument that is encoded incorrectly.
+var executorTests = []struct {
+	name   string
+	prog   string
+	errnos map[int]int // expected non-zero errnos by call index (call 0 is testMmap)
+}{
+	{
+		name: "align",
+		prog: `syz_compare(&(0x7f0000000000)="010000000200000003000400000000000500000000000000", 0x18, &(0x7f0000001000)=@align0={0x1, 0x2, 0x3, 0x4, 0x5}, 0x18)`,
+	},
+	{
+		name: "struct",
+		prog: `syz_compare(&(0x7f0000000000)="01000000000000000200000000000000", 0x10, &(0x7f0000001000)=@struct={0x1, {0x2}}, 0x10)`,
+	},
+	{
+		name: "bitfield",
+		prog: `syz_compare(&(0x7f0000000000)="4208210442000000", 0x8, &(0x7f0000001000)=@bf1={{0x42, 0x42, 0x42}, 0x42}, 0x8)`,
+	},
+	{
+		name: "bigendian-int",
+		prog: `syz_compare(&(0x7f0000000000)="420042000000420000000000000042", 0xf, &(0x7f0000001000)=@end0={0x42, 0x42, 0x42, 0x42}, 0xf)`,
+	},
+	{
+		name: "bigendian-len-flags",
+		prog: `syz_compare(&(0x7f0000000000)="000e000000420000000000000001", 0xe, &(0x7f0000001000)=@end1={0xe, 0x42, 0x1}, 0xe)`,
+	},
+	{
+		// Values are start + perProc*pid + val, pid is testPid.
+		name: "proc",
+		prog: `syz_compare(&(0x7f0000000000)="00712e4e0000", 0x6, &(0x7f0000001000)=@proc={0x1, 0x2}, 0x6)`,
+	},
+	{
+		name: "csum-ipv4",
+		prog: `syz_compare(&(0x7f0000000000)="ebfc0a0000010a000002", 0xa, &(0x7f0000001000)=@csum_ipv4={0x0, 0xa000001, 0xa000002}, 0xa)`,
+	},
+	{
+		name: "csum-ipv4-udp",
+		prog: `syz_compare(&(0x7f0000000000)="ebfc0a0000010a0000025118abcdef", 0xf, &(0x7f0000001000)=@csum_ipv4_udp={{0x0, 0xa000001, 0xa000002}, {0x0, "abcdef"}}, 0xf)`,
+	},
+	{
+		// Results of pipe are passed to write and read, read data is checked by syz_compare.
+		name: "results",
+		prog: `pipe(&(0x7f0000000000)={<r0=>0xffffffffffffffff, <r1=>0xffffffffffffffff})
+write(r1, &(0x7f0000001000)="0102030405", 0x5)
+read(r0, &(0x7f0000002000)="0000000000", 0x5)
+syz_compare(&(0x7f0000003000)="0102030405", 0x5, &(0x7f0000002000)=@blob="", 0x5)`,
+	},
+	{
+		// Check that mismatches are actually detected.
+		name:   "mismatch",
+		prog:   `syz_compare(&(0x7f0000000000)="0100", 0x2, &(0x7f0000001000)=@blob="0200", 0x2)`,
+		errnos: map[int]int{1: int(syscall.EINVAL)},
+	},
+	{
+		name:   "mismatch-size",
+		prog:   `syz_compare(&(0x7f0000000000)="0100", 0x2, &(0x7f0000001000)=@blob="010000", 0x3)`,
+		errnos: map[int]int{1: int(syscall.EBADF)},
+	},
+}
+
+const (
+	testPid     = 3
+	testTimeout = 10 * time.Second
+	testMmap    = "mmap(&(0x7f0000000000/0x10000)=nil, 0x10000, 0x3, 0x32, 0xffffffffffffffff, 0x0)\n"
+)
+
+func TestExecutor(t *testing.T) {
+	target, err := prog.GetTarget("linux", runtime.GOARCH)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bin, err := csource.Build(target, "c++", "executor_linux.cc")
+	if err == csource.NoCompilerErr {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(bin)
+	for _, flags := range []uint64{0, ipc.FlagThreaded} {
+		testExecutor(t, target, bin, flags)
+	}
+}
+
+func testExecutor(t *testing.T, target *prog.Target, bin string, flags uint64) {
+	env, err := ipc.MakeEnv(bin, testPid, ipc.Config{Flags: flags, Timeout: testTimeout})
+	if err != nil {
+		t.Fatalf("failed to create env: %v", err)
+	}
+	defer env.Close()
+	for _, test := range executorTests {
+		p, err := target.Deserialize([]byte(testMmap + test.prog))
+		if err != nil {
+			t.Fatalf("failed to deserialize %v: %v", test.name, err)
+		}
+		output, info, _, failed, hanged, err := env.Exec(&ipc.ExecOpts{}, p)
+		if err != nil || failed || hanged {
+			t.Fatalf("%v: failed to execute (failed=%v hanged=%v): %v\n%s",
+				test.name, failed, hanged, err, output)
+		}
+		if len(info) != len(p.Calls) {
+			t.Fatalf("%v: executor returned info for %v calls, program has %v",
+				test.name, len(info), len(p.Calls))
+		}
+		for i, inf := range info {
+			if inf.Errno == -1 {
+				t.Errorf("%v (flags=0x%x): call %v %v was not executed",
+					test.name, flags, i, p.Calls[i].Meta.Name)
+				continue
+			}
+			if want := test.errnos[i]; inf.Errno != want {
+				msg := ""
+				if p.Calls[i].Meta.CallName == "syz_compare" {
+					msg = "\n" + explainMismatch(p, testPid, i)
+				}
+				t.Errorf("%v (flags=0x%x): call %v %v returned errno %v, want %v%v",
+					test.name, flags, i, p.Calls[i].Meta.Name, inf.Errno, want, msg)
+			}
+		}
+	}
+}
+
+// explainMismatch simulates copyin instructions of exec encoding of program p
+// up to syz_compare call callIndex and returns description of the encoded argument
+// responsible for the first mismatching byte. Memory written by syscalls is not simulated.
+func explainMismatch(p *prog.Prog, pid, callIndex int) string {
+	buf := make([]byte, prog.ExecBufferSize)
+	n, err := p.SerializeForExec(buf, pid)
+	if err != nil {
+		return fmt.Sprintf("failed to serialize program: %v", err)
+	}
+	buf = buf[:n]
+	pos := 0
+	next := func() uint64 {
+		if pos+8 > len(buf) {
+			panic("exec encoding is truncated")
+		}
+		v := binary.LittleEndian.Uint64(buf[pos:])
+		pos += 8
+		return v
+	}
+	mem := make(map[uint64]byte)
+	writers := make(map[uint64]string)
+	store := func(addr uint64, data []byte, writer string) {
+		for i, v := range data {
+			mem[addr+uint64(i)] = v
+			writers[addr+uint64(i)] = writer
+		}
+	}
+	load := func(addr, size uint64) []byte {
+		data := make([]byte, size)
+		for i := range data {
+			data[i] = mem[addr+uint64(i)]
+		}
+		return data
+	}
+	for call := 0; ; {
+		instrPos := pos
+		switch instr := next(); instr {
+		case prog.ExecInstrEOF:
+			return fmt.Sprintf("exec encoding does not contain call %v", callIndex)
+		case prog.ExecInstrCopyout:
+			next() // addr
+			next() // size
+		case prog.ExecInstrCopyin:
+			addr, typ, size := next(), next(), next()
+			writer := fmt.Sprintf("copyin at offset %v: addr=0x%x kind=%v size=%v", instrPos, addr, typ, size)
+			switch typ {
+			case prog.ExecArgConst:
+				val, bfOff, bfLen := next(), next(), next()
+				old := make([]byte, 8)
+				copy(old, load(addr, size))
+				v := binary.LittleEndian.Uint64(old)
+				if bfLen == 0 {
+					v = val
+				} else {
+					mask := uint64(1)<<bfLen - 1
+					v = v&^(mask<<bfOff) | (val&mask)<<bfOff
+				}
+				binary.LittleEndian.PutUint64(old, v)
+				store(addr, old[:size], fmt.Sprintf("%v val=0x%x bf_off=%v bf_len=%v", writer, val, bfOff, bfLen))
+			case prog.ExecArgResult:
+				idx, opDiv, opAdd := next(), next(), next()
+				// Results are not known, assume default value.
+				store(addr, make([]byte, size), fmt.Sprintf("%v result=%v div=%v add=%v", writer, idx, opDiv, opAdd))
+			case prog.ExecArgData:
+				store(addr, buf[pos:pos+int(size)], writer)
+				pos += int(size+7) / 8 * 8
+			case prog.ExecArgCsum:
+				if kind := next(); kind != prog.ExecArgCsumInet {
+					return fmt.Sprintf("%v: unknown checksum kind %v", writer, kind)
+				}
+				var acc uint32
+				for chunks := next(); chunks > 0; chunks-- {
+					var data []byte
+					switch kind, v, sz := next(), next(), next(); kind {
+					case prog.ExecArgCsumChunkData:
+						data = load(v, sz)
+					case prog.ExecArgCsumChunkConst:
+						data = make([]byte, 8)
+						binary.LittleEndian.PutUint64(data, v)
+						data = data[:sz]
+					default:
+						return fmt.Sprintf("%v: unknown checksum chunk kind %v", writer, kind)
+					}
+					for i := 0; i+1 < len(data); i += 2 {
+						acc += uint32(binary.LittleEndian.Uint16(data[i:]))
+					}
+					if len(data)%2 != 0 {
+						acc += uint32(data[len(data)-1])
+					}
+					for acc > 0xffff {
+						acc = acc&0xffff + acc>>16
+					}
+				}
+				csum := make([]byte, 2)
+				binary.LittleEndian.PutUint16(csum, ^uint16(acc))
+				store(addr, csum, writer)
+			default:
+				return fmt.Sprintf("%v: unknown arg kind", writer)
+			}
+		default:
+			nargs := next()
+			args := make([]uint64, nargs)
+			for i := range args {
+				next() // kind, const and result args have the same size
+				next() // size
+				args[i] = next()
+				next()
+				next()
+			}
+			if call != callIndex {
+				call++
+				continue
+			}
+			if len(args) != 4 {
+				return fmt.Sprintf("call %v has %v args in exec encoding, want 4", call, len(args))
+			}
+			if args[1] != args[3] {
+				return fmt.Sprintf("want_len=%v differs from got_len=%v", args[1], args[3])
+			}
+			want, got := load(args[0], args[1]), load(args[2], args[3])
+			if bytes.Equal(want, got) {
+				return fmt.Sprintf("exec encoding produces expected data, executor diverged:\n%x", want)
+			}
+			off := uint64(0)
+			for want[off] == got[off] {
+				off++
+			}
+			res := fmt.Sprintf("mismatch at offset %v: want 0x%02x, encoded 0x%02x\nwant:    %x\nencoded: %x\n",
+				off, want[off], got[off], want, got)
+			if writer, ok := writers[args[2]+off]; ok {
+				res += fmt.Sprintf("byte is written by %v\n", writer)
+			} else {
+				res += "byte is not written by any copyin instruction\n"
+			}
+			if ptr, ok := p.Calls[call].Args[2].(*prog.PointerArg); ok && ptr.Res != nil {
+				for _, arg := range argsAtOffset(ptr.Res, 0, off) {
+					res += fmt.Sprintf("arg %v %v (%T) covers the byte\n",
+						arg.Type().Name(), arg.Type().FieldName(), arg)
+				}
+			}
+			return res
+		}
+	}
+}
+
+// argsAtOffset returns leaf args that cover byte at offset target of arg located at offset.
+// There can be several such args in case of bitfields.
+func argsAtOffset(arg prog.Arg, offset, target uint64) []prog.Arg {
+	if target < offset || target >= offset+arg.Size() {
+		return nil
+	}
+	switch a := arg.(type) {
+	case *prog.GroupArg:
+		var res []prog.Arg
+		for _, inner := range a.Inner {
+			res = append(res, argsAtOffset(inner, offset, target)...)
+			if !inner.Type().BitfieldMiddle() {
+				offset += inner.Size()
+			}
+		}
+		return res
+	case *prog.UnionArg:
+		return argsAtOffset(a.Option, offset, target)
+	default:
+		return []prog.Arg{arg}
+	}
+}
@@ -2,17 +2,18 @@
 
 #if defined(__i386__) || 0
 #define GOARCH "386"
-#define SYZ_REVISION "20782fd14495c17a4a404316929c89e9d66373d6"
-#define __NR_syz_emit_ethernet 1000000
-#define __NR_syz_extract_tcp_res 1000001
-#define __NR_syz_fuse_mount 1000002
-#define __NR_syz_fuseblk_mount 1000003
-#define __NR_syz_kvm_setup_cpu 1000004
-#define __NR_syz_open_dev 1000005
-#define __NR_syz_open_pts 1000006
-#define __NR_syz_test 1000007
+#define SYZ_REVISION "c1bed3915bd0f3552b67297bf9ca3838a3da5148"
+#define __NR_syz_compare 1000000
+#define __NR_syz_emit_ethernet 1000001
+#define __NR_syz_extract_tcp_res 1000002
+#define __NR_syz_fuse_mount 1000003
+#define __NR_syz_fuseblk_mount 1000004
+#define __NR_syz_kvm_setup_cpu 1000005
+#define __NR_syz_open_dev 1000006
+#define __NR_syz_open_pts 1000007
+#define __NR_syz_test 1000008
 
-unsigned syscall_count = 1481;
+unsigned syscall_count = 1482;
 call_t syscalls[] = {
     {"accept4", 364},
     {"accept4$ax25", 364},
@@ -1347,109 +1348,110 @@ call_t syscalls[] = {
     {"sysfs$3", 135},
     {"sysinfo", 116},
     {"syslog", 103},
-    {"syz_emit_ethernet", 1000000, (syscall_t)syz_emit_ethernet},
-    {"syz_extract_tcp_res", 1000001, (syscall_t)syz_extract_tcp_res},
-    {"syz_extract_tcp_res$synack", 1000001, (syscall_t)syz_extract_tcp_res},
-    {"syz_fuse_mount", 1000002, (syscall_t)syz_fuse_mount},
-    {"syz_fuseblk_mount", 1000003, (syscall_t)syz_fuseblk_mount},
-    {"syz_kvm_setup_cpu$arm64", 1000004, (syscall_t)syz_kvm_setup_cpu},
-    {"syz_kvm_setup_cpu$x86", 1000004, (syscall_t)syz_kvm_setup_cpu},
-    {"syz_open_dev$admmidi", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$adsp", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$amidi", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$audion", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$dmmidi", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$dri", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$dricontrol", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$drirender", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$dspn", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$evdev", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$floppy", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$ircomm", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$loop", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$mice", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$midi", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$mouse", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$random", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sg", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndctrl", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndhw", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndmidi", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndpcmc", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndpcmp", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndseq", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndtimer", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$tlk_device", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$tun", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$urandom", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$usb", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$usbmon", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$vcsa", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$vcsn", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_pts", 1000006, (syscall_t)syz_open_pts},
-    {"syz_test", 1000007, (syscall_t)syz_test},
-    {"syz_test$align0", 1000007, (syscall_t)syz_test},
-    {"syz_test$align1", 1000007, (syscall_t)syz_test},
-    {"syz_test$align2", 1000007, (syscall_t)syz_test},
-    {"syz_test$align3", 1000007, (syscall_t)syz_test},
-    {"syz_test$align4", 1000007, (syscall_t)syz_test},
-    {"syz_test$align5", 1000007, (syscall_t)syz_test},
-    {"syz_test$align6", 1000007, (syscall_t)syz_test},
-    {"syz_test$array0", 1000007, (syscall_t)syz_test},
-    {"syz_test$array1", 1000007, (syscall_t)syz_test},
-    {"syz_test$array2", 1000007, (syscall_t)syz_test},
-    {"syz_test$bf0", 1000007, (syscall_t)syz_test},
-    {"syz_test$bf1", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_encode", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_ipv4", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_ipv4_tcp", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_ipv4_udp", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_ipv6_icmp", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_ipv6_tcp", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_ipv6_udp", 1000007, (syscall_t)syz_test},
-    {"syz_test$end0", 1000007, (syscall_t)syz_test},
-    {"syz_test$end1", 1000007, (syscall_t)syz_test},
-    {"syz_test$int", 1000007, (syscall_t)syz_test},
-    {"syz_test$length0", 1000007, (syscall_t)syz_test},
-    {"syz_test$length1", 1000007, (syscall_t)syz_test},
-    {"syz_test$length10", 1000007, (syscall_t)syz_test},
-    {"syz_test$length11", 1000007, (syscall_t)syz_test},
-    {"syz_test$length12", 1000007, (syscall_t)syz_test},
-    {"syz_test$length13", 1000007, (syscall_t)syz_test},
-    {"syz_test$length14", 1000007, (syscall_t)syz_test},
-    {"syz_test$length15", 1000007, (syscall_t)syz_test},
-    {"syz_test$length16", 1000007, (syscall_t)syz_test},
-    {"syz_test$length17", 1000007, (syscall_t)syz_test},
-    {"syz_test$length18", 1000007, (syscall_t)syz_test},
-    {"syz_test$length19", 1000007, (syscall_t)syz_test},
-    {"syz_test$length2", 1000007, (syscall_t)syz_test},
-    {"syz_test$length20", 1000007, (syscall_t)syz_test},
-    {"syz_test$length3", 1000007, (syscall_t)syz_test},
-    {"syz_test$length4", 1000007, (syscall_t)syz_test},
-    {"syz_test$length5", 1000007, (syscall_t)syz_test},
-    {"syz_test$length6", 1000007, (syscall_t)syz_test},
-    {"syz_test$length7", 1000007, (syscall_t)syz_test},
-    {"syz_test$length8", 1000007, (syscall_t)syz_test},
-    {"syz_test$length9", 1000007, (syscall_t)syz_test},
-    {"syz_test$missing_resource", 1000007, (syscall_t)syz_test},
-    {"syz_test$opt0", 1000007, (syscall_t)syz_test},
-    {"syz_test$opt1", 1000007, (syscall_t)syz_test},
-    {"syz_test$opt2", 1000007, (syscall_t)syz_test},
-    {"syz_test$recur0", 1000007, (syscall_t)syz_test},
-    {"syz_test$recur1", 1000007, (syscall_t)syz_test},
-    {"syz_test$recur2", 1000007, (syscall_t)syz_test},
-    {"syz_test$regression0", 1000007, (syscall_t)syz_test},
-    {"syz_test$res0", 1000007, (syscall_t)syz_test},
-    {"syz_test$res1", 1000007, (syscall_t)syz_test},
-    {"syz_test$struct", 1000007, (syscall_t)syz_test},
-    {"syz_test$text_x86_16", 1000007, (syscall_t)syz_test},
-    {"syz_test$text_x86_32", 1000007, (syscall_t)syz_test},
-    {"syz_test$text_x86_64", 1000007, (syscall_t)syz_test},
-    {"syz_test$text_x86_real", 1000007, (syscall_t)syz_test},
-    {"syz_test$union0", 1000007, (syscall_t)syz_test},
-    {"syz_test$union1", 1000007, (syscall_t)syz_test},
-    {"syz_test$union2", 1000007, (syscall_t)syz_test},
-    {"syz_test$vma0", 1000007, (syscall_t)syz_test},
+    {"syz_compare", 1000000, (syscall_t)syz_compare},
+    {"syz_emit_ethernet", 1000001, (syscall_t)syz_emit_ethernet},
+    {"syz_extract_tcp_res", 1000002, (syscall_t)syz_extract_tcp_res},
+    {"syz_extract_tcp_res$synack", 1000002, (syscall_t)syz_extract_tcp_res},
+    {"syz_fuse_mount", 1000003, (syscall_t)syz_fuse_mount},
+    {"syz_fuseblk_mount", 1000004, (syscall_t)syz_fuseblk_mount},
+    {"syz_kvm_setup_cpu$arm64", 1000005, (syscall_t)syz_kvm_setup_cpu},
+    {"syz_kvm_setup_cpu$x86", 1000005, (syscall_t)syz_kvm_setup_cpu},
+    {"syz_open_dev$admmidi", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$adsp", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$amidi", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$audion", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$dmmidi", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$dri", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$dricontrol", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$drirender", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$dspn", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$evdev", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$floppy", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$ircomm", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$loop", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$mice", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$midi", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$mouse", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$random", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sg", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndctrl", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndhw", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndmidi", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndpcmc", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndpcmp", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndseq", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndtimer", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$tlk_device", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$tun", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$urandom", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$usb", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$usbmon", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$vcsa", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$vcsn", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_pts", 1000007, (syscall_t)syz_open_pts},
+    {"syz_test", 1000008, (syscall_t)syz_test},
+    {"syz_test$align0", 1000008, (syscall_t)syz_test},
+    {"syz_test$align1", 1000008, (syscall_t)syz_test},
+    {"syz_test$align2", 1000008, (syscall_t)syz_test},
+    {"syz_test$align3", 1000008, (syscall_t)syz_test},
+    {"syz_test$align4", 1000008, (syscall_t)syz_test},
+    {"syz_test$align5", 1000008, (syscall_t)syz_test},
+    {"syz_test$align6", 1000008, (syscall_t)syz_test},
+    {"syz_test$array0", 1000008, (syscall_t)syz_test},
+    {"syz_test$array1", 1000008, (syscall_t)syz_test},
+    {"syz_test$array2", 1000008, (syscall_t)syz_test},
+    {"syz_test$bf0", 1000008, (syscall_t)syz_test},
+    {"syz_test$bf1", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_encode", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_ipv4", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_ipv4_tcp", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_ipv4_udp", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_ipv6_icmp", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_ipv6_tcp", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_ipv6_udp", 1000008, (syscall_t)syz_test},
+    {"syz_test$end0", 1000008, (syscall_t)syz_test},
+    {"syz_test$end1", 1000008, (syscall_t)syz_test},
+    {"syz_test$int", 1000008, (syscall_t)syz_test},
+    {"syz_test$length0", 1000008, (syscall_t)syz_test},
+    {"syz_test$length1", 1000008, (syscall_t)syz_test},
+    {"syz_test$length10", 1000008, (syscall_t)syz_test},
+    {"syz_test$length11", 1000008, (syscall_t)syz_test},
+    {"syz_test$length12", 1000008, (syscall_t)syz_test},
+    {"syz_test$length13", 1000008, (syscall_t)syz_test},
+    {"syz_test$length14", 1000008, (syscall_t)syz_test},
+    {"syz_test$length15", 1000008, (syscall_t)syz_test},
+    {"syz_test$length16", 1000008, (syscall_t)syz_test},
+    {"syz_test$length17", 1000008, (syscall_t)syz_test},
+    {"syz_test$length18", 1000008, (syscall_t)syz_test},
+    {"syz_test$length19", 1000008, (syscall_t)syz_test},
+    {"syz_test$length2", 1000008, (syscall_t)syz_test},
+    {"syz_test$length20", 1000008, (syscall_t)syz_test},
+    {"syz_test$length3", 1000008, (syscall_t)syz_test},
+    {"syz_test$length4", 1000008, (syscall_t)syz_test},
+    {"syz_test$length5", 1000008, (syscall_t)syz_test},
+    {"syz_test$length6", 1000008, (syscall_t)syz_test},
+    {"syz_test$length7", 1000008, (syscall_t)syz_test},
+    {"syz_test$length8", 1000008, (syscall_t)syz_test},
+    {"syz_test$length9", 1000008, (syscall_t)syz_test},
+    {"syz_test$missing_resource", 1000008, (syscall_t)syz_test},
+    {"syz_test$opt0", 1000008, (syscall_t)syz_test},
+    {"syz_test$opt1", 1000008, (syscall_t)syz_test},
+    {"syz_test$opt2", 1000008, (syscall_t)syz_test},
+    {"syz_test$recur0", 1000008, (syscall_t)syz_test},
+    {"syz_test$recur1", 1000008, (syscall_t)syz_test},
+    {"syz_test$recur2", 1000008, (syscall_t)syz_test},
+    {"syz_test$regression0", 1000008, (syscall_t)syz_test},
+    {"syz_test$res0", 1000008, (syscall_t)syz_test},
+    {"syz_test$res1", 1000008, (syscall_t)syz_test},
+    {"syz_test$struct", 1000008, (syscall_t)syz_test},
+    {"syz_test$text_x86_16", 1000008, (syscall_t)syz_test},
+    {"syz_test$text_x86_32", 1000008, (syscall_t)syz_test},
+    {"syz_test$text_x86_64", 1000008, (syscall_t)syz_test},
+    {"syz_test$text_x86_real", 1000008, (syscall_t)syz_test},
+    {"syz_test$union0", 1000008, (syscall_t)syz_test},
+    {"syz_test$union1", 1000008, (syscall_t)syz_test},
+    {"syz_test$union2", 1000008, (syscall_t)syz_test},
+    {"syz_test$vma0", 1000008, (syscall_t)syz_test},
     {"tee", 315},
     {"tgkill", 270},
     {"time", 13},
@@ -1501,17 +1503,18 @@ call_t syscalls[] = {
 
 #if defined(__x86_64__) || 0
 #define GOARCH "amd64"
-#define SYZ_REVISION "c1ab3550b8fbe92662ee054df76419327b5b72a7"
-#define __NR_syz_emit_ethernet 1000000
-#define __NR_syz_extract_tcp_res 1000001
-#define __NR_syz_fuse_mount 1000002
-#define __NR_syz_fuseblk_mount 1000003
-#define __NR_syz_kvm_setup_cpu 1000004
-#define __NR_syz_open_dev 1000005
-#define __NR_syz_open_pts 1000006
-#define __NR_syz_test 1000007
+#define SYZ_REVISION "e0b88f3668f94ed85165e48654d7d16f2c34f8e9"
+#define __NR_syz_compare 1000000
+#define __NR_syz_emit_ethernet 1000001
+#define __NR_syz_extract_tcp_res 1000002
+#define __NR_syz_fuse_mount 1000003
+#define __NR_syz_fuseblk_mount 1000004
+#define __NR_syz_kvm_setup_cpu 1000005
+#define __NR_syz_open_dev 1000006
+#define __NR_syz_open_pts 1000007
+#define __NR_syz_test 1000008
 
-unsigned syscall_count = 1542;
+unsigned syscall_count = 1543;
 call_t syscalls[] = {
     {"accept", 43},
     {"accept$alg", 43},
@@ -2907,109 +2910,110 @@ call_t syscalls[] = {
     {"sysfs$3", 139},
     {"sysinfo", 99},
     {"syslog", 103},
-    {"syz_emit_ethernet", 1000000, (syscall_t)syz_emit_ethernet},
-    {"syz_extract_tcp_res", 1000001, (syscall_t)syz_extract_tcp_res},
-    {"syz_extract_tcp_res$synack", 1000001, (syscall_t)syz_extract_tcp_res},
-    {"syz_fuse_mount", 1000002, (syscall_t)syz_fuse_mount},
-    {"syz_fuseblk_mount", 1000003, (syscall_t)syz_fuseblk_mount},
-    {"syz_kvm_setup_cpu$arm64", 1000004, (syscall_t)syz_kvm_setup_cpu},
-    {"syz_kvm_setup_cpu$x86", 1000004, (syscall_t)syz_kvm_setup_cpu},
-    {"syz_open_dev$admmidi", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$adsp", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$amidi", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$audion", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$dmmidi", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$dri", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$dricontrol", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$drirender", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$dspn", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$evdev", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$floppy", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$ircomm", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$loop", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$mice", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$midi", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$mouse", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$random", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sg", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndctrl", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndhw", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndmidi", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndpcmc", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndpcmp", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndseq", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndtimer", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$tlk_device", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$tun", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$urandom", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$usb", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$usbmon", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$vcsa", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$vcsn", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_pts", 1000006, (syscall_t)syz_open_pts},
-    {"syz_test", 1000007, (syscall_t)syz_test},
-    {"syz_test$align0", 1000007, (syscall_t)syz_test},
-    {"syz_test$align1", 1000007, (syscall_t)syz_test},
-    {"syz_test$align2", 1000007, (syscall_t)syz_test},
-    {"syz_test$align3", 1000007, (syscall_t)syz_test},
-    {"syz_test$align4", 1000007, (syscall_t)syz_test},
-    {"syz_test$align5", 1000007, (syscall_t)syz_test},
-    {"syz_test$align6", 1000007, (syscall_t)syz_test},
-    {"syz_test$array0", 1000007, (syscall_t)syz_test},
-    {"syz_test$array1", 1000007, (syscall_t)syz_test},
-    {"syz_test$array2", 1000007, (syscall_t)syz_test},
-    {"syz_test$bf0", 1000007, (syscall_t)syz_test},
-    {"syz_test$bf1", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_encode", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_ipv4", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_ipv4_tcp", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_ipv4_udp", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_ipv6_icmp", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_ipv6_tcp", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_ipv6_udp", 1000007, (syscall_t)syz_test},
-    {"syz_test$end0", 1000007, (syscall_t)syz_test},
-    {"syz_test$end1", 1000007, (syscall_t)syz_test},
-    {"syz_test$int", 1000007, (syscall_t)syz_test},
-    {"syz_test$length0", 1000007, (syscall_t)syz_test},
-    {"syz_test$length1", 1000007, (syscall_t)syz_test},
-    {"syz_test$length10", 1000007, (syscall_t)syz_test},
-    {"syz_test$length11", 1000007, (syscall_t)syz_test},
-    {"syz_test$length12", 1000007, (syscall_t)syz_test},
-    {"syz_test$length13", 1000007, (syscall_t)syz_test},
-    {"syz_test$length14", 1000007, (syscall_t)syz_test},
-    {"syz_test$length15", 1000007, (syscall_t)syz_test},
-    {"syz_test$length16", 1000007, (syscall_t)syz_test},
-    {"syz_test$length17", 1000007, (syscall_t)syz_test},
-    {"syz_test$length18", 1000007, (syscall_t)syz_test},
-    {"syz_test$length19", 1000007, (syscall_t)syz_test},
-    {"syz_test$length2", 1000007, (syscall_t)syz_test},
-    {"syz_test$length20", 1000007, (syscall_t)syz_test},
-    {"syz_test$length3", 1000007, (syscall_t)syz_test},
-    {"syz_test$length4", 1000007, (syscall_t)syz_test},
-    {"syz_test$length5", 1000007, (syscall_t)syz_test},
-    {"syz_test$length6", 1000007, (syscall_t)syz_test},
-    {"syz_test$length7", 1000007, (syscall_t)syz_test},
-    {"syz_test$length8", 1000007, (syscall_t)syz_test},
-    {"syz_test$length9", 1000007, (syscall_t)syz_test},
-    {"syz_test$missing_resource", 1000007, (syscall_t)syz_test},
-    {"syz_test$opt0", 1000007, (syscall_t)syz_test},
-    {"syz_test$opt1", 1000007, (syscall_t)syz_test},
-    {"syz_test$opt2", 1000007, (syscall_t)syz_test},
-    {"syz_test$recur0", 1000007, (syscall_t)syz_test},
-    {"syz_test$recur1", 1000007, (syscall_t)syz_test},
-    {"syz_test$recur2", 1000007, (syscall_t)syz_test},
-    {"syz_test$regression0", 1000007, (syscall_t)syz_test},
-    {"syz_test$res0", 1000007, (syscall_t)syz_test},
-    {"syz_test$res1", 1000007, (syscall_t)syz_test},
-    {"syz_test$struct", 1000007, (syscall_t)syz_test},
-    {"syz_test$text_x86_16", 1000007, (syscall_t)syz_test},
-    {"syz_test$text_x86_32", 1000007, (syscall_t)syz_test},
-    {"syz_test$text_x86_64", 1000007, (syscall_t)syz_test},
-    {"syz_test$text_x86_real", 1000007, (syscall_t)syz_test},
-    {"syz_test$union0", 1000007, (syscall_t)syz_test},
-    {"syz_test$union1", 1000007, (syscall_t)syz_test},
-    {"syz_test$union2", 1000007, (syscall_t)syz_test},
-    {"syz_test$vma0", 1000007, (syscall_t)syz_test},
+    {"syz_compare", 1000000, (syscall_t)syz_compare},
+    {"syz_emit_ethernet", 1000001, (syscall_t)syz_emit_ethernet},
+    {"syz_extract_tcp_res", 1000002, (syscall_t)syz_extract_tcp_res},
+    {"syz_extract_tcp_res$synack", 1000002, (syscall_t)syz_extract_tcp_res},
+    {"syz_fuse_mount", 1000003, (syscall_t)syz_fuse_mount},
+    {"syz_fuseblk_mount", 1000004, (syscall_t)syz_fuseblk_mount},
+    {"syz_kvm_setup_cpu$arm64", 1000005, (syscall_t)syz_kvm_setup_cpu},
+    {"syz_kvm_setup_cpu$x86", 1000005, (syscall_t)syz_kvm_setup_cpu},
+    {"syz_open_dev$admmidi", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$adsp", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$amidi", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$audion", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$dmmidi", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$dri", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$dricontrol", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$drirender", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$dspn", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$evdev", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$floppy", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$ircomm", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$loop", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$mice", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$midi", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$mouse", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$random", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sg", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndctrl", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndhw", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndmidi", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndpcmc", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndpcmp", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndseq", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndtimer", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$tlk_device", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$tun", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$urandom", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$usb", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$usbmon", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$vcsa", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$vcsn", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_pts", 1000007, (syscall_t)syz_open_pts},
+    {"syz_test", 1000008, (syscall_t)syz_test},
+    {"syz_test$align0", 1000008, (syscall_t)syz_test},
+    {"syz_test$align1", 1000008, (syscall_t)syz_test},
+    {"syz_test$align2", 1000008, (syscall_t)syz_test},
+    {"syz_test$align3", 1000008, (syscall_t)syz_test},
+    {"syz_test$align4", 1000008, (syscall_t)syz_test},
+    {"syz_test$align5", 1000008, (syscall_t)syz_test},
+    {"syz_test$align6", 1000008, (syscall_t)syz_test},
+    {"syz_test$array0", 1000008, (syscall_t)syz_test},
+    {"syz_test$array1", 1000008, (syscall_t)syz_test},
+    {"syz_test$array2", 1000008, (syscall_t)syz_test},
+    {"syz_test$bf0", 1000008, (syscall_t)syz_test},
+    {"syz_test$bf1", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_encode", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_ipv4", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_ipv4_tcp", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_ipv4_udp", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_ipv6_icmp", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_ipv6_tcp", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_ipv6_udp", 1000008, (syscall_t)syz_test},
+    {"syz_test$end0", 1000008, (syscall_t)syz_test},
+    {"syz_test$end1", 1000008, (syscall_t)syz_test},
+    {"syz_test$int", 1000008, (syscall_t)syz_test},
+    {"syz_test$length0", 1000008, (syscall_t)syz_test},
+    {"syz_test$length1", 1000008, (syscall_t)syz_test},
+    {"syz_test$length10", 1000008, (syscall_t)syz_test},
+    {"syz_test$length11", 1000008, (syscall_t)syz_test},
+    {"syz_test$length12", 1000008, (syscall_t)syz_test},
+    {"syz_test$length13", 1000008, (syscall_t)syz_test},
+    {"syz_test$length14", 1000008, (syscall_t)syz_test},
+    {"syz_test$length15", 1000008, (syscall_t)syz_test},
+    {"syz_test$length16", 1000008, (syscall_t)syz_test},
+    {"syz_test$length17", 1000008, (syscall_t)syz_test},
+    {"syz_test$length18", 1000008, (syscall_t)syz_test},
+    {"syz_test$length19", 1000008, (syscall_t)syz_test},
+    {"syz_test$length2", 1000008, (syscall_t)syz_test},
+    {"syz_test$length20", 1000008, (syscall_t)syz_test},
+    {"syz_test$length3", 1000008, (syscall_t)syz_test},
+    {"syz_test$length4", 1000008, (syscall_t)syz_test},
+    {"syz_test$length5", 1000008, (syscall_t)syz_test},
+    {"syz_test$length6", 1000008, (syscall_t)syz_test},
+    {"syz_test$length7", 1000008, (syscall_t)syz_test},
+    {"syz_test$length8", 1000008, (syscall_t)syz_test},
+    {"syz_test$length9", 1000008, (syscall_t)syz_test},
+    {"syz_test$missing_resource", 1000008, (syscall_t)syz_test},
+    {"syz_test$opt0", 1000008, (syscall_t)syz_test},
+    {"syz_test$opt1", 1000008, (syscall_t)syz_test},
+    {"syz_test$opt2", 1000008, (syscall_t)syz_test},
+    {"syz_test$recur0", 1000008, (syscall_t)syz_test},
+    {"syz_test$recur1", 1000008, (syscall_t)syz_test},
+    {"syz_test$recur2", 1000008, (syscall_t)syz_test},
+    {"syz_test$regression0", 1000008, (syscall_t)syz_test},
+    {"syz_test$res0", 1000008, (syscall_t)syz_test},
+    {"syz_test$res1", 1000008, (syscall_t)syz_test},
+    {"syz_test$struct", 1000008, (syscall_t)syz_test},
+    {"syz_test$text_x86_16", 1000008, (syscall_t)syz_test},
+    {"syz_test$text_x86_32", 1000008, (syscall_t)syz_test},
+    {"syz_test$text_x86_64", 1000008, (syscall_t)syz_test},
+    {"syz_test$text_x86_real", 1000008, (syscall_t)syz_test},
+    {"syz_test$union0", 1000008, (syscall_t)syz_test},
+    {"syz_test$union1", 1000008, (syscall_t)syz_test},
+    {"syz_test$union2", 1000008, (syscall_t)syz_test},
+    {"syz_test$vma0", 1000008, (syscall_t)syz_test},
     {"tee", 276},
     {"tgkill", 234},
     {"time", 201},
@@ -3061,17 +3065,18 @@ call_t syscalls[] = {
 
 #if defined(__arm__) || 0
 #define GOARCH "arm"
-#define SYZ_REVISION "48d11406b51cb3dad2399863375cfc24d4892473"
-#define __NR_syz_emit_ethernet 1000000
-#define __NR_syz_extract_tcp_res 1000001
-#define __NR_syz_fuse_mount 1000002
-#define __NR_syz_fuseblk_mount 1000003
-#define __NR_syz_kvm_setup_cpu 1000004
-#define __NR_syz_open_dev 1000005
-#define __NR_syz_open_pts 1000006
-#define __NR_syz_test 1000007
+#define SYZ_REVISION "9c3490d5568465d31c477b6f400a5556c9dcd67e"
+#define __NR_syz_compare 1000000
+#define __NR_syz_emit_ethernet 1000001
+#define __NR_syz_extract_tcp_res 1000002
+#define __NR_syz_fuse_mount 1000003
+#define __NR_syz_fuseblk_mount 1000004
+#define __NR_syz_kvm_setup_cpu 1000005
+#define __NR_syz_open_dev 1000006
+#define __NR_syz_open_pts 1000007
+#define __NR_syz_test 1000008
 
-unsigned syscall_count = 1495;
+unsigned syscall_count = 1496;
 call_t syscalls[] = {
     {"accept", 9437469},
     {"accept$alg", 9437469},
@@ -4420,109 +4425,110 @@ call_t syscalls[] = {
     {"sysfs$3", 9437319},
     {"sysinfo", 9437300},
     {"syslog", 9437287},
-    {"syz_emit_ethernet", 1000000, (syscall_t)syz_emit_ethernet},
-    {"syz_extract_tcp_res", 1000001, (syscall_t)syz_extract_tcp_res},
-    {"syz_extract_tcp_res$synack", 1000001, (syscall_t)syz_extract_tcp_res},
-    {"syz_fuse_mount", 1000002, (syscall_t)syz_fuse_mount},
-    {"syz_fuseblk_mount", 1000003, (syscall_t)syz_fuseblk_mount},
-    {"syz_kvm_setup_cpu$arm64", 1000004, (syscall_t)syz_kvm_setup_cpu},
-    {"syz_kvm_setup_cpu$x86", 1000004, (syscall_t)syz_kvm_setup_cpu},
-    {"syz_open_dev$admmidi", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$adsp", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$amidi", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$audion", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$dmmidi", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$dri", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$dricontrol", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$drirender", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$dspn", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$evdev", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$floppy", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$ircomm", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$loop", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$mice", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$midi", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$mouse", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$random", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sg", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndctrl", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndhw", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndmidi", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndpcmc", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndpcmp", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndseq", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndtimer", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$tlk_device", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$tun", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$urandom", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$usb", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$usbmon", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$vcsa", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$vcsn", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_pts", 1000006, (syscall_t)syz_open_pts},
-    {"syz_test", 1000007, (syscall_t)syz_test},
-    {"syz_test$align0", 1000007, (syscall_t)syz_test},
-    {"syz_test$align1", 1000007, (syscall_t)syz_test},
-    {"syz_test$align2", 1000007, (syscall_t)syz_test},
-    {"syz_test$align3", 1000007, (syscall_t)syz_test},
-    {"syz_test$align4", 1000007, (syscall_t)syz_test},
-    {"syz_test$align5", 1000007, (syscall_t)syz_test},
-    {"syz_test$align6", 1000007, (syscall_t)syz_test},
-    {"syz_test$array0", 1000007, (syscall_t)syz_test},
-    {"syz_test$array1", 1000007, (syscall_t)syz_test},
-    {"syz_test$array2", 1000007, (syscall_t)syz_test},
-    {"syz_test$bf0", 1000007, (syscall_t)syz_test},
-    {"syz_test$bf1", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_encode", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_ipv4", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_ipv4_tcp", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_ipv4_udp", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_ipv6_icmp", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_ipv6_tcp", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_ipv6_udp", 1000007, (syscall_t)syz_test},
-    {"syz_test$end0", 1000007, (syscall_t)syz_test},
-    {"syz_test$end1", 1000007, (syscall_t)syz_test},
-    {"syz_test$int", 1000007, (syscall_t)syz_test},
-    {"syz_test$length0", 1000007, (syscall_t)syz_test},
-    {"syz_test$length1", 1000007, (syscall_t)syz_test},
-    {"syz_test$length10", 1000007, (syscall_t)syz_test},
-    {"syz_test$length11", 1000007, (syscall_t)syz_test},
-    {"syz_test$length12", 1000007, (syscall_t)syz_test},
-    {"syz_test$length13", 1000007, (syscall_t)syz_test},
-    {"syz_test$length14", 1000007, (syscall_t)syz_test},
-    {"syz_test$length15", 1000007, (syscall_t)syz_test},
-    {"syz_test$length16", 1000007, (syscall_t)syz_test},
-    {"syz_test$length17", 1000007, (syscall_t)syz_test},
-    {"syz_test$length18", 1000007, (syscall_t)syz_test},
-    {"syz_test$length19", 1000007, (syscall_t)syz_test},
-    {"syz_test$length2", 1000007, (syscall_t)syz_test},
-    {"syz_test$length20", 1000007, (syscall_t)syz_test},
-    {"syz_test$length3", 1000007, (syscall_t)syz_test},
-    {"syz_test$length4", 1000007, (syscall_t)syz_test},
-    {"syz_test$length5", 1000007, (syscall_t)syz_test},
-    {"syz_test$length6", 1000007, (syscall_t)syz_test},
-    {"syz_test$length7", 1000007, (syscall_t)syz_test},
-    {"syz_test$length8", 1000007, (syscall_t)syz_test},
-    {"syz_test$length9", 1000007, (syscall_t)syz_test},
-    {"syz_test$missing_resource", 1000007, (syscall_t)syz_test},
-    {"syz_test$opt0", 1000007, (syscall_t)syz_test},
-    {"syz_test$opt1", 1000007, (syscall_t)syz_test},
-    {"syz_test$opt2", 1000007, (syscall_t)syz_test},
-    {"syz_test$recur0", 1000007, (syscall_t)syz_test},
-    {"syz_test$recur1", 1000007, (syscall_t)syz_test},
-    {"syz_test$recur2", 1000007, (syscall_t)syz_test},
-    {"syz_test$regression0", 1000007, (syscall_t)syz_test},
-    {"syz_test$res0", 1000007, (syscall_t)syz_test},
-    {"syz_test$res1", 1000007, (syscall_t)syz_test},
-    {"syz_test$struct", 1000007, (syscall_t)syz_test},
-    {"syz_test$text_x86_16", 1000007, (syscall_t)syz_test},
-    {"syz_test$text_x86_32", 1000007, (syscall_t)syz_test},
-    {"syz_test$text_x86_64", 1000007, (syscall_t)syz_test},
-    {"syz_test$text_x86_real", 1000007, (syscall_t)syz_test},
-    {"syz_test$union0", 1000007, (syscall_t)syz_test},
-    {"syz_test$union1", 1000007, (syscall_t)syz_test},
-    {"syz_test$union2", 1000007, (syscall_t)syz_test},
-    {"syz_test$vma0", 1000007, (syscall_t)syz_test},
+    {"syz_compare", 1000000, (syscall_t)syz_compare},
+    {"syz_emit_ethernet", 1000001, (syscall_t)syz_emit_ethernet},
+    {"syz_extract_tcp_res", 1000002, (syscall_t)syz_extract_tcp_res},
+    {"syz_extract_tcp_res$synack", 1000002, (syscall_t)syz_extract_tcp_res},
+    {"syz_fuse_mount", 1000003, (syscall_t)syz_fuse_mount},
+    {"syz_fuseblk_mount", 1000004, (syscall_t)syz_fuseblk_mount},
+    {"syz_kvm_setup_cpu$arm64", 1000005, (syscall_t)syz_kvm_setup_cpu},
+    {"syz_kvm_setup_cpu$x86", 1000005, (syscall_t)syz_kvm_setup_cpu},
+    {"syz_open_dev$admmidi", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$adsp", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$amidi", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$audion", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$dmmidi", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$dri", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$dricontrol", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$drirender", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$dspn", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$evdev", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$floppy", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$ircomm", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$loop", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$mice", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$midi", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$mouse", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$random", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sg", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndctrl", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndhw", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndmidi", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndpcmc", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndpcmp", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndseq", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndtimer", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$tlk_device", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$tun", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$urandom", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$usb", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$usbmon", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$vcsa", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$vcsn", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_pts", 1000007, (syscall_t)syz_open_pts},
+    {"syz_test", 1000008, (syscall_t)syz_test},
+    {"syz_test$align0", 1000008, (syscall_t)syz_test},
+    {"syz_test$align1", 1000008, (syscall_t)syz_test},
+    {"syz_test$align2", 1000008, (syscall_t)syz_test},
+    {"syz_test$align3", 1000008, (syscall_t)syz_test},
+    {"syz_test$align4", 1000008, (syscall_t)syz_test},
+    {"syz_test$align5", 1000008, (syscall_t)syz_test},
+    {"syz_test$align6", 1000008, (syscall_t)syz_test},
+    {"syz_test$array0", 1000008, (syscall_t)syz_test},
+    {"syz_test$array1", 1000008, (syscall_t)syz_test},
+    {"syz_test$array2", 1000008, (syscall_t)syz_test},
+    {"syz_test$bf0", 1000008, (syscall_t)syz_test},
+    {"syz_test$bf1", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_encode", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_ipv4", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_ipv4_tcp", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_ipv4_udp", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_ipv6_icmp", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_ipv6_tcp", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_ipv6_udp", 1000008, (syscall_t)syz_test},
+    {"syz_test$end0", 1000008, (syscall_t)syz_test},
+    {"syz_test$end1", 1000008, (syscall_t)syz_test},
+    {"syz_test$int", 1000008, (syscall_t)syz_test},
+    {"syz_test$length0", 1000008, (syscall_t)syz_test},
+    {"syz_test$length1", 1000008, (syscall_t)syz_test},
+    {"syz_test$length10", 1000008, (syscall_t)syz_test},
+    {"syz_test$length11", 1000008, (syscall_t)syz_test},
+    {"syz_test$length12", 1000008, (syscall_t)syz_test},
+    {"syz_test$length13", 1000008, (syscall_t)syz_test},
+    {"syz_test$length14", 1000008, (syscall_t)syz_test},
+    {"syz_test$length15", 1000008, (syscall_t)syz_test},
+    {"syz_test$length16", 1000008, (syscall_t)syz_test},
+    {"syz_test$length17", 1000008, (syscall_t)syz_test},
+    {"syz_test$length18", 1000008, (syscall_t)syz_test},
+    {"syz_test$length19", 1000008, (syscall_t)syz_test},
+    {"syz_test$length2", 1000008, (syscall_t)syz_test},
+    {"syz_test$length20", 1000008, (syscall_t)syz_test},
+    {"syz_test$length3", 1000008, (syscall_t)syz_test},
+    {"syz_test$length4", 1000008, (syscall_t)syz_test},
+    {"syz_test$length5", 1000008, (syscall_t)syz_test},
+    {"syz_test$length6", 1000008, (syscall_t)syz_test},
+    {"syz_test$length7", 1000008, (syscall_t)syz_test},
+    {"syz_test$length8", 1000008, (syscall_t)syz_test},
+    {"syz_test$length9", 1000008, (syscall_t)syz_test},
+    {"syz_test$missing_resource", 1000008, (syscall_t)syz_test},
+    {"syz_test$opt0", 1000008, (syscall_t)syz_test},
+    {"syz_test$opt1", 1000008, (syscall_t)syz_test},
+    {"syz_test$opt2", 1000008, (syscall_t)syz_test},
+    {"syz_test$recur0", 1000008, (syscall_t)syz_test},
+    {"syz_test$recur1", 1000008, (syscall_t)syz_test},
+    {"syz_test$recur2", 1000008, (syscall_t)syz_test},
+    {"syz_test$regression0", 1000008, (syscall_t)syz_test},
+    {"syz_test$res0", 1000008, (syscall_t)syz_test},
+    {"syz_test$res1", 1000008, (syscall_t)syz_test},
+    {"syz_test$struct", 1000008, (syscall_t)syz_test},
+    {"syz_test$text_x86_16", 1000008, (syscall_t)syz_test},
+    {"syz_test$text_x86_32", 1000008, (syscall_t)syz_test},
+    {"syz_test$text_x86_64", 1000008, (syscall_t)syz_test},
+    {"syz_test$text_x86_real", 1000008, (syscall_t)syz_test},
+    {"syz_test$union0", 1000008, (syscall_t)syz_test},
+    {"syz_test$union1", 1000008, (syscall_t)syz_test},
+    {"syz_test$union2", 1000008, (syscall_t)syz_test},
+    {"syz_test$vma0", 1000008, (syscall_t)syz_test},
     {"tee", 9437526},
     {"tgkill", 9437452},
     {"time", 9437197},
@@ -4574,17 +4580,18 @@ call_t syscalls[] = {
 
 #if defined(__aarch64__) || 0
 #define GOARCH "arm64"
-#define SYZ_REVISION "65746b600515d1f1026ed56961d3450e70d65878"
-#define __NR_syz_emit_ethernet 1000000
-#define __NR_syz_extract_tcp_res 1000001
-#define __NR_syz_fuse_mount 1000002
-#define __NR_syz_fuseblk_mount 1000003
-#define __NR_syz_kvm_setup_cpu 1000004
-#define __NR_syz_open_dev 1000005
-#define __NR_syz_open_pts 1000006
-#define __NR_syz_test 1000007
+#define SYZ_REVISION "689f1df9d9b4df2611f2987fbd4e7c62bbc4c589"
+#define __NR_syz_compare 1000000
+#define __NR_syz_emit_ethernet 1000001
+#define __NR_syz_extract_tcp_res 1000002
+#define __NR_syz_fuse_mount 1000003
+#define __NR_syz_fuseblk_mount 1000004
+#define __NR_syz_kvm_setup_cpu 1000005
+#define __NR_syz_open_dev 1000006
+#define __NR_syz_open_pts 1000007
+#define __NR_syz_test 1000008
 
-unsigned syscall_count = 1470;
+unsigned syscall_count = 1471;
 call_t syscalls[] = {
     {"accept", 202},
     {"accept$alg", 202},
@@ -5914,109 +5921,110 @@ call_t syscalls[] = {
     {"syncfs", 267},
     {"sysinfo", 179},
     {"syslog", 116},
-    {"syz_emit_ethernet", 1000000, (syscall_t)syz_emit_ethernet},
-    {"syz_extract_tcp_res", 1000001, (syscall_t)syz_extract_tcp_res},
-    {"syz_extract_tcp_res$synack", 1000001, (syscall_t)syz_extract_tcp_res},
-    {"syz_fuse_mount", 1000002, (syscall_t)syz_fuse_mount},
-    {"syz_fuseblk_mount", 1000003, (syscall_t)syz_fuseblk_mount},
-    {"syz_kvm_setup_cpu$arm64", 1000004, (syscall_t)syz_kvm_setup_cpu},
-    {"syz_kvm_setup_cpu$x86", 1000004, (syscall_t)syz_kvm_setup_cpu},
-    {"syz_open_dev$admmidi", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$adsp", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$amidi", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$audion", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$dmmidi", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$dri", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$dricontrol", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$drirender", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$dspn", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$evdev", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$floppy", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$ircomm", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$loop", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$mice", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$midi", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$mouse", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$random", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sg", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndctrl", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndhw", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndmidi", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndpcmc", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndpcmp", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndseq", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndtimer", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$tlk_device", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$tun", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$urandom", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$usb", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$usbmon", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$vcsa", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$vcsn", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_pts", 1000006, (syscall_t)syz_open_pts},
-    {"syz_test", 1000007, (syscall_t)syz_test},
-    {"syz_test$align0", 1000007, (syscall_t)syz_test},
-    {"syz_test$align1", 1000007, (syscall_t)syz_test},
-    {"syz_test$align2", 1000007, (syscall_t)syz_test},
-    {"syz_test$align3", 1000007, (syscall_t)syz_test},
-    {"syz_test$align4", 1000007, (syscall_t)syz_test},
-    {"syz_test$align5", 1000007, (syscall_t)syz_test},
-    {"syz_test$align6", 1000007, (syscall_t)syz_test},
-    {"syz_test$array0", 1000007, (syscall_t)syz_test},
-    {"syz_test$array1", 1000007, (syscall_t)syz_test},
-    {"syz_test$array2", 1000007, (syscall_t)syz_test},
-    {"syz_test$bf0", 1000007, (syscall_t)syz_test},
-    {"syz_test$bf1", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_encode", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_ipv4", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_ipv4_tcp", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_ipv4_udp", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_ipv6_icmp", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_ipv6_tcp", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_ipv6_udp", 1000007, (syscall_t)syz_test},
-    {"syz_test$end0", 1000007, (syscall_t)syz_test},
-    {"syz_test$end1", 1000007, (syscall_t)syz_test},
-    {"syz_test$int", 1000007, (syscall_t)syz_test},
-    {"syz_test$length0", 1000007, (syscall_t)syz_test},
-    {"syz_test$length1", 1000007, (syscall_t)syz_test},
-    {"syz_test$length10", 1000007, (syscall_t)syz_test},
-    {"syz_test$length11", 1000007, (syscall_t)syz_test},
-    {"syz_test$length12", 1000007, (syscall_t)syz_test},
-    {"syz_test$length13", 1000007, (syscall_t)syz_test},
-    {"syz_test$length14", 1000007, (syscall_t)syz_test},
-    {"syz_test$length15", 1000007, (syscall_t)syz_test},
-    {"syz_test$length16", 1000007, (syscall_t)syz_test},
-    {"syz_test$length17", 1000007, (syscall_t)syz_test},
-    {"syz_test$length18", 1000007, (syscall_t)syz_test},
-    {"syz_test$length19", 1000007, (syscall_t)syz_test},
-    {"syz_test$length2", 1000007, (syscall_t)syz_test},
-    {"syz_test$length20", 1000007, (syscall_t)syz_test},
-    {"syz_test$length3", 1000007, (syscall_t)syz_test},
-    {"syz_test$length4", 1000007, (syscall_t)syz_test},
-    {"syz_test$length5", 1000007, (syscall_t)syz_test},
-    {"syz_test$length6", 1000007, (syscall_t)syz_test},
-    {"syz_test$length7", 1000007, (syscall_t)syz_test},
-    {"syz_test$length8", 1000007, (syscall_t)syz_test},
-    {"syz_test$length9", 1000007, (syscall_t)syz_test},
-    {"syz_test$missing_resource", 1000007, (syscall_t)syz_test},
-    {"syz_test$opt0", 1000007, (syscall_t)syz_test},
-    {"syz_test$opt1", 1000007, (syscall_t)syz_test},
-    {"syz_test$opt2", 1000007, (syscall_t)syz_test},
-    {"syz_test$recur0", 1000007, (syscall_t)syz_test},
-    {"syz_test$recur1", 1000007, (syscall_t)syz_test},
-    {"syz_test$recur2", 1000007, (syscall_t)syz_test},
-    {"syz_test$regression0", 1000007, (syscall_t)syz_test},
-    {"syz_test$res0", 1000007, (syscall_t)syz_test},
-    {"syz_test$res1", 1000007, (syscall_t)syz_test},
-    {"syz_test$struct", 1000007, (syscall_t)syz_test},
-    {"syz_test$text_x86_16", 1000007, (syscall_t)syz_test},
-    {"syz_test$text_x86_32", 1000007, (syscall_t)syz_test},
-    {"syz_test$text_x86_64", 1000007, (syscall_t)syz_test},
-    {"syz_test$text_x86_real", 1000007, (syscall_t)syz_test},
-    {"syz_test$union0", 1000007, (syscall_t)syz_test},
-    {"syz_test$union1", 1000007, (syscall_t)syz_test},
-    {"syz_test$union2", 1000007, (syscall_t)syz_test},
-    {"syz_test$vma0", 1000007, (syscall_t)syz_test},
+    {"syz_compare", 1000000, (syscall_t)syz_compare},
+    {"syz_emit_ethernet", 1000001, (syscall_t)syz_emit_ethernet},
+    {"syz_extract_tcp_res", 1000002, (syscall_t)syz_extract_tcp_res},
+    {"syz_extract_tcp_res$synack", 1000002, (syscall_t)syz_extract_tcp_res},
+    {"syz_fuse_mount", 1000003, (syscall_t)syz_fuse_mount},
+    {"syz_fuseblk_mount", 1000004, (syscall_t)syz_fuseblk_mount},
+    {"syz_kvm_setup_cpu$arm64", 1000005, (syscall_t)syz_kvm_setup_cpu},
+    {"syz_kvm_setup_cpu$x86", 1000005, (syscall_t)syz_kvm_setup_cpu},
+    {"syz_open_dev$admmidi", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$adsp", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$amidi", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$audion", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$dmmidi", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$dri", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$dricontrol", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$drirender", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$dspn", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$evdev", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$floppy", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$ircomm", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$loop", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$mice", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$midi", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$mouse", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$random", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sg", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndctrl", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndhw", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndmidi", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndpcmc", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndpcmp", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndseq", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndtimer", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$tlk_device", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$tun", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$urandom", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$usb", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$usbmon", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$vcsa", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$vcsn", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_pts", 1000007, (syscall_t)syz_open_pts},
+    {"syz_test", 1000008, (syscall_t)syz_test},
+    {"syz_test$align0", 1000008, (syscall_t)syz_test},
+    {"syz_test$align1", 1000008, (syscall_t)syz_test},
+    {"syz_test$align2", 1000008, (syscall_t)syz_test},
+    {"syz_test$align3", 1000008, (syscall_t)syz_test},
+    {"syz_test$align4", 1000008, (syscall_t)syz_test},
+    {"syz_test$align5", 1000008, (syscall_t)syz_test},
+    {"syz_test$align6", 1000008, (syscall_t)syz_test},
+    {"syz_test$array0", 1000008, (syscall_t)syz_test},
+    {"syz_test$array1", 1000008, (syscall_t)syz_test},
+    {"syz_test$array2", 1000008, (syscall_t)syz_test},
+    {"syz_test$bf0", 1000008, (syscall_t)syz_test},
+    {"syz_test$bf1", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_encode", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_ipv4", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_ipv4_tcp", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_ipv4_udp", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_ipv6_icmp", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_ipv6_tcp", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_ipv6_udp", 1000008, (syscall_t)syz_test},
+    {"syz_test$end0", 1000008, (syscall_t)syz_test},
+    {"syz_test$end1", 1000008, (syscall_t)syz_test},
+    {"syz_test$int", 1000008, (syscall_t)syz_test},
+    {"syz_test$length0", 1000008, (syscall_t)syz_test},
+    {"syz_test$length1", 1000008, (syscall_t)syz_test},
+    {"syz_test$length10", 1000008, (syscall_t)syz_test},
+    {"syz_test$length11", 1000008, (syscall_t)syz_test},
+    {"syz_test$length12", 1000008, (syscall_t)syz_test},
+    {"syz_test$length13", 1000008, (syscall_t)syz_test},
+    {"syz_test$length14", 1000008, (syscall_t)syz_test},
+    {"syz_test$length15", 1000008, (syscall_t)syz_test},
+    {"syz_test$length16", 1000008, (syscall_t)syz_test},
+    {"syz_test$length17", 1000008, (syscall_t)syz_test},
+    {"syz_test$length18", 1000008, (syscall_t)syz_test},
+    {"syz_test$length19", 1000008, (syscall_t)syz_test},
+    {"syz_test$length2", 1000008, (syscall_t)syz_test},
+    {"syz_test$length20", 1000008, (syscall_t)syz_test},
+    {"syz_test$length3", 1000008, (syscall_t)syz_test},
+    {"syz_test$length4", 1000008, (syscall_t)syz_test},
+    {"syz_test$length5", 1000008, (syscall_t)syz_test},
+    {"syz_test$length6", 1000008, (syscall_t)syz_test},
+    {"syz_test$length7", 1000008, (syscall_t)syz_test},
+    {"syz_test$length8", 1000008, (syscall_t)syz_test},
+    {"syz_test$length9", 1000008, (syscall_t)syz_test},
+    {"syz_test$missing_resource", 1000008, (syscall_t)syz_test},
+    {"syz_test$opt0", 1000008, (syscall_t)syz_test},
+    {"syz_test$opt1", 1000008, (syscall_t)syz_test},
+    {"syz_test$opt2", 1000008, (syscall_t)syz_test},
+    {"syz_test$recur0", 1000008, (syscall_t)syz_test},
+    {"syz_test$recur1", 1000008, (syscall_t)syz_test},
+    {"syz_test$recur2", 1000008, (syscall_t)syz_test},
+    {"syz_test$regression0", 1000008, (syscall_t)syz_test},
+    {"syz_test$res0", 1000008, (syscall_t)syz_test},
+    {"syz_test$res1", 1000008, (syscall_t)syz_test},
+    {"syz_test$struct", 1000008, (syscall_t)syz_test},
+    {"syz_test$text_x86_16", 1000008, (syscall_t)syz_test},
+    {"syz_test$text_x86_32", 1000008, (syscall_t)syz_test},
+    {"syz_test$text_x86_64", 1000008, (syscall_t)syz_test},
+    {"syz_test$text_x86_real", 1000008, (syscall_t)syz_test},
+    {"syz_test$union0", 1000008, (syscall_t)syz_test},
+    {"syz_test$union1", 1000008, (syscall_t)syz_test},
+    {"syz_test$union2", 1000008, (syscall_t)syz_test},
+    {"syz_test$vma0", 1000008, (syscall_t)syz_test},
     {"tee", 77},
     {"tgkill", 131},
     {"timer_create", 107},
@@ -6062,17 +6070,18 @@ call_t syscalls[] = {
 
 #if defined(__ppc64__) || defined(__PPC64__) || defined(__powerpc64__) || 0
 #define GOARCH "ppc64le"
-#define SYZ_REVISION "5e9d29319478130f5d8b9238e869d979870e5cb3"
-#define __NR_syz_emit_ethernet 1000000
-#define __NR_syz_extract_tcp_res 1000001
-#define __NR_syz_fuse_mount 1000002
-#define __NR_syz_fuseblk_mount 1000003
-#define __NR_syz_kvm_setup_cpu 1000004
-#define __NR_syz_open_dev 1000005
-#define __NR_syz_open_pts 1000006
-#define __NR_syz_test 1000007
+#define SYZ_REVISION "62c5fa07a225b35c0c96d814e4267c73290a87d1"
+#define __NR_syz_compare 1000000
+#define __NR_syz_emit_ethernet 1000001
+#define __NR_syz_extract_tcp_res 1000002
+#define __NR_syz_fuse_mount 1000003
+#define __NR_syz_fuseblk_mount 1000004
+#define __NR_syz_kvm_setup_cpu 1000005
+#define __NR_syz_open_dev 1000006
+#define __NR_syz_open_pts 1000007
+#define __NR_syz_test 1000008
 
-unsigned syscall_count = 1451;
+unsigned syscall_count = 1452;
 call_t syscalls[] = {
     {"accept", 330},
     {"accept$alg", 330},
@@ -7377,109 +7386,110 @@ call_t syscalls[] = {
     {"sysfs$3", 135},
     {"sysinfo", 116},
     {"syslog", 103},
-    {"syz_emit_ethernet", 1000000, (syscall_t)syz_emit_ethernet},
-    {"syz_extract_tcp_res", 1000001, (syscall_t)syz_extract_tcp_res},
-    {"syz_extract_tcp_res$synack", 1000001, (syscall_t)syz_extract_tcp_res},
-    {"syz_fuse_mount", 1000002, (syscall_t)syz_fuse_mount},
-    {"syz_fuseblk_mount", 1000003, (syscall_t)syz_fuseblk_mount},
-    {"syz_kvm_setup_cpu$arm64", 1000004, (syscall_t)syz_kvm_setup_cpu},
-    {"syz_kvm_setup_cpu$x86", 1000004, (syscall_t)syz_kvm_setup_cpu},
-    {"syz_open_dev$admmidi", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$adsp", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$amidi", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$audion", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$dmmidi", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$dri", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$dricontrol", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$drirender", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$dspn", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$evdev", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$floppy", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$ircomm", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$loop", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$mice", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$midi", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$mouse", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$random", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sg", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndctrl", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndhw", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndmidi", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndpcmc", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndpcmp", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndseq", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$sndtimer", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$tlk_device", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$tun", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$urandom", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$usb", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$usbmon", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$vcsa", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_dev$vcsn", 1000005, (syscall_t)syz_open_dev},
-    {"syz_open_pts", 1000006, (syscall_t)syz_open_pts},
-    {"syz_test", 1000007, (syscall_t)syz_test},
-    {"syz_test$align0", 1000007, (syscall_t)syz_test},
-    {"syz_test$align1", 1000007, (syscall_t)syz_test},
-    {"syz_test$align2", 1000007, (syscall_t)syz_test},
-    {"syz_test$align3", 1000007, (syscall_t)syz_test},
-    {"syz_test$align4", 1000007, (syscall_t)syz_test},
-    {"syz_test$align5", 1000007, (syscall_t)syz_test},
-    {"syz_test$align6", 1000007, (syscall_t)syz_test},
-    {"syz_test$array0", 1000007, (syscall_t)syz_test},
-    {"syz_test$array1", 1000007, (syscall_t)syz_test},
-    {"syz_test$array2", 1000007, (syscall_t)syz_test},
-    {"syz_test$bf0", 1000007, (syscall_t)syz_test},
-    {"syz_test$bf1", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_encode", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_ipv4", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_ipv4_tcp", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_ipv4_udp", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_ipv6_icmp", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_ipv6_tcp", 1000007, (syscall_t)syz_test},
-    {"syz_test$csum_ipv6_udp", 1000007, (syscall_t)syz_test},
-    {"syz_test$end0", 1000007, (syscall_t)syz_test},
-    {"syz_test$end1", 1000007, (syscall_t)syz_test},
-    {"syz_test$int", 1000007, (syscall_t)syz_test},
-    {"syz_test$length0", 1000007, (syscall_t)syz_test},
-    {"syz_test$length1", 1000007, (syscall_t)syz_test},
-    {"syz_test$length10", 1000007, (syscall_t)syz_test},
-    {"syz_test$length11", 1000007, (syscall_t)syz_test},
-    {"syz_test$length12", 1000007, (syscall_t)syz_test},
-    {"syz_test$length13", 1000007, (syscall_t)syz_test},
-    {"syz_test$length14", 1000007, (syscall_t)syz_test},
-    {"syz_test$length15", 1000007, (syscall_t)syz_test},
-    {"syz_test$length16", 1000007, (syscall_t)syz_test},
-    {"syz_test$length17", 1000007, (syscall_t)syz_test},
-    {"syz_test$length18", 1000007, (syscall_t)syz_test},
-    {"syz_test$length19", 1000007, (syscall_t)syz_test},
-    {"syz_test$length2", 1000007, (syscall_t)syz_test},
-    {"syz_test$length20", 1000007, (syscall_t)syz_test},
-    {"syz_test$length3", 1000007, (syscall_t)syz_test},
-    {"syz_test$length4", 1000007, (syscall_t)syz_test},
-    {"syz_test$length5", 1000007, (syscall_t)syz_test},
-    {"syz_test$length6", 1000007, (syscall_t)syz_test},
-    {"syz_test$length7", 1000007, (syscall_t)syz_test},
-    {"syz_test$length8", 1000007, (syscall_t)syz_test},
-    {"syz_test$length9", 1000007, (syscall_t)syz_test},
-    {"syz_test$missing_resource", 1000007, (syscall_t)syz_test},
-    {"syz_test$opt0", 1000007, (syscall_t)syz_test},
-    {"syz_test$opt1", 1000007, (syscall_t)syz_test},
-    {"syz_test$opt2", 1000007, (syscall_t)syz_test},
-    {"syz_test$recur0", 1000007, (syscall_t)syz_test},
-    {"syz_test$recur1", 1000007, (syscall_t)syz_test},
-    {"syz_test$recur2", 1000007, (syscall_t)syz_test},
-    {"syz_test$regression0", 1000007, (syscall_t)syz_test},
-    {"syz_test$res0", 1000007, (syscall_t)syz_test},
-    {"syz_test$res1", 1000007, (syscall_t)syz_test},
-    {"syz_test$struct", 1000007, (syscall_t)syz_test},
-    {"syz_test$text_x86_16", 1000007, (syscall_t)syz_test},
-    {"syz_test$text_x86_32", 1000007, (syscall_t)syz_test},
-    {"syz_test$text_x86_64", 1000007, (syscall_t)syz_test},
-    {"syz_test$text_x86_real", 1000007, (syscall_t)syz_test},
-    {"syz_test$union0", 1000007, (syscall_t)syz_test},
-    {"syz_test$union1", 1000007, (syscall_t)syz_test},
-    {"syz_test$union2", 1000007, (syscall_t)syz_test},
-    {"syz_test$vma0", 1000007, (syscall_t)syz_test},
+    {"syz_compare", 1000000, (syscall_t)syz_compare},
+    {"syz_emit_ethernet", 1000001, (syscall_t)syz_emit_ethernet},
+    {"syz_extract_tcp_res", 1000002, (syscall_t)syz_extract_tcp_res},
+    {"syz_extract_tcp_res$synack", 1000002, (syscall_t)syz_extract_tcp_res},
+    {"syz_fuse_mount", 1000003, (syscall_t)syz_fuse_mount},
+    {"syz_fuseblk_mount", 1000004, (syscall_t)syz_fuseblk_mount},
+    {"syz_kvm_setup_cpu$arm64", 1000005, (syscall_t)syz_kvm_setup_cpu},
+    {"syz_kvm_setup_cpu$x86", 1000005, (syscall_t)syz_kvm_setup_cpu},
+    {"syz_open_dev$admmidi", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$adsp", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$amidi", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$audion", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$dmmidi", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$dri", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$dricontrol", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$drirender", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$dspn", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$evdev", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$floppy", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$ircomm", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$loop", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$mice", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$midi", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$mouse", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$random", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sg", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndctrl", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndhw", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndmidi", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndpcmc", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndpcmp", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndseq", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$sndtimer", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$tlk_device", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$tun", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$urandom", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$usb", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$usbmon", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$vcsa", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_dev$vcsn", 1000006, (syscall_t)syz_open_dev},
+    {"syz_open_pts", 1000007, (syscall_t)syz_open_pts},
+    {"syz_test", 1000008, (syscall_t)syz_test},
+    {"syz_test$align0", 1000008, (syscall_t)syz_test},
+    {"syz_test$align1", 1000008, (syscall_t)syz_test},
+    {"syz_test$align2", 1000008, (syscall_t)syz_test},
+    {"syz_test$align3", 1000008, (syscall_t)syz_test},
+    {"syz_test$align4", 1000008, (syscall_t)syz_test},
+    {"syz_test$align5", 1000008, (syscall_t)syz_test},
+    {"syz_test$align6", 1000008, (syscall_t)syz_test},
+    {"syz_test$array0", 1000008, (syscall_t)syz_test},
+    {"syz_test$array1", 1000008, (syscall_t)syz_test},
+    {"syz_test$array2", 1000008, (syscall_t)syz_test},
+    {"syz_test$bf0", 1000008, (syscall_t)syz_test},
+    {"syz_test$bf1", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_encode", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_ipv4", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_ipv4_tcp", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_ipv4_udp", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_ipv6_icmp", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_ipv6_tcp", 1000008, (syscall_t)syz_test},
+    {"syz_test$csum_ipv6_udp", 1000008, (syscall_t)syz_test},
+    {"syz_test$end0", 1000008, (syscall_t)syz_test},
+    {"syz_test$end1", 1000008, (syscall_t)syz_test},
+    {"syz_test$int", 1000008, (syscall_t)syz_test},
+    {"syz_test$length0", 1000008, (syscall_t)syz_test},
+    {"syz_test$length1", 1000008, (syscall_t)syz_test},
+    {"syz_test$length10", 1000008, (syscall_t)syz_test},
+    {"syz_test$length11", 1000008, (syscall_t)syz_test},
+    {"syz_test$length12", 1000008, (syscall_t)syz_test},
+    {"syz_test$length13", 1000008, (syscall_t)syz_test},
+    {"syz_test$length14", 1000008, (syscall_t)syz_test},
+    {"syz_test$length15", 1000008, (syscall_t)syz_test},
+    {"syz_test$length16", 1000008, (syscall_t)syz_test},
+    {"syz_test$length17", 1000008, (syscall_t)syz_test},
+    {"syz_test$length18", 1000008, (syscall_t)syz_test},
+    {"syz_test$length19", 1000008, (syscall_t)syz_test},
+    {"syz_test$length2", 1000008, (syscall_t)syz_test},
+    {"syz_test$length20", 1000008, (syscall_t)syz_test},
+    {"syz_test$length3", 1000008, (syscall_t)syz_test},
+    {"syz_test$length4", 1000008, (syscall_t)syz_test},
+    {"syz_test$length5", 1000008, (syscall_t)syz_test},
+    {"syz_test$length6", 1000008, (syscall_t)syz_test},
+    {"syz_test$length7", 1000008, (syscall_t)syz_test},
+    {"syz_test$length8", 1000008, (syscall_t)syz_test},
+    {"syz_test$length9", 1000008, (syscall_t)syz_test},
+    {"syz_test$missing_resource", 1000008, (syscall_t)syz_test},
+    {"syz_test$opt0", 1000008, (syscall_t)syz_test},
+    {"syz_test$opt1", 1000008, (syscall_t)syz_test},
+    {"syz_test$opt2", 1000008, (syscall_t)syz_test},
+    {"syz_test$recur0", 1000008, (syscall_t)syz_test},
+    {"syz_test$recur1", 1000008, (syscall_t)syz_test},
+    {"syz_test$recur2", 1000008, (syscall_t)syz_test},
+    {"syz_test$regression0", 1000008, (syscall_t)syz_test},
+    {"syz_test$res0", 1000008, (syscall_t)syz_test},
+    {"syz_test$res1", 1000008, (syscall_t)syz_test},
+    {"syz_test$struct", 1000008, (syscall_t)syz_test},
+    {"syz_test$text_x86_16", 1000008, (syscall_t)syz_test},
+    {"syz_test$text_x86_32", 1000008, (syscall_t)syz_test},
+    {"syz_test$text_x86_64", 1000008, (syscall_t)syz_test},
+    {"syz_test$text_x86_real", 1000008, (syscall_t)syz_test},
+    {"syz_test$union0", 1000008, (syscall_t)syz_test},
+    {"syz_test$union1", 1000008, (syscall_t)syz_test},
+    {"syz_test$union2", 1000008, (syscall_t)syz_test},
+    {"syz_test$vma0", 1000008, (syscall_t)syz_test},
     {"tee", 284},
     {"tgkill", 250},
     {"time", 13},
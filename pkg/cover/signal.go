@@ -0,0 +1,93 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package cover
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SignalFunc derives feedback signal for a single call from the raw coverage trace
+// of the call (basic block PCs in execution order, not deduplicated).
+// call is syscall ID of the call (prog.Syscall.ID), errno is the call errno (0 on success).
+// The returned signal is sorted and does not contain duplicates.
+type SignalFunc func(call, errno int, trace []uint32) []uint32
+
+// DefaultSignal means that signal is computed by executor itself
+// (code edges computed as xor of two subsequent hashed basic block PCs).
+const DefaultSignal = "executor"
+
+// SignalFuncs contains all supported Go-side signal functions.
+var SignalFuncs = map[string]SignalFunc{
+	"edge":      EdgeSignal,
+	"pc":        PCSignal,
+	"pc-errno":  PCErrnoSignal,
+	"call-edge": CallEdgeSignal,
+}
+
+// MakeSignalFunc returns signal function by name.
+// Returns nil function for the default executor-side signal.
+func MakeSignalFunc(name string) (SignalFunc, error) {
+	if name == "" || name == DefaultSignal {
+		return nil, nil
+	}
+	fn := SignalFuncs[name]
+	if fn == nil {
+		var names []string
+		for n := range SignalFuncs {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown signal %q, want %v or one of %v", name, DefaultSignal, names)
+	}
+	return fn, nil
+}
+
+// EdgeSignal is the same signal that executor computes:
+// code edges computed as xor of two subsequent basic block PCs.
+func EdgeSignal(call, errno int, trace []uint32) []uint32 {
+	return edges(0, trace)
+}
+
+// PCSignal uses basic block PCs themselves as signal.
+func PCSignal(call, errno int, trace []uint32) []uint32 {
+	return Canonicalize(append(make([]uint32, 0, len(trace)), trace...))
+}
+
+// PCErrnoSignal is basic block PCs mixed with the call errno,
+// so that the same code reached with a different result is considered new.
+func PCErrnoSignal(call, errno int, trace []uint32) []uint32 {
+	salt := hash(uint32(errno))
+	sig := make([]uint32, len(trace))
+	for i, pc := range trace {
+		sig[i] = pc ^ salt
+	}
+	return Canonicalize(sig)
+}
+
+// CallEdgeSignal is code edges mixed with syscall ID,
+// so that the same code reached from different syscalls is considered new.
+func CallEdgeSignal(call, errno int, trace []uint32) []uint32 {
+	return edges(hash(uint32(call)), trace)
+}
+
+func edges(salt uint32, trace []uint32) []uint32 {
+	sig := make([]uint32, len(trace))
+	prev := uint32(0)
+	for i, pc := range trace {
+		sig[i] = pc ^ prev ^ salt
+		prev = hash(pc)
+	}
+	return Canonicalize(sig)
+}
+
+// hash is the same hash function that executor uses for signal.
+func hash(a uint32) uint32 {
+	a = (a ^ 61) ^ (a >> 16)
+	a = a + (a << 3)
+	a = a ^ (a >> 4)
+	a = a * 0x27d4eb2d
+	a = a ^ (a >> 15)
+	return a
+}
@@ -0,0 +1,99 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package cover
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestEdgeSignal(t *testing.T) {
+	// Values computed by executor for the same trace.
+	trace := []uint32{0x81000010, 0x81000020, 0x81000010}
+	sig := EdgeSignal(0, 0, trace)
+	want := Canonicalize([]uint32{
+		0x81000010,
+		0x81000020 ^ hash(0x81000010),
+		0x81000010 ^ hash(0x81000020),
+	})
+	if !reflect.DeepEqual(sig, []uint32(want)) {
+		t.Fatalf("got signal %x, want %x", sig, want)
+	}
+}
+
+func TestSignalFuncs(t *testing.T) {
+	rnd, iters := initTest(t)
+	for name, fn := range SignalFuncs {
+		fn1, err := MakeSignalFunc(name)
+		if err != nil || fn1 == nil {
+			t.Fatalf("failed to make signal func %v: %v", name, err)
+		}
+		for i := 0; i < iters/100; i++ {
+			trace := randTrace(rnd, 100)
+			sig := fn(1, 0, append([]uint32{}, trace...))
+			if !sort.IsSorted(Cover(sig)) {
+				t.Fatalf("%v: signal is not sorted: %x", name, sig)
+			}
+			if sig1 := fn(1, 0, trace); !reflect.DeepEqual(sig, sig1) {
+				t.Fatalf("%v: signal is not deterministic:\n%x\n%x", name, sig, sig1)
+			}
+			if len(trace) != 0 && len(sig) == 0 {
+				t.Fatalf("%v: no signal for trace %x", name, trace)
+			}
+		}
+	}
+	if fn, err := MakeSignalFunc(DefaultSignal); fn != nil || err != nil {
+		t.Fatalf("default signal: fn=%v err=%v", fn != nil, err)
+	}
+	if _, err := MakeSignalFunc("foo"); err == nil {
+		t.Fatalf("unknown signal does not fail")
+	}
+}
+
+func randTrace(rnd *rand.Rand, n int) []uint32 {
+	trace := make([]uint32, rnd.Intn(n))
+	for i := range trace {
+		// Real traces contain lots of repeated PCs.
+		trace[i] = 0x81000000 + uint32(rnd.Intn(n/2+1))*16
+	}
+	return trace
+}
+
+func benchmarkSignal(b *testing.B, fn SignalFunc) {
+	rnd := rand.New(rand.NewSource(0))
+	traces := make([][]uint32, 100)
+	for i := range traces {
+		traces[i] = randTrace(rnd, 20000)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = fn(1, 0, traces[i%len(traces)])
+	}
+}
+
+// BenchmarkSignalCopy is the baseline for the signal benchmarks:
+// copying of the trace out of the shared memory region.
+func BenchmarkSignalCopy(b *testing.B) {
+	benchmarkSignal(b, func(call, errno int, trace []uint32) []uint32 {
+		return append([]uint32{}, trace...)
+	})
+}
+
+func BenchmarkSignalEdge(b *testing.B) {
+	benchmarkSignal(b, EdgeSignal)
+}
+
+func BenchmarkSignalPC(b *testing.B) {
+	benchmarkSignal(b, PCSignal)
+}
+
+func BenchmarkSignalPCErrno(b *testing.B) {
+	benchmarkSignal(b, PCErrnoSignal)
+}
+
+func BenchmarkSignalCallEdge(b *testing.B) {
+	benchmarkSignal(b, CallEdgeSignal)
+}
@@ -0,0 +1,452 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// Package coverreport builds line- and function-level kernel coverage profiles
+// from PCs of covered __sanitizer_cov_trace_pc calls.
+// The profiles can be exported in LCOV format and as per-function and per-directory summaries.
+package coverreport
+
+import (
+	"bufio"
+	"bytes"
+	"debug/elf"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/syzkaller/pkg/symbolizer"
+)
+
+// CallLen is length of a call instruction, x86-ism.
+// Fuzzers report return addresses of __sanitizer_cov_trace_pc calls,
+// subtract CallLen to get PCs of the calls.
+// CallLen and parsing of call instructions in CoveredPCs are x86_64-specific,
+// so CoveredPCs and VMOffset reject binaries for other architectures (see checkArch).
+const CallLen = 5
+
+// checkArch checks that bin is an x86_64 binary.
+func checkArch(bin string) error {
+	f, err := elf.Open(bin)
+	if err != nil {
+		return fmt.Errorf("failed to open %v: %v", bin, err)
+	}
+	defer f.Close()
+	if f.Machine != elf.EM_X86_64 {
+		return fmt.Errorf("coverage is supported only for x86_64 kernels, %v is %v", bin, f.Machine)
+	}
+	return nil
+}
+
+// Symbol is a text symbol of the kernel binary.
+type Symbol struct {
+	Start uint64
+	End   uint64
+	Name  string
+}
+
+// Profile is coverage of a set of PCs.
+// Only functions with at least one covered PC are included.
+type Profile struct {
+	Prefix string // common source path prefix that is stripped from file names
+	Files  []*File
+	Funcs  []*Func
+}
+
+type File struct {
+	Name  string // file name without Prefix
+	Path  string // file name as in debug info
+	Lines []Line // sorted by line number
+}
+
+type Line struct {
+	Line    int
+	Covered bool
+}
+
+type Func struct {
+	Name    string
+	File    string // file name without Prefix
+	Line    int    // first line with coverage PCs
+	Covered int    // number of covered coverage PCs
+	Total   int    // total number of coverage PCs
+}
+
+// Dir is coverage of all functions in files under a source directory (including subdirectories).
+type Dir struct {
+	Name         string
+	Funcs        int
+	CoveredFuncs int
+	Covered      int
+	Total        int
+}
+
+// SortedSymbols returns symbols read with symbolizer.ReadSymbols sorted by address.
+func SortedSymbols(symbols map[string][]symbolizer.Symbol) []Symbol {
+	var res []Symbol
+	for name, ss := range symbols {
+		for _, s := range ss {
+			res = append(res, Symbol{s.Addr, s.Addr + uint64(s.Size), name})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Start < res[j].Start
+	})
+	return res
+}
+
+// FindSymbol returns the symbol containing pc, or nil.
+func FindSymbol(symbols []Symbol, pc uint64) *Symbol {
+	idx := sort.Search(len(symbols), func(i int) bool {
+		return pc < symbols[i].End
+	})
+	if idx == len(symbols) || pc < symbols[idx].Start {
+		return nil
+	}
+	return &symbols[idx]
+}
+
+// UncoveredPCs returns uncovered PCs from coverPCs (PCs of all __sanitizer_cov_trace_pc calls, sorted)
+// in functions containing pcs.
+func UncoveredPCs(symbols []Symbol, coverPCs, pcs []uint64) []uint64 {
+	if len(coverPCs) == 0 {
+		return nil
+	}
+	handledFuncs := make(map[uint64]bool)
+	uncovered := make(map[uint64]bool)
+	for _, pc := range pcs {
+		s := FindSymbol(symbols, pc)
+		if s == nil {
+			continue
+		}
+		if !handledFuncs[s.Start] {
+			handledFuncs[s.Start] = true
+			startPC := sort.Search(len(coverPCs), func(i int) bool {
+				return s.Start <= coverPCs[i]
+			})
+			endPC := sort.Search(len(coverPCs), func(i int) bool {
+				return s.End < coverPCs[i]
+			})
+			for _, pc1 := range coverPCs[startPC:endPC] {
+				uncovered[pc1] = true
+			}
+		}
+		delete(uncovered, pc)
+	}
+	uncoveredPCs := make([]uint64, 0, len(uncovered))
+	for pc := range uncovered {
+		uncoveredPCs = append(uncoveredPCs, pc)
+	}
+	sort.Slice(uncoveredPCs, func(i, j int) bool {
+		return uncoveredPCs[i] < uncoveredPCs[j]
+	})
+	return uncoveredPCs
+}
+
+// MakeProfile symbolizes covered pcs and uncovered coverage PCs in the same functions
+// and builds coverage profile for them.
+func MakeProfile(vmlinux string, symbols []Symbol, coverPCs, pcs []uint64) (*Profile, error) {
+	uncovered := UncoveredPCs(symbols, coverPCs, pcs)
+	coveredFrames, err := Symbolize(vmlinux, pcs)
+	if err != nil {
+		return nil, err
+	}
+	if len(coveredFrames) == 0 {
+		return nil, fmt.Errorf("'%s' does not have debug info (set CONFIG_DEBUG_INFO=y)", vmlinux)
+	}
+	uncoveredFrames, err := Symbolize(vmlinux, uncovered)
+	if err != nil {
+		return nil, err
+	}
+	return makeProfile(symbols, pcs, uncovered, coveredFrames, uncoveredFrames), nil
+}
+
+func makeProfile(symbols []Symbol, pcs, uncovered []uint64, coveredFrames, uncoveredFrames []symbolizer.Frame) *Profile {
+	prefix := commonPrefix(coveredFrames, uncoveredFrames)
+	trim := func(file string) string {
+		if len(file) > len(prefix) {
+			return file[len(prefix):]
+		}
+		return file
+	}
+	p := &Profile{Prefix: prefix}
+	for path, lines := range fileSet(coveredFrames, uncoveredFrames) {
+		p.Files = append(p.Files, &File{
+			Name:  trim(path),
+			Path:  path,
+			Lines: lines,
+		})
+	}
+	sort.Slice(p.Files, func(i, j int) bool {
+		return p.Files[i].Name < p.Files[j].Name
+	})
+
+	funcs := make(map[uint64]*Func)
+	for i, list := range [][]uint64{pcs, uncovered} {
+		handled := make(map[uint64]bool)
+		for _, pc := range list {
+			if handled[pc] {
+				continue
+			}
+			handled[pc] = true
+			s := FindSymbol(symbols, pc)
+			if s == nil {
+				continue
+			}
+			fn := funcs[s.Start]
+			if fn == nil {
+				if i != 0 {
+					continue
+				}
+				fn = &Func{Name: s.Name}
+				funcs[s.Start] = fn
+			}
+			if i == 0 {
+				fn.Covered++
+			}
+			fn.Total++
+		}
+	}
+	// Non-inline frames give the source location of the function itself.
+	// Frames are mapped to functions by PC rather than by name,
+	// because there can be several static functions with the same name.
+	for _, frames := range [][]symbolizer.Frame{coveredFrames, uncoveredFrames} {
+		for _, frame := range frames {
+			if frame.Inline {
+				continue
+			}
+			s := FindSymbol(symbols, frame.PC)
+			if s == nil {
+				continue
+			}
+			fn := funcs[s.Start]
+			if fn == nil {
+				continue
+			}
+			if fn.Line == 0 || frame.Line < fn.Line {
+				fn.File = trim(frame.File)
+				fn.Line = frame.Line
+			}
+		}
+	}
+	for _, fn := range funcs {
+		p.Funcs = append(p.Funcs, fn)
+	}
+	sort.Slice(p.Funcs, func(i, j int) bool {
+		f1, f2 := p.Funcs[i], p.Funcs[j]
+		if f1.File != f2.File {
+			return f1.File < f2.File
+		}
+		if f1.Line != f2.Line {
+			return f1.Line < f2.Line
+		}
+		return f1.Name < f2.Name
+	})
+	return p
+}
+
+// fileSet returns covered and uncovered lines of all source files.
+// Uncovered lines are included only for functions with some coverage.
+func fileSet(covered, uncovered []symbolizer.Frame) map[string][]Line {
+	files := make(map[string]map[int]bool)
+	funcs := make(map[string]bool)
+	for _, frame := range covered {
+		if files[frame.File] == nil {
+			files[frame.File] = make(map[int]bool)
+		}
+		files[frame.File][frame.Line] = true
+		funcs[frame.Func] = true
+	}
+	for _, frame := range uncovered {
+		if !funcs[frame.Func] {
+			continue
+		}
+		if files[frame.File] == nil {
+			files[frame.File] = make(map[int]bool)
+		}
+		if !files[frame.File][frame.Line] {
+			files[frame.File][frame.Line] = false
+		}
+	}
+	res := make(map[string][]Line)
+	for f, lines := range files {
+		sorted := make([]Line, 0, len(lines))
+		for ln, covered := range lines {
+			sorted = append(sorted, Line{ln, covered})
+		}
+		sort.Slice(sorted, func(i, j int) bool {
+			return sorted[i].Line < sorted[j].Line
+		})
+		res[f] = sorted
+	}
+	return res
+}
+
+func commonPrefix(frames ...[]symbolizer.Frame) string {
+	prefix, first := "", true
+	for _, list := range frames {
+		for _, frame := range list {
+			if first {
+				prefix, first = frame.File, false
+				continue
+			}
+			i := 0
+			for ; i < len(prefix) && i < len(frame.File); i++ {
+				if prefix[i] != frame.File[i] {
+					break
+				}
+			}
+			prefix = prefix[:i]
+		}
+	}
+	// Don't cut file names in the middle.
+	return prefix[:strings.LastIndexByte(prefix, '/')+1]
+}
+
+// Dirs returns coverage rolled up into all source directories of the profile.
+// The root directory is named ".".
+func (p *Profile) Dirs() []*Dir {
+	dirs := make(map[string]*Dir)
+	for _, fn := range p.Funcs {
+		for dir := filepath.Dir(fn.File); ; dir = filepath.Dir(dir) {
+			if dir == "/" {
+				dir = "."
+			}
+			d := dirs[dir]
+			if d == nil {
+				d = &Dir{Name: dir}
+				dirs[dir] = d
+			}
+			d.Funcs++
+			if fn.Covered != 0 {
+				d.CoveredFuncs++
+			}
+			d.Covered += fn.Covered
+			d.Total += fn.Total
+			if dir == "." {
+				break
+			}
+		}
+	}
+	var res []*Dir
+	for _, d := range dirs {
+		res = append(res, d)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
+// Symbolize returns source frames for pcs.
+func Symbolize(vmlinux string, pcs []uint64) ([]symbolizer.Frame, error) {
+	symb := symbolizer.NewSymbolizer()
+	defer symb.Close()
+
+	frames, err := symb.SymbolizeArray(vmlinux, pcs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range frames {
+		frames[i].PC--
+	}
+	return frames, nil
+}
+
+// CoveredPCs returns sorted list of PCs of __sanitizer_cov_trace_pc calls in binary bin.
+func CoveredPCs(bin string) ([]uint64, error) {
+	if err := checkArch(bin); err != nil {
+		return nil, err
+	}
+	cmd := exec.Command("objdump", "-d", "--no-show-raw-insn", bin)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	defer stdout.Close()
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	defer cmd.Wait()
+	var pcs []uint64
+	s := bufio.NewScanner(stdout)
+	// A line looks as: "ffffffff8100206a:       callq  ffffffff815cc1d0 <__sanitizer_cov_trace_pc>"
+	callInsn := []byte("callq ")
+	traceFunc := []byte(" <__sanitizer_cov_trace_pc>")
+	for s.Scan() {
+		ln := s.Bytes()
+		if pos := bytes.Index(ln, callInsn); pos == -1 {
+			continue
+		} else if bytes.Index(ln[pos:], traceFunc) == -1 {
+			continue
+		}
+		colon := bytes.IndexByte(ln, ':')
+		if colon == -1 {
+			continue
+		}
+		pc, err := strconv.ParseUint(string(ln[:colon]), 16, 64)
+		if err != nil {
+			continue
+		}
+		pcs = append(pcs, pc)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(pcs, func(i, j int) bool {
+		return pcs[i] < pcs[j]
+	})
+	return pcs, nil
+}
+
+var (
+	vmOffsetsMu sync.Mutex
+	vmOffsets   = make(map[string]uint32)
+)
+
+// VMOffset returns upper 32 bits of text addresses of vmlinux
+// that are needed to restore PCs reported by fuzzers (see cover.RestorePC).
+func VMOffset(vmlinux string) (uint32, error) {
+	vmOffsetsMu.Lock()
+	defer vmOffsetsMu.Unlock()
+	if v, ok := vmOffsets[vmlinux]; ok {
+		return v, nil
+	}
+	if err := checkArch(vmlinux); err != nil {
+		return 0, err
+	}
+	out, err := exec.Command("readelf", "-SW", vmlinux).CombinedOutput()
+	if err != nil {
+		return 0, fmt.Errorf("readelf failed: %v\n%s", err, out)
+	}
+	s := bufio.NewScanner(bytes.NewReader(out))
+	var addr uint32
+	for s.Scan() {
+		ln := s.Text()
+		pieces := strings.Fields(ln)
+		for i := 0; i < len(pieces); i++ {
+			if pieces[i] != "PROGBITS" {
+				continue
+			}
+			v, err := strconv.ParseUint("0x"+pieces[i+1], 0, 64)
+			if err != nil {
+				return 0, fmt.Errorf("failed to parse addr in readelf output: %v", err)
+			}
+			if v == 0 {
+				continue
+			}
+			v32 := (uint32)(v >> 32)
+			if addr == 0 {
+				addr = v32
+			}
+			if addr != v32 {
+				return 0, fmt.Errorf("different section offsets in a single binary")
+			}
+		}
+	}
+	vmOffsets[vmlinux] = addr
+	return addr, nil
+}
@@ -0,0 +1,214 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package coverreport
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/symbolizer"
+)
+
+func testProfile() *Profile {
+	symbols := []Symbol{
+		{0x100, 0x200, "tcp_sendmsg"},
+		{0x200, 0x300, "tcp_recvmsg"},
+		{0x300, 0x400, "sys_socket"},
+	}
+	pcs := []uint64{0x110, 0x120, 0x310}
+	uncovered := UncoveredPCs(symbols, []uint64{0x110, 0x120, 0x130, 0x210, 0x310}, pcs)
+	coveredFrames := []symbolizer.Frame{
+		{PC: 0x110, Func: "tcp_sendmsg", File: "/src/net/ipv4/tcp.c", Line: 10},
+		{PC: 0x120, Func: "skb_put", File: "/src/include/linux/skbuff.h", Line: 5, Inline: true},
+		{PC: 0x120, Func: "tcp_sendmsg", File: "/src/net/ipv4/tcp.c", Line: 12},
+		{PC: 0x310, Func: "sys_socket", File: "/src/net/socket.c", Line: 100},
+	}
+	uncoveredFrames := []symbolizer.Frame{
+		{PC: 0x130, Func: "tcp_sendmsg", File: "/src/net/ipv4/tcp.c", Line: 14},
+	}
+	return makeProfile(symbols, pcs, uncovered, coveredFrames, uncoveredFrames)
+}
+
+func TestUncoveredPCs(t *testing.T) {
+	symbols := []Symbol{
+		{0x100, 0x200, "foo"},
+		{0x200, 0x300, "bar"},
+	}
+	got := UncoveredPCs(symbols, []uint64{0x110, 0x120, 0x130, 0x210}, []uint64{0x120, 0x500})
+	want := []uint64{0x110, 0x130}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %x, want %x", got, want)
+	}
+}
+
+func TestProfile(t *testing.T) {
+	p := testProfile()
+	if p.Prefix != "/src/" {
+		t.Fatalf("bad prefix %q", p.Prefix)
+	}
+	wantFiles := []*File{
+		{"include/linux/skbuff.h", "/src/include/linux/skbuff.h", []Line{{5, true}}},
+		{"net/ipv4/tcp.c", "/src/net/ipv4/tcp.c", []Line{{10, true}, {12, true}, {14, false}}},
+		{"net/socket.c", "/src/net/socket.c", []Line{{100, true}}},
+	}
+	if !reflect.DeepEqual(p.Files, wantFiles) {
+		t.Fatalf("bad files:\n%+v\nwant:\n%+v", p.Files, wantFiles)
+	}
+	wantFuncs := []*Func{
+		{"tcp_sendmsg", "net/ipv4/tcp.c", 10, 2, 3},
+		{"sys_socket", "net/socket.c", 100, 1, 1},
+	}
+	if !reflect.DeepEqual(p.Funcs, wantFuncs) {
+		t.Fatalf("bad funcs:\n%+v\nwant:\n%+v", p.Funcs, wantFuncs)
+	}
+	wantDirs := []*Dir{
+		{".", 2, 2, 3, 4},
+		{"net", 2, 2, 3, 4},
+		{"net/ipv4", 1, 1, 2, 3},
+	}
+	if dirs := p.Dirs(); !reflect.DeepEqual(dirs, wantDirs) {
+		t.Fatalf("bad dirs:\n%+v\nwant:\n%+v", dirs, wantDirs)
+	}
+}
+
+func TestProfileStaticFuncs(t *testing.T) {
+	// Two static functions with the same name in different files.
+	symbols := []Symbol{
+		{0x100, 0x200, "init_once"},
+		{0x200, 0x300, "init_once"},
+	}
+	pcs := []uint64{0x110, 0x210}
+	coveredFrames := []symbolizer.Frame{
+		{PC: 0x110, Func: "init_once", File: "/src/fs/inode.c", Line: 20},
+		{PC: 0x210, Func: "init_once", File: "/src/mm/slab.c", Line: 10},
+	}
+	p := makeProfile(symbols, pcs, nil, coveredFrames, nil)
+	wantFuncs := []*Func{
+		{"init_once", "fs/inode.c", 20, 1, 1},
+		{"init_once", "mm/slab.c", 10, 1, 1},
+	}
+	if !reflect.DeepEqual(p.Funcs, wantFuncs) {
+		t.Fatalf("bad funcs:\n%+v\nwant:\n%+v", p.Funcs, wantFuncs)
+	}
+}
+
+func TestExport(t *testing.T) {
+	p := testProfile()
+	tests := map[string]string{
+		"lcov": `TN:
+SF:/src/include/linux/skbuff.h
+FNF:0
+FNH:0
+DA:5,1
+LF:1
+LH:1
+end_of_record
+TN:
+SF:/src/net/ipv4/tcp.c
+FN:10,tcp_sendmsg
+FNDA:1,tcp_sendmsg
+FNF:1
+FNH:1
+DA:10,1
+DA:12,1
+DA:14,0
+LF:3
+LH:2
+end_of_record
+TN:
+SF:/src/net/socket.c
+FN:100,sys_socket
+FNDA:1,sys_socket
+FNF:1
+FNH:1
+DA:100,1
+LF:1
+LH:1
+end_of_record
+`,
+		"funcs.csv": `file,function,line,covered,total
+net/ipv4/tcp.c,tcp_sendmsg,10,2,3
+net/socket.c,sys_socket,100,1,1
+`,
+		"dirs.csv": `dir,functions,covered functions,covered,total
+.,2,2,3,4
+net,2,2,3,4
+net/ipv4,1,1,2,3
+`,
+		"dirs.json": `[
+	{
+		"Name": ".",
+		"Funcs": 2,
+		"CoveredFuncs": 2,
+		"Covered": 3,
+		"Total": 4
+	},
+	{
+		"Name": "net",
+		"Funcs": 2,
+		"CoveredFuncs": 2,
+		"Covered": 3,
+		"Total": 4
+	},
+	{
+		"Name": "net/ipv4",
+		"Funcs": 1,
+		"CoveredFuncs": 1,
+		"Covered": 2,
+		"Total": 3
+	}
+]
+`,
+	}
+	for format, want := range tests {
+		buf := new(bytes.Buffer)
+		if err := Export(buf, p, format); err != nil {
+			t.Fatalf("%v: %v", format, err)
+		}
+		if got := buf.String(); got != want {
+			t.Errorf("%v: got:\n%v\nwant:\n%v", format, got, want)
+		}
+	}
+	if err := Export(new(bytes.Buffer), p, "xml"); err == nil {
+		t.Fatalf("unknown format is accepted")
+	}
+}
+
+func TestParsePCs(t *testing.T) {
+	pcs, err := ParsePCs([]byte("0xffffffff81000010\n\n0xffffffff81000020\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []uint64{0xffffffff81000010, 0xffffffff81000020}; !reflect.DeepEqual(pcs, want) {
+		t.Fatalf("got %x, want %x", pcs, want)
+	}
+	if _, err := ParsePCs([]byte("0x10\nfoo\n")); err == nil {
+		t.Fatalf("bad PC is accepted")
+	}
+}
+
+func TestCheckArch(t *testing.T) {
+	// The test binary is built for the host.
+	err := checkArch(os.Args[0])
+	if runtime.GOARCH == "amd64" && err != nil {
+		t.Fatalf("x86_64 binary is rejected: %v", err)
+	}
+	if runtime.GOARCH != "amd64" && err == nil {
+		t.Fatalf("%v binary is not rejected", runtime.GOARCH)
+	}
+	f, err := ioutil.TempFile("", "syz-coverreport")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.Write([]byte("not an elf"))
+	f.Close()
+	if err := checkArch(f.Name()); err == nil {
+		t.Fatalf("non-ELF file is not rejected")
+	}
+}
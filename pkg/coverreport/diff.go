@@ -0,0 +1,303 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package coverreport
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/syzkaller/pkg/osutil"
+)
+
+// Snapshot is coverage profile saved at some point in time.
+// Snapshots do not refer to the kernel binary, so they can be compared across kernel builds.
+type Snapshot struct {
+	Time    time.Time
+	Tag     string // kernel build tag (see manager tag config parameter)
+	PCs     int    // number of covered PCs
+	PCsHash string // hash of the set of covered PCs, used to detect coverage changes
+	Profile *Profile
+}
+
+// WriteSnapshot saves snapshot s to file as gzipped JSON.
+func WriteSnapshot(file string, s *Snapshot) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	buf := new(bytes.Buffer)
+	gz := gzip.NewWriter(buf)
+	gz.Write(data)
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return osutil.WriteFile(file, buf.Bytes())
+}
+
+// ReadSnapshot reads snapshot saved with WriteSnapshot.
+func ReadSnapshot(file string) (*Snapshot, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %v: %v", file, err)
+	}
+	defer gz.Close()
+	s := new(Snapshot)
+	if err := json.NewDecoder(gz).Decode(s); err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %v: %v", file, err)
+	}
+	if s.Profile == nil {
+		s.Profile = new(Profile)
+	}
+	return s, nil
+}
+
+// ReadSnapshotInfo reads snapshot saved with WriteSnapshot without the profile.
+// It is much faster than ReadSnapshot since the profile follows the other fields in the file.
+func ReadSnapshotInfo(file string) (*Snapshot, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %v: %v", file, err)
+	}
+	defer gz.Close()
+	s := new(Snapshot)
+	dec := json.NewDecoder(gz)
+	if _, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %v: %v", file, err)
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot %v: %v", file, err)
+		}
+		var v interface{}
+		switch tok {
+		case "Time":
+			v = &s.Time
+		case "Tag":
+			v = &s.Tag
+		case "PCs":
+			v = &s.PCs
+		case "PCsHash":
+			v = &s.PCsHash
+		default:
+			return s, nil
+		}
+		if err := dec.Decode(v); err != nil {
+			return nil, fmt.Errorf("failed to read snapshot %v: %v", file, err)
+		}
+	}
+	return s, nil
+}
+
+// Diff is the difference between old and new coverage profiles.
+type Diff struct {
+	Files []*FileDiff // all files of both profiles
+	Funcs []*FuncDiff // only functions with changed coverage
+}
+
+type FileDiff struct {
+	Name    string
+	Path    string
+	Lines   []LineDiff // sorted by line number
+	Added   int        // number of newly covered lines
+	Removed int        // number of no longer covered lines
+}
+
+type LineDiff struct {
+	Line int
+	Old  bool // covered in the old profile
+	New  bool // covered in the new profile
+}
+
+type FuncDiff struct {
+	Name  string
+	File  string
+	Line  int
+	Old   int // covered PCs in the old profile
+	New   int // covered PCs in the new profile
+	Total int // total PCs (in the new profile if the function is present there)
+}
+
+// MakeDiff compares profiles old and new. If both profiles come from the same source tree,
+// files are matched by full path, otherwise (e.g. different kernel build dirs)
+// by path relative to the profile prefix.
+func MakeDiff(old, new *Profile) *Diff {
+	sameTree := old.Prefix != "" && new.Prefix != "" &&
+		(strings.HasPrefix(old.Prefix, new.Prefix) || strings.HasPrefix(new.Prefix, old.Prefix))
+	prefix := new.Prefix
+	if sameTree && len(old.Prefix) < len(prefix) || len(new.Files) == 0 {
+		prefix = old.Prefix
+	}
+	fileKey := func(p *Profile, name string) string {
+		if sameTree {
+			return p.Prefix + name
+		}
+		return name
+	}
+	files := make(map[string]*FileDiff)
+	lines := make(map[string]map[int]*LineDiff)
+	for i, p := range []*Profile{old, new} {
+		for _, f := range p.Files {
+			key := fileKey(p, f.Name)
+			fd := files[key]
+			if fd == nil {
+				fd = &FileDiff{Name: f.Name, Path: f.Path}
+				if sameTree {
+					fd.Name = strings.TrimPrefix(f.Path, prefix)
+				}
+				files[key] = fd
+				lines[key] = make(map[int]*LineDiff)
+			}
+			if i == 1 {
+				fd.Path = f.Path
+			}
+			for _, ln := range f.Lines {
+				ld := lines[key][ln.Line]
+				if ld == nil {
+					ld = &LineDiff{Line: ln.Line}
+					lines[key][ln.Line] = ld
+				}
+				if i == 0 {
+					ld.Old = ln.Covered
+				} else {
+					ld.New = ln.Covered
+				}
+			}
+		}
+	}
+	d := diffFiles(files, lines)
+	funcs := make(map[string]*FuncDiff)
+	for i, p := range []*Profile{old, new} {
+		for _, fn := range p.Funcs {
+			key := fileKey(p, fn.File) + ":" + fn.Name
+			fd := funcs[key]
+			if fd == nil {
+				fd = &FuncDiff{Name: fn.Name, File: fn.File}
+				if sameTree {
+					fd.File = strings.TrimPrefix(p.Prefix+fn.File, prefix)
+				}
+				funcs[key] = fd
+			}
+			if i == 0 {
+				fd.Old = fn.Covered
+			} else {
+				fd.New = fn.Covered
+			}
+			fd.Line = fn.Line
+			fd.Total = fn.Total
+		}
+	}
+	for _, fd := range funcs {
+		if fd.Old != fd.New {
+			d.Funcs = append(d.Funcs, fd)
+		}
+	}
+	sort.Slice(d.Funcs, func(i, j int) bool {
+		f1, f2 := d.Funcs[i], d.Funcs[j]
+		if f1.File != f2.File {
+			return f1.File < f2.File
+		}
+		if f1.Line != f2.Line {
+			return f1.Line < f2.Line
+		}
+		return f1.Name < f2.Name
+	})
+	return d
+}
+
+func diffFiles(files map[string]*FileDiff, lines map[string]map[int]*LineDiff) *Diff {
+	d := new(Diff)
+	for key, fd := range files {
+		for _, ld := range lines[key] {
+			fd.Lines = append(fd.Lines, *ld)
+			if ld.New && !ld.Old {
+				fd.Added++
+			}
+			if ld.Old && !ld.New {
+				fd.Removed++
+			}
+		}
+		sort.Slice(fd.Lines, func(i, j int) bool {
+			return fd.Lines[i].Line < fd.Lines[j].Line
+		})
+		d.Files = append(d.Files, fd)
+	}
+	sort.Slice(d.Files, func(i, j int) bool {
+		return d.Files[i].Name < d.Files[j].Name
+	})
+	return d
+}
+
+// DiffFormats lists supported diff export formats.
+var DiffFormats = []string{"text", "json"}
+
+// ExportDiff writes diff d to w in the format (one of DiffFormats).
+// Text format lists functions with changed coverage and then changed lines,
+// newly covered are marked with '+', no longer covered with '-'.
+func ExportDiff(w io.Writer, d *Diff, format string) error {
+	switch format {
+	case "text":
+		buf := bufio.NewWriter(w)
+		added, removed := 0, 0
+		for _, f := range d.Files {
+			added += f.Added
+			removed += f.Removed
+		}
+		fmt.Fprintf(buf, "%v functions changed, %v lines newly covered, %v lines no longer covered\n",
+			len(d.Funcs), added, removed)
+		if len(d.Funcs) != 0 {
+			fmt.Fprintf(buf, "\n")
+		}
+		for _, fn := range d.Funcs {
+			fmt.Fprintf(buf, "%v %v %v:%v %v/%v -> %v/%v\n", diffMark(fn.Old != 0, fn.New != 0),
+				fn.Name, fn.File, fn.Line, fn.Old, fn.Total, fn.New, fn.Total)
+		}
+		for _, f := range d.Files {
+			if f.Added == 0 && f.Removed == 0 {
+				continue
+			}
+			fmt.Fprintf(buf, "\n%v (+%v -%v)\n", f.Name, f.Added, f.Removed)
+			for _, ln := range f.Lines {
+				if ln.Old != ln.New {
+					fmt.Fprintf(buf, "%v %v\n", diffMark(ln.Old, ln.New), ln.Line)
+				}
+			}
+		}
+		return buf.Flush()
+	case "json":
+		return exportJSON(w, d)
+	default:
+		return fmt.Errorf("unknown diff format %q, supported: %v",
+			format, strings.Join(DiffFormats, ", "))
+	}
+}
+
+func diffMark(old, new bool) string {
+	switch {
+	case !old && new:
+		return "+"
+	case old && !new:
+		return "-"
+	default:
+		return "~"
+	}
+}
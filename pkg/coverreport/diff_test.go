@@ -0,0 +1,114 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package coverreport
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testNewProfile(prefix string) *Profile {
+	return &Profile{
+		Prefix: prefix,
+		Files: []*File{
+			{"net/ipv4/tcp.c", prefix + "net/ipv4/tcp.c", []Line{{10, true}, {12, false}, {14, true}}},
+			{"net/ipv6/tcp_ipv6.c", prefix + "net/ipv6/tcp_ipv6.c", []Line{{20, true}}},
+		},
+		Funcs: []*Func{
+			{"tcp_sendmsg", "net/ipv4/tcp.c", 10, 2, 3},
+			{"tcp_v6_connect", "net/ipv6/tcp_ipv6.c", 20, 1, 4},
+		},
+	}
+}
+
+func TestMakeDiff(t *testing.T) {
+	for _, prefix := range []string{"/src/", "/build/linux/"} {
+		d := MakeDiff(testProfile(), testNewProfile(prefix))
+		wantFiles := []*FileDiff{
+			{"include/linux/skbuff.h", "/src/include/linux/skbuff.h", []LineDiff{{5, true, false}}, 0, 1},
+			{"net/ipv4/tcp.c", prefix + "net/ipv4/tcp.c",
+				[]LineDiff{{10, true, true}, {12, true, false}, {14, false, true}}, 1, 1},
+			{"net/ipv6/tcp_ipv6.c", prefix + "net/ipv6/tcp_ipv6.c", []LineDiff{{20, false, true}}, 1, 0},
+			{"net/socket.c", "/src/net/socket.c", []LineDiff{{100, true, false}}, 0, 1},
+		}
+		if !reflect.DeepEqual(d.Files, wantFiles) {
+			t.Errorf("prefix %v: bad files:\n%+v\nwant:\n%+v", prefix, d.Files, wantFiles)
+		}
+		wantFuncs := []*FuncDiff{
+			{"tcp_v6_connect", "net/ipv6/tcp_ipv6.c", 20, 0, 1, 4},
+			{"sys_socket", "net/socket.c", 100, 1, 0, 1},
+		}
+		if !reflect.DeepEqual(d.Funcs, wantFuncs) {
+			t.Errorf("prefix %v: bad funcs:\n%+v\nwant:\n%+v", prefix, d.Funcs, wantFuncs)
+		}
+	}
+}
+
+func TestExportDiff(t *testing.T) {
+	d := MakeDiff(testProfile(), testNewProfile("/src/"))
+	buf := new(bytes.Buffer)
+	if err := ExportDiff(buf, d, "text"); err != nil {
+		t.Fatal(err)
+	}
+	want := `2 functions changed, 2 lines newly covered, 3 lines no longer covered
+
++ tcp_v6_connect net/ipv6/tcp_ipv6.c:20 0/4 -> 1/4
+- sys_socket net/socket.c:100 1/1 -> 0/1
+
+include/linux/skbuff.h (+0 -1)
+- 5
+
+net/ipv4/tcp.c (+1 -1)
+- 12
++ 14
+
+net/ipv6/tcp_ipv6.c (+1 -0)
++ 20
+
+net/socket.c (+0 -1)
+- 100
+`
+	if got := buf.String(); got != want {
+		t.Fatalf("got:\n%v\nwant:\n%v", got, want)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syz-coverreport")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "snapshot.json.gz")
+	s := &Snapshot{
+		Time:    time.Date(2018, 5, 1, 10, 20, 30, 0, time.UTC),
+		Tag:     "v4.17-rc3",
+		PCs:     3,
+		PCsHash: "abcd",
+		Profile: testProfile(),
+	}
+	if err := WriteSnapshot(file, s); err != nil {
+		t.Fatal(err)
+	}
+	s1, err := ReadSnapshot(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s, s1) {
+		t.Fatalf("read snapshot differs:\n%+v\nwant:\n%+v", s1, s)
+	}
+	info, err := ReadSnapshotInfo(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.Time.Equal(s.Time) || info.Tag != s.Tag || info.PCs != s.PCs || info.PCsHash != s.PCsHash ||
+		info.Profile != nil {
+		t.Fatalf("bad snapshot info: %+v", info)
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package coverreport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Formats lists supported export formats.
+var Formats = []string{"lcov", "funcs.csv", "funcs.json", "dirs.csv", "dirs.json"}
+
+// IsFormat returns whether format is one of Formats.
+func IsFormat(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// Export writes profile p to w in the format (one of Formats).
+func Export(w io.Writer, p *Profile, format string) error {
+	switch format {
+	case "lcov":
+		return exportLCOV(w, p)
+	case "funcs.csv":
+		rows := [][]string{{"file", "function", "line", "covered", "total"}}
+		for _, fn := range p.Funcs {
+			rows = append(rows, []string{fn.File, fn.Name, strconv.Itoa(fn.Line),
+				strconv.Itoa(fn.Covered), strconv.Itoa(fn.Total)})
+		}
+		return exportCSV(w, rows)
+	case "funcs.json":
+		return exportJSON(w, p.Funcs)
+	case "dirs.csv":
+		rows := [][]string{{"dir", "functions", "covered functions", "covered", "total"}}
+		for _, d := range p.Dirs() {
+			rows = append(rows, []string{d.Name, strconv.Itoa(d.Funcs), strconv.Itoa(d.CoveredFuncs),
+				strconv.Itoa(d.Covered), strconv.Itoa(d.Total)})
+		}
+		return exportCSV(w, rows)
+	case "dirs.json":
+		return exportJSON(w, p.Dirs())
+	default:
+		return fmt.Errorf("unknown coverage format %q, supported: %v",
+			format, strings.Join(Formats, ", "))
+	}
+}
+
+// exportLCOV writes the profile in lcov tracefile format (see geninfo(1)), it can be rendered with genhtml.
+// Hit counts are not known, so covered lines and functions have count 1.
+func exportLCOV(w io.Writer, p *Profile) error {
+	funcs := make(map[string][]*Func)
+	for _, fn := range p.Funcs {
+		funcs[fn.File] = append(funcs[fn.File], fn)
+	}
+	buf := bufio.NewWriter(w)
+	for _, f := range p.Files {
+		fmt.Fprintf(buf, "TN:\nSF:%v\n", f.Path)
+		hit := 0
+		for _, fn := range funcs[f.Name] {
+			fmt.Fprintf(buf, "FN:%v,%v\n", fn.Line, fn.Name)
+		}
+		for _, fn := range funcs[f.Name] {
+			fmt.Fprintf(buf, "FNDA:%v,%v\n", lcovCount(fn.Covered != 0), fn.Name)
+			if fn.Covered != 0 {
+				hit++
+			}
+		}
+		fmt.Fprintf(buf, "FNF:%v\nFNH:%v\n", len(funcs[f.Name]), hit)
+		hit = 0
+		for _, ln := range f.Lines {
+			fmt.Fprintf(buf, "DA:%v,%v\n", ln.Line, lcovCount(ln.Covered))
+			if ln.Covered {
+				hit++
+			}
+		}
+		fmt.Fprintf(buf, "LF:%v\nLH:%v\nend_of_record\n", len(f.Lines), hit)
+	}
+	return buf.Flush()
+}
+
+func lcovCount(covered bool) int {
+	if covered {
+		return 1
+	}
+	return 0
+}
+
+func exportCSV(w io.Writer, rows [][]string) error {
+	cw := csv.NewWriter(w)
+	cw.WriteAll(rows)
+	return cw.Error()
+}
+
+func exportJSON(w io.Writer, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// ContentType returns HTTP content type for the format.
+func ContentType(format string) string {
+	switch {
+	case strings.HasSuffix(format, ".json"):
+		return "application/json"
+	case strings.HasSuffix(format, ".csv"):
+		return "text/csv; charset=utf-8"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+// ParsePCs parses raw coverage in the format of manager /rawcover page (one hex PC per line).
+func ParsePCs(data []byte) ([]uint64, error) {
+	var pcs []uint64
+	for i, ln := range bytes.Split(data, []byte{'\n'}) {
+		ln = bytes.TrimSpace(ln)
+		if len(ln) == 0 {
+			continue
+		}
+		pc, err := strconv.ParseUint(string(ln), 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %v: bad PC %q", i+1, ln)
+		}
+		pcs = append(pcs, pc)
+	}
+	return pcs, nil
+}
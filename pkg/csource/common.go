@@ -26,6 +26,17 @@ var commonHeader = `
 #if defined(SYZ_EXECUTOR) || (defined(SYZ_REPEAT) && defined(SYZ_WAIT_REPEAT))
 #include <sys/prctl.h>
 #endif
+#if defined(SYZ_EXECUTOR) || defined(SYZ_RACE_AFFINITY)
+#include <sched.h>
+#endif
+#if defined(SYZ_LEAK)
+#include <errno.h>
+#include <fcntl.h>
+#include <stdarg.h>
+#include <stdio.h>
+#include <stdlib.h>
+#include <string.h>
+#endif
 #if defined(SYZ_EXECUTOR) || (defined(SYZ_REPEAT) && defined(SYZ_WAIT_REPEAT) && defined(SYZ_USE_TMP_DIR))
 #include <dirent.h>
 #include <sys/mount.h>
@@ -165,18 +176,27 @@ extern unsigned syscall_count;
 
 #if defined(SYZ_EXECUTOR) || (defined(SYZ_REPEAT) && defined(SYZ_WAIT_REPEAT)) ||            \
     defined(SYZ_USE_TMP_DIR) || defined(SYZ_TUN_ENABLE) || defined(SYZ_SANDBOX_NAMESPACE) || \
-    defined(SYZ_SANDBOX_SETUID) || defined(SYZ_FAULT_INJECTION) || defined(__NR_syz_kvm_setup_cpu)
+    defined(SYZ_SANDBOX_SETUID) || defined(SYZ_FAULT_INJECTION) || defined(SYZ_LEAK) ||      \
+    defined(__NR_syz_kvm_setup_cpu)
 const int kFailStatus = 67;
 const int kRetryStatus = 69;
 #endif
 
 #if defined(SYZ_EXECUTOR)
 const int kErrorStatus = 68;
+
+const int kSetupFailStatus = 70;
+const int kSandboxFailStatus = 71;
+const int kKcovFailStatus = 72;
+const int kShmemFailStatus = 73;
+
+static int fail_status = kFailStatus;
 #endif
 
 #if defined(SYZ_EXECUTOR) || (defined(SYZ_REPEAT) && defined(SYZ_WAIT_REPEAT)) ||            \
     defined(SYZ_USE_TMP_DIR) || defined(SYZ_TUN_ENABLE) || defined(SYZ_SANDBOX_NAMESPACE) || \
-    defined(SYZ_SANDBOX_SETUID) || defined(SYZ_FAULT_INJECTION) || defined(__NR_syz_kvm_setup_cpu)
+    defined(SYZ_SANDBOX_SETUID) || defined(SYZ_FAULT_INJECTION) || defined(SYZ_LEAK) ||      \
+    defined(__NR_syz_kvm_setup_cpu)
 NORETURN static void fail(const char* msg, ...)
 {
 	int e = errno;
@@ -186,7 +206,11 @@ NORETURN static void fail(const char* msg, ...)
 	vfprintf(stderr, msg, args);
 	va_end(args);
 	fprintf(stderr, " (errno %d)\n", e);
+#if defined(SYZ_EXECUTOR)
+	doexit((e == ENOMEM || e == EAGAIN) ? kRetryStatus : fail_status);
+#else
 	doexit((e == ENOMEM || e == EAGAIN) ? kRetryStatus : kFailStatus);
+#endif
 }
 #endif
 
@@ -1706,6 +1730,28 @@ static uintptr_t syz_test()
 }
 #endif
 
+#if defined(SYZ_EXECUTOR)
+static uintptr_t syz_compare(uintptr_t want, uintptr_t want_len, uintptr_t got, uintptr_t got_len)
+{
+	if (want_len != got_len) {
+		debug("syz_compare: want_len=%lu got_len=%lu\n", (unsigned long)want_len, (unsigned long)got_len);
+		errno = EBADF;
+		return -1;
+	}
+	for (uintptr_t i = 0; i < want_len; i++) {
+		uint8_t want_byte = 0, got_byte = 0;
+		NONFAILING(want_byte = ((uint8_t*)want)[i]; got_byte = ((uint8_t*)got)[i]);
+		if (want_byte != got_byte) {
+			debug("syz_compare: mismatch at offset %lu: want 0x%x, got 0x%x\n",
+			      (unsigned long)i, want_byte, got_byte);
+			errno = EINVAL;
+			return -1;
+		}
+	}
+	return 0;
+}
+#endif
+
 #if defined(SYZ_EXECUTOR) || defined(SYZ_SANDBOX_NONE) || defined(SYZ_SANDBOX_SETUID) || defined(SYZ_SANDBOX_NAMESPACE)
 static void loop();
 
@@ -1980,6 +2026,81 @@ static int fault_injected(int fail_fd)
 }
 #endif
 
+#if defined(SYZ_EXECUTOR) || defined(SYZ_RACE_AFFINITY)
+static void race_affinity(int cpu)
+{
+	cpu_set_t set;
+	int ncpu = sysconf(_SC_NPROCESSORS_ONLN);
+	if (ncpu <= 0)
+		ncpu = 1;
+	CPU_ZERO(&set);
+	for (int i = 0; i < ncpu && i < CPU_SETSIZE; i++) {
+		if (cpu < 0 || i == cpu % ncpu)
+			CPU_SET(i, &set);
+	}
+	sched_setaffinity(0, sizeof(set), &set);
+}
+#endif
+
+#if defined(SYZ_EXECUTOR) || defined(SYZ_RACE)
+static void race_delay(uint64_t us)
+{
+	usleep(us);
+}
+#endif
+
+#if defined(SYZ_LEAK)
+#define KMEMLEAK_FILE "/sys/kernel/debug/kmemleak"
+
+static void kmemleak_write(int fd, const char* what)
+{
+	if (write(fd, what, strlen(what)) != (ssize_t)strlen(what))
+		fail("failed to write %s to %s", what, KMEMLEAK_FILE);
+}
+
+static void setup_leak()
+{
+	int fd = open(KMEMLEAK_FILE, O_RDWR);
+	if (fd == -1)
+		fail("failed to open %s", KMEMLEAK_FILE);
+	if (write(fd, "scan=off", 8) != 8 && errno != EBUSY)
+		fail("failed to write scan=off to %s", KMEMLEAK_FILE);
+	kmemleak_write(fd, "scan");
+	sleep(1);
+	kmemleak_write(fd, "scan");
+	kmemleak_write(fd, "clear");
+	close(fd);
+}
+
+static void check_leaks()
+{
+	static char buf[128 << 10];
+	int fd = open(KMEMLEAK_FILE, O_RDWR);
+	if (fd == -1)
+		fail("failed to open %s", KMEMLEAK_FILE);
+	kmemleak_write(fd, "scan");
+	sleep(1);
+	kmemleak_write(fd, "scan");
+	ssize_t n = read(fd, buf, sizeof(buf) - 1);
+	if (n < 0)
+		fail("failed to read %s", KMEMLEAK_FILE);
+	if (n != 0) {
+		sleep(1);
+		kmemleak_write(fd, "scan");
+		n = read(fd, buf, sizeof(buf) - 1);
+		if (n < 0)
+			fail("failed to read %s", KMEMLEAK_FILE);
+		if (n != 0) {
+			buf[n] = 0;
+			printf("BUG: memory leak\n%s\n", buf);
+			fflush(stdout);
+		}
+	}
+	kmemleak_write(fd, "clear");
+	close(fd);
+}
+#endif
+
 #if defined(SYZ_REPEAT)
 static void test();
 
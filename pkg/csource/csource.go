@@ -15,7 +15,9 @@ import (
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 	"unsafe"
 
@@ -34,6 +36,15 @@ type Options struct {
 	FaultCall int
 	FaultNth  int
 
+	Race         bool // start RaceCall2 RaceDelay microseconds after RaceCall1 without waiting for it
+	RaceCall1    int
+	RaceCall2    int
+	RaceDelay    int
+	RaceAffinity bool // pin racing calls to different CPUs
+
+	// Scan for kmemleak leaks while the program runs and print them.
+	Leak bool
+
 	// These options allow for a more fine-tuned control over the generated C code.
 	EnableTun  bool
 	UseTmpDir  bool
@@ -54,6 +65,18 @@ func (opts Options) Check() error {
 		// Collide requires threaded.
 		return errors.New("Collide without Threaded")
 	}
+	if opts.Race && (!opts.Threaded || opts.Collide) {
+		// Racing calls are scheduled on separate threads instead of colliding all calls.
+		return errors.New("Race without Threaded or with Collide")
+	}
+	if !opts.Race && opts.RaceAffinity {
+		// This does not affect generated code.
+		return errors.New("RaceAffinity without Race")
+	}
+	if opts.Leak && opts.Sandbox == "" {
+		// Leaks are checked for by the parent process while the sandboxed child runs the program.
+		return errors.New("Leak without Sandbox")
+	}
 	if !opts.Repeat && opts.Procs > 1 {
 		// This does not affect generated code.
 		return errors.New("Procs>1 without Repeat")
@@ -67,6 +90,47 @@ func (opts Options) Check() error {
 	return nil
 }
 
+// ParseOptions parses opts formatted with "%+v" (as saved in repro.prog headers).
+// Fields that are not present keep their zero values.
+func ParseOptions(data []byte) (Options, error) {
+	var opts Options
+	str := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(str, "{") || !strings.HasSuffix(str, "}") {
+		return opts, fmt.Errorf("bad options %q", str)
+	}
+	v := reflect.ValueOf(&opts).Elem()
+	for _, field := range strings.Fields(str[1 : len(str)-1]) {
+		colon := strings.IndexByte(field, ':')
+		if colon == -1 {
+			return opts, fmt.Errorf("bad options field %q", field)
+		}
+		name, val := field[:colon], field[colon+1:]
+		f := v.FieldByName(name)
+		if !f.IsValid() {
+			return opts, fmt.Errorf("unknown options field %q", name)
+		}
+		switch f.Kind() {
+		case reflect.Bool:
+			b, err := strconv.ParseBool(val)
+			if err != nil {
+				return opts, fmt.Errorf("bad options field %q: %v", field, err)
+			}
+			f.SetBool(b)
+		case reflect.Int:
+			n, err := strconv.ParseInt(val, 10, 64)
+			if err != nil {
+				return opts, fmt.Errorf("bad options field %q: %v", field, err)
+			}
+			f.SetInt(n)
+		case reflect.String:
+			f.SetString(val)
+		default:
+			return opts, fmt.Errorf("unsupported options field %q", name)
+		}
+	}
+	return opts, nil
+}
+
 func Write(p *prog.Prog, opts Options) ([]byte, error) {
 	if err := opts.Check(); err != nil {
 		return nil, fmt.Errorf("csource: invalid opts: %v", err)
@@ -125,9 +189,7 @@ func Write(p *prog.Prog, opts Options) ([]byte, error) {
 			fmt.Fprintf(w, "\tuse_temporary_dir();\n")
 		}
 		if opts.Sandbox != "" {
-			fmt.Fprintf(w, "\tint pid = do_sandbox_%v(0, %v);\n", opts.Sandbox, opts.EnableTun)
-			fmt.Fprint(w, "\tint status = 0;\n")
-			fmt.Fprint(w, "\twhile (waitpid(pid, &status, __WALL) != pid) {}\n")
+			generateSandbox(w, opts)
 		} else {
 			if opts.EnableTun {
 				fmt.Fprintf(w, "\tsetup_tun(0, %v);\n", opts.EnableTun)
@@ -146,9 +208,7 @@ func Write(p *prog.Prog, opts Options) ([]byte, error) {
 				fmt.Fprintf(w, "\tuse_temporary_dir();\n")
 			}
 			if opts.Sandbox != "" {
-				fmt.Fprintf(w, "\tint pid = do_sandbox_%v(0, %v);\n", opts.Sandbox, opts.EnableTun)
-				fmt.Fprint(w, "\tint status = 0;\n")
-				fmt.Fprint(w, "\twhile (waitpid(pid, &status, __WALL) != pid) {}\n")
+				generateSandbox(w, opts)
 			} else {
 				if opts.EnableTun {
 					fmt.Fprintf(w, "\tsetup_tun(0, %v);\n", opts.EnableTun)
@@ -159,6 +219,9 @@ func Write(p *prog.Prog, opts Options) ([]byte, error) {
 		} else {
 			fmt.Fprint(w, "int main()\n{\n")
 			fmt.Fprint(w, "\tint i;")
+			if opts.Leak {
+				fmt.Fprint(w, "\tsetup_leak();\n")
+			}
 			fmt.Fprintf(w, "\tfor (i = 0; i < %v; i++) {\n", opts.Procs)
 			fmt.Fprint(w, "\t\tif (fork() == 0) {\n")
 			if opts.HandleSegv {
@@ -180,7 +243,12 @@ func Write(p *prog.Prog, opts Options) ([]byte, error) {
 			fmt.Fprint(w, "\t\t\treturn 0;\n")
 			fmt.Fprint(w, "\t\t}\n")
 			fmt.Fprint(w, "\t}\n")
-			fmt.Fprint(w, "\tsleep(1000000);\n")
+			if opts.Leak {
+				fmt.Fprint(w, "\twhile (1)\n")
+				fmt.Fprint(w, "\t\tcheck_leaks();\n")
+			} else {
+				fmt.Fprint(w, "\tsleep(1000000);\n")
+			}
 			fmt.Fprint(w, "\treturn 0;\n}\n")
 		}
 	}
@@ -211,6 +279,23 @@ func Write(p *prog.Prog, opts Options) ([]byte, error) {
 	return out1, nil
 }
 
+// generateSandbox runs the program in a sandboxed child process and waits for it to finish.
+// With Leak the parent process periodically scans for leaks while the child runs.
+func generateSandbox(w io.Writer, opts Options) {
+	if opts.Leak {
+		fmt.Fprint(w, "\tsetup_leak();\n")
+	}
+	fmt.Fprintf(w, "\tint pid = do_sandbox_%v(0, %v);\n", opts.Sandbox, opts.EnableTun)
+	fmt.Fprint(w, "\tint status = 0;\n")
+	if opts.Leak {
+		fmt.Fprint(w, "\twhile (waitpid(pid, &status, __WALL | WNOHANG) != pid)\n")
+		fmt.Fprint(w, "\t\tcheck_leaks();\n")
+		fmt.Fprint(w, "\tcheck_leaks();\n")
+	} else {
+		fmt.Fprint(w, "\twhile (waitpid(pid, &status, __WALL) != pid) {}\n")
+	}
+}
+
 func generateTestFunc(w io.Writer, opts Options, calls []string, name string) {
 	if !opts.Threaded && !opts.Collide {
 		fmt.Fprintf(w, "void %v()\n{\n", name)
@@ -231,6 +316,13 @@ func generateTestFunc(w io.Writer, opts Options, calls []string, name string) {
 		fmt.Fprintf(w, "\tswitch ((long)arg) {\n")
 		for i, c := range calls {
 			fmt.Fprintf(w, "\tcase %v:\n", i)
+			if opts.Race && opts.RaceAffinity && (i == opts.RaceCall1 || i == opts.RaceCall2) {
+				cpu := 0
+				if i == opts.RaceCall2 {
+					cpu = 1
+				}
+				fmt.Fprintf(w, "\t\trace_affinity(%v);\n", cpu)
+			}
 			fmt.Fprintf(w, "%s", strings.Replace(c, "\t", "\t\t", -1))
 			fmt.Fprintf(w, "\t\tbreak;\n")
 		}
@@ -253,8 +345,17 @@ func generateTestFunc(w io.Writer, opts Options, calls []string, name string) {
 			fmt.Fprintf(w, "\tsrand(getpid());\n")
 		}
 		fmt.Fprintf(w, "\tfor (i = 0; i < %v; i++) {\n", len(calls))
+		if opts.Race {
+			fmt.Fprintf(w, "\t\tif (i == %v)\n", opts.RaceCall2)
+			fmt.Fprintf(w, "\t\t\trace_delay(%v);\n", opts.RaceDelay)
+		}
 		fmt.Fprintf(w, "\t\tpthread_create(&th[i], 0, thr, (void*)i);\n")
-		fmt.Fprintf(w, "\t\tusleep(rand()%%10000);\n")
+		if opts.Race {
+			fmt.Fprintf(w, "\t\tif (i != %v)\n", opts.RaceCall1)
+			fmt.Fprintf(w, "\t\t\tusleep(rand()%%10000);\n")
+		} else {
+			fmt.Fprintf(w, "\t\tusleep(rand()%%10000);\n")
+		}
 		fmt.Fprintf(w, "\t}\n")
 		if opts.Collide {
 			fmt.Fprintf(w, "\tfor (i = 0; i < %v; i++) {\n", len(calls))
@@ -379,7 +480,7 @@ loop:
 			}
 			meta := target.Syscalls[instr]
 			emitCall := true
-			if meta.CallName == "syz_test" {
+			if meta.CallName == "syz_test" || meta.CallName == "syz_compare" {
 				emitCall = false
 			}
 			if !opts.EnableTun && (meta.CallName == "syz_emit_ethernet" || meta.CallName == "syz_extract_tcp_res") {
@@ -462,6 +563,15 @@ func preprocessCommonHeader(target *prog.Target, opts Options, handled map[strin
 	if opts.Fault {
 		defines = append(defines, "SYZ_FAULT_INJECTION")
 	}
+	if opts.Race {
+		defines = append(defines, "SYZ_RACE")
+	}
+	if opts.RaceAffinity {
+		defines = append(defines, "SYZ_RACE_AFFINITY")
+	}
+	if opts.Leak {
+		defines = append(defines, "SYZ_LEAK")
+	}
 	if opts.EnableTun {
 		defines = append(defines, "SYZ_TUN_ENABLE")
 	}
@@ -49,6 +49,11 @@ func enumerateField(opt Options, field int) []Options {
 		opts = append(opts, opt)
 	} else if fldName == "FaultNth" {
 		opts = append(opts, opt)
+	} else if fldName == "RaceCall1" || fldName == "RaceDelay" {
+		opts = append(opts, opt)
+	} else if fldName == "RaceCall2" {
+		fld.SetInt(1)
+		opts = append(opts, opt)
 	} else if fld.Kind() == reflect.Bool {
 		for _, v := range []bool{false, true} {
 			fld.SetBool(v)
@@ -179,3 +184,21 @@ func testOne(t *testing.T, p *prog.Prog, opts Options) {
 	}
 	defer os.Remove(bin)
 }
+
+func TestParseOptions(t *testing.T) {
+	for _, opts := range allOptionsPermutations() {
+		opts.RaceCall1, opts.RaceCall2, opts.RaceDelay = 1, 3, 100
+		got, err := ParseOptions([]byte(fmt.Sprintf("%+v", opts)))
+		if err != nil {
+			t.Fatalf("failed to parse %+v: %v", opts, err)
+		}
+		if got != opts {
+			t.Fatalf("parsed options differ:\n%+v\n%+v", opts, got)
+		}
+	}
+	for _, bad := range []string{"", "{Threaded}", "{Foo:true}", "{Procs:x}", "Threaded:true"} {
+		if _, err := ParseOptions([]byte(bad)); err == nil {
+			t.Errorf("parsing %q did not fail", bad)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"github.com/google/syzkaller/pkg/ipc"
+	"github.com/google/syzkaller/prog"
+)
+
+// CallStats are execution counters of a single syscall.
+type CallStats struct {
+	Execs     uint64
+	Errnos    map[int]uint64 // errno histogram, 0 means success
+	NewSignal uint64         // number of executions that gave new signal
+}
+
+// recordCallStats accounts execution results of calls of p.
+// Calls that were not executed and results of injected faults are not accounted.
+func (fuzzer *Fuzzer) recordCallStats(p *prog.Prog, info []ipc.CallInfo) {
+	fuzzer.callStatsMu.Lock()
+	defer fuzzer.callStatsMu.Unlock()
+	for i, inf := range info {
+		if inf.Errno == -1 || i >= len(p.Calls) {
+			continue
+		}
+		stats := fuzzer.callStatsLocked(p.Calls[i].Meta)
+		stats.Execs++
+		if !inf.FaultInjected {
+			stats.Errnos[inf.Errno]++
+		}
+	}
+}
+
+func (fuzzer *Fuzzer) recordCallNewSignal(call *prog.Syscall) {
+	fuzzer.callStatsMu.Lock()
+	defer fuzzer.callStatsMu.Unlock()
+	fuzzer.callStatsLocked(call).NewSignal++
+}
+
+func (fuzzer *Fuzzer) callStatsLocked(call *prog.Syscall) *CallStats {
+	stats := fuzzer.callStats[call.Name]
+	if stats == nil {
+		stats = &CallStats{Errnos: make(map[int]uint64)}
+		fuzzer.callStats[call.Name] = stats
+	}
+	return stats
+}
+
+// GrabCallStats returns per-syscall counters accumulated since the previous call.
+func (fuzzer *Fuzzer) GrabCallStats() map[string]*CallStats {
+	fuzzer.callStatsMu.Lock()
+	defer fuzzer.callStatsMu.Unlock()
+	stats := fuzzer.callStats
+	fuzzer.callStats = make(map[string]*CallStats)
+	return stats
+}
@@ -0,0 +1,103 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"math/rand"
+)
+
+// Fault injection into (call, nth) pairs that have already hit an exercised fault site
+// is repeated only with 1/faultRecheck probability (other programs may reach other sites with the same nth).
+const faultRecheck = 10
+
+// FaultSite is a kernel fault injection site.
+// The site is identified by coverage PC after which the fault path diverges from the normal one.
+type FaultSite struct {
+	PC   uint32
+	Call string // syscall that hit the site
+	Nth  int    // fault point of the syscall that hit the site
+	Hits uint64 // number of injections into the site since the previous GrabFaultSites
+}
+
+type faultKey struct {
+	call int // syscall ID
+	nth  int
+}
+
+type faultHitKey struct {
+	faultKey
+	pc uint32
+}
+
+// AddFaultSites merges fault sites exercised elsewhere (e.g. by other fuzzers) into known sites.
+// Fault points (Call, Nth) that hit the sites are then skipped as if they were exercised locally.
+func (fuzzer *Fuzzer) AddFaultSites(sites []FaultSite) {
+	fuzzer.faultMu.Lock()
+	defer fuzzer.faultMu.Unlock()
+	for _, site := range sites {
+		fuzzer.faultSites[site.PC] = struct{}{}
+		meta := fuzzer.target.SyscallMap[site.Call]
+		if meta == nil {
+			continue
+		}
+		key := faultKey{meta.ID, site.Nth}
+		if _, ok := fuzzer.faultCalls[key]; !ok {
+			fuzzer.faultCalls[key] = site.PC
+		}
+	}
+}
+
+// GrabFaultSites returns fault sites hit since the previous call.
+func (fuzzer *Fuzzer) GrabFaultSites() []FaultSite {
+	fuzzer.faultMu.Lock()
+	defer fuzzer.faultMu.Unlock()
+	var sites []FaultSite
+	for _, site := range fuzzer.faultHits {
+		sites = append(sites, *site)
+	}
+	fuzzer.faultHits = make(map[faultHitKey]*FaultSite)
+	return sites
+}
+
+// skipFault returns true if injecting nth fault into call is likely to hit an already exercised site
+// (the fault point has hit a site here or on other fuzzers).
+func (fuzzer *Fuzzer) skipFault(rnd *rand.Rand, call, nth int) bool {
+	fuzzer.faultMu.Lock()
+	defer fuzzer.faultMu.Unlock()
+	_, ok := fuzzer.faultCalls[faultKey{call, nth}]
+	return ok && rnd.Intn(faultRecheck) != 0
+}
+
+// recordFault records that injecting nth fault into call hit site pc, returns true if the site is new.
+func (fuzzer *Fuzzer) recordFault(call int, callName string, nth int, pc uint32) bool {
+	fuzzer.faultMu.Lock()
+	defer fuzzer.faultMu.Unlock()
+	key := faultKey{call, nth}
+	fuzzer.faultCalls[key] = pc
+	site := fuzzer.faultHits[faultHitKey{key, pc}]
+	if site == nil {
+		site = &FaultSite{PC: pc, Call: callName, Nth: nth}
+		fuzzer.faultHits[faultHitKey{key, pc}] = site
+	}
+	site.Hits++
+	if _, ok := fuzzer.faultSites[pc]; ok {
+		return false
+	}
+	fuzzer.faultSites[pc] = struct{}{}
+	return true
+}
+
+// faultSite returns the last PC of the common prefix of coverage traces of the call
+// executed without faults (base) and with an injected fault (trace).
+// Returns false if the traces do not diverge or have no common prefix.
+func faultSite(base, trace []uint32) (uint32, bool) {
+	n := 0
+	for n < len(base) && n < len(trace) && base[n] == trace[n] {
+		n++
+	}
+	if n == 0 || n == len(base) && n == len(trace) {
+		return 0, false
+	}
+	return trace[n-1], true
+}
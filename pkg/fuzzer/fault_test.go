@@ -0,0 +1,94 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestFaultSite(t *testing.T) {
+	tests := []struct {
+		base  []uint32
+		trace []uint32
+		site  uint32
+		ok    bool
+	}{
+		{[]uint32{1, 2, 3}, []uint32{1, 2, 10}, 2, true},
+		{[]uint32{1, 2, 3}, []uint32{1}, 1, true},
+		{[]uint32{1, 2}, []uint32{1, 2, 3}, 2, true},
+		{[]uint32{1, 2, 3}, []uint32{1, 2, 3}, 0, false},
+		{[]uint32{1, 2, 3}, []uint32{4, 5}, 0, false},
+		{nil, []uint32{4, 5}, 0, false},
+	}
+	for i, test := range tests {
+		site, ok := faultSite(test.base, test.trace)
+		if site != test.site || ok != test.ok {
+			t.Errorf("test #%v: got %v/%v, want %v/%v", i, site, ok, test.site, test.ok)
+		}
+	}
+}
+
+func TestFuzzerFaultSites(t *testing.T) {
+	exec := &testExecutor{faults: 3}
+	fuzzer, proc, _ := initTest(t, exec)
+	proc.rnd = rand.New(rand.NewSource(0))
+	data := []byte("mmap(&(0x7f0000000000/0x1000)=nil, 0x1000, 0x3, 0x32, 0xffffffffffffffff, 0x0)\n")
+	p, err := testTarget.Deserialize(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta := p.Calls[0].Meta
+	// The site is known, but it was hit by a different fault point on another fuzzer.
+	fuzzer.AddFaultSites([]FaultSite{{PC: uint32(meta.ID)<<8 | 1, Call: meta.Name, Nth: 10}})
+	proc.failCall(p, 0)
+	// 1 run without faults, 3 injected faults and 1 run that did not inject a fault.
+	if exec.execs != 5 {
+		t.Fatalf("executed %v programs, want 5", exec.execs)
+	}
+	sites := fuzzer.GrabFaultSites()
+	if len(sites) != 3 {
+		t.Fatalf("got %v fault sites, want 3: %+v", len(sites), sites)
+	}
+	for _, site := range sites {
+		if site.PC>>8 != uint32(meta.ID) || site.PC&0xff == 0 || site.PC&0xff > 3 ||
+			site.Call != meta.Name || site.Hits != 1 {
+			t.Fatalf("bad fault site %+v", site)
+		}
+	}
+	if n := fuzzer.GrabStats()[StatFaultSite.String()]; n != 2 {
+		t.Fatalf("got %v new fault sites, want 2 (one was known)", n)
+	}
+	// Now all fault points are known to hit exercised sites, so they are mostly skipped.
+	exec.execs = 0
+	proc.failCall(p, 0)
+	if exec.execs >= 5 {
+		t.Fatalf("known fault sites are not skipped: %v executions", exec.execs)
+	}
+}
+
+func TestFuzzerFaultSitesFromOtherFuzzers(t *testing.T) {
+	exec := &testExecutor{faults: 3}
+	fuzzer, proc, _ := initTest(t, exec)
+	proc.rnd = rand.New(rand.NewSource(0))
+	data := []byte("mmap(&(0x7f0000000000/0x1000)=nil, 0x1000, 0x3, 0x32, 0xffffffffffffffff, 0x0)\n")
+	p, err := testTarget.Deserialize(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta := p.Calls[0].Meta
+	var sites []FaultSite
+	for nth := 0; nth < 3; nth++ {
+		sites = append(sites, FaultSite{PC: uint32(meta.ID)<<8 | uint32(nth+1), Call: meta.Name, Nth: nth})
+	}
+	fuzzer.AddFaultSites(sites)
+	// All fault points are known to hit sites exercised by other fuzzers, so they are mostly skipped.
+	proc.failCall(p, 0)
+	if exec.execs >= 5 {
+		t.Fatalf("fault sites exercised by other fuzzers are not skipped: %v executions", exec.execs)
+	}
+	if n := fuzzer.GrabStats()[StatFaultSite.String()]; n != 0 {
+		t.Fatalf("got %v new fault sites, want 0", n)
+	}
+}
@@ -0,0 +1,539 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// Package fuzzer contains the fuzzing engine: corpus, feedback signal and work queues
+// (candidates, triage, smashing) and the fuzzing loop that drives an executor.
+// The engine does not know how programs are executed nor how it talks to the manager,
+// these are provided by the user via Executor and Manager interfaces.
+package fuzzer
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+
+	"github.com/google/syzkaller/pkg/cover"
+	"github.com/google/syzkaller/pkg/hash"
+	"github.com/google/syzkaller/pkg/ipc"
+	"github.com/google/syzkaller/prog"
+)
+
+const (
+	programLength = 30
+	// With adaptive schedule smashing queues are consumed only when the scheduler chooses
+	// the corresponding strategy, so they are capped and the oldest inputs are dropped.
+	maxSmashQueue = 1000
+)
+
+// Executor executes programs on behalf of a single Proc.
+type Executor interface {
+	// Exec executes program p and returns per-call execution info.
+	// It returns nil if the results must be ignored (e.g. executor has detected a kernel bug).
+	Exec(opts *ipc.ExecOpts, p *prog.Prog) []ipc.CallInfo
+}
+
+// Manager is the fuzzer's connection to the outside world (normally syz-manager over RPC).
+type Manager interface {
+	// NewInput is called when the fuzzer adds a new (triaged and minimized) input to the corpus.
+	NewInput(inp Input) error
+	// NeedCandidates is called when the fuzzer runs low on candidate programs,
+	// the manager should supply more with Fuzzer.AddCandidate if it has any.
+	NeedCandidates()
+}
+
+// Input is a corpus program along with its signal and coverage.
+type Input struct {
+	Call   string
+	Prog   []byte
+	Signal []uint32
+	Cover  []uint32
+	// Power schedule counters, see InputStats.
+	Chosen uint64
+	Found  uint64
+}
+
+type Config struct {
+	Target         *prog.Target
+	ChoiceTable    *prog.ChoiceTable
+	Manager        Manager
+	Procs          int  // number of procs, used to decide when we need more candidates
+	NoCover        bool // executor does not return signal, all programs are added to corpus as is
+	FaultInjection bool // smash inputs with fault injection
+	Comps          bool // smash inputs with comparison hints
+	Race           bool // race pairs of calls that share coverage or resources (requires threaded executor)
+
+	Schedule string // how to choose between fuzzing strategies (see Schedules), empty means fixed
+	Power    string // how to choose corpus programs for mutation (see PowerSchedules), empty means uniform
+}
+
+type Fuzzer struct {
+	target  *prog.Target
+	manager Manager
+	procs   int
+	noCover bool
+	fault   bool
+	comps   bool
+	race    bool
+
+	adaptive bool
+	sched    scheduler
+	paused   uint32 // accessed atomically
+
+	ctMu    sync.RWMutex
+	ct      *prog.ChoiceTable
+	enabled map[*prog.Syscall]bool // nil if all calls are enabled
+
+	signalMu     sync.RWMutex
+	corpusSignal map[uint32]struct{} // signal of inputs in corpus
+	maxSignal    map[uint32]struct{} // max signal ever observed including flakes
+	newSignal    map[uint32]struct{} // diff of maxSignal since last GrabNewSignal
+	noisySignal  map[uint32]struct{} // signal excluded from novelty checks
+	flakySignal  map[uint32]uint64   // signal that was flaky in triage since last GrabFlakySignal
+
+	corpusMu     sync.RWMutex
+	corpus       []*prog.Prog
+	corpusInputs []*corpusInput // metadata of programs in corpus, in the same order
+	corpusHashes map[hash.Sig]*corpusInput
+	signalFreq   map[uint32]uint32 // number of corpus inputs with the signal
+	chooser      *chooser          // nil for uniform power schedule without focus
+	focus        map[uint32]float32
+
+	queueMu         sync.RWMutex
+	triage          []workInput
+	triageCandidate []workInput
+	candidates      []candidate
+	smashQueue      []workInput
+	// Only with adaptive schedule, with fixed schedule inputs are smashed with all strategies at once.
+	hintQueue  []workInput
+	faultQueue []workInput
+
+	faultMu    sync.Mutex
+	faultSites map[uint32]struct{}        // fault sites exercised by all fuzzers
+	faultCalls map[faultKey]uint32        // last fault site hit by (call, nth) here or on other fuzzers
+	faultHits  map[faultHitKey]*FaultSite // diff since last GrabFaultSites
+
+	callStatsMu sync.Mutex
+	callStats   map[string]*CallStats // per-syscall stats since last GrabCallStats
+
+	raceMu    sync.Mutex
+	racePairs map[racePairKey]*RacePair // raced pairs since last GrabRacePairs
+
+	stats [StatCount]uint64
+}
+
+// workInput is a program that gives new signal in call.
+type workInput struct {
+	p         *prog.Prog
+	call      int
+	signal    []uint32
+	minimized bool
+	input     *corpusInput  // set if the program is already in corpus
+	opts      *ipc.ExecOpts // options the signal was obtained with (e.g. racing calls), nil for default
+}
+
+type candidate struct {
+	p         *prog.Prog
+	minimized bool
+}
+
+type Stat int
+
+const (
+	StatGenerate Stat = iota
+	StatFuzz
+	StatCandidate
+	StatTriage
+	StatMinimize
+	StatSmash
+	StatHint
+	StatSeed
+	StatRace
+	StatNewInput
+	StatFaultSite
+	StatRaceSignal
+	StatDisabled
+	StatCount
+)
+
+var statNames = [StatCount]string{
+	StatGenerate:   "exec gen",
+	StatFuzz:       "exec fuzz",
+	StatCandidate:  "exec candidate",
+	StatTriage:     "exec triage",
+	StatMinimize:   "exec minimize",
+	StatSmash:      "exec smash",
+	StatHint:       "exec hints",
+	StatSeed:       "exec seeds",
+	StatRace:       "exec race",
+	StatNewInput:   "fuzzer new inputs",
+	StatFaultSite:  "fuzzer new fault sites",
+	StatRaceSignal: "fuzzer races with new signal",
+	StatDisabled:   "fuzzer skipped disabled calls",
+}
+
+func (stat Stat) String() string {
+	return statNames[stat]
+}
+
+func NewFuzzer(cfg *Config) (*Fuzzer, error) {
+	switch cfg.Schedule {
+	case "", ScheduleFixed, ScheduleAdaptive:
+	default:
+		return nil, fmt.Errorf("unknown schedule %q, supported: %v", cfg.Schedule, Schedules)
+	}
+	power := powerFuncs[cfg.Power]
+	if power == nil && cfg.Power != "" {
+		return nil, fmt.Errorf("unknown power schedule %q, supported: %v", cfg.Power, PowerSchedules)
+	}
+	fuzzer := &Fuzzer{
+		target:       cfg.Target,
+		ct:           cfg.ChoiceTable,
+		manager:      cfg.Manager,
+		procs:        cfg.Procs,
+		noCover:      cfg.NoCover,
+		fault:        cfg.FaultInjection,
+		comps:        cfg.Comps,
+		race:         cfg.Race,
+		corpusSignal: make(map[uint32]struct{}),
+		maxSignal:    make(map[uint32]struct{}),
+		newSignal:    make(map[uint32]struct{}),
+		noisySignal:  make(map[uint32]struct{}),
+		flakySignal:  make(map[uint32]uint64),
+		corpusHashes: make(map[hash.Sig]*corpusInput),
+		signalFreq:   make(map[uint32]uint32),
+		faultSites:   make(map[uint32]struct{}),
+		faultCalls:   make(map[faultKey]uint32),
+		faultHits:    make(map[faultHitKey]*FaultSite),
+		callStats:    make(map[string]*CallStats),
+		racePairs:    make(map[racePairKey]*RacePair),
+		adaptive:     cfg.Schedule == ScheduleAdaptive,
+	}
+	if power != nil && cfg.Power != PowerUniform {
+		fuzzer.chooser = &chooser{power: power}
+	}
+	return fuzzer, nil
+}
+
+// AddInput adds an input received from manager to corpus.
+// Power schedule counters of the input are restored from inp.
+func (fuzzer *Fuzzer) AddInput(inp Input) error {
+	p, err := fuzzer.target.Deserialize(inp.Prog)
+	if err != nil {
+		return fmt.Errorf("failed to deserialize input: %v", err)
+	}
+	ci := fuzzer.addInputToCorpus(p, hash.Hash(inp.Prog), inp.Signal, inp.Cover)
+	atomic.StoreUint64(&ci.chosen, inp.Chosen)
+	atomic.StoreUint64(&ci.found, inp.Found)
+	if fuzzer.noCover {
+		return nil
+	}
+	fuzzer.signalMu.Lock()
+	if diff := cover.SignalDiff(fuzzer.maxSignal, inp.Signal); len(diff) != 0 {
+		cover.SignalAdd(fuzzer.corpusSignal, diff)
+		cover.SignalAdd(fuzzer.maxSignal, diff)
+	}
+	fuzzer.signalMu.Unlock()
+	return nil
+}
+
+// AddCandidate queues a candidate program for execution and triage
+// (or adds it to corpus directly if coverage is disabled).
+func (fuzzer *Fuzzer) AddCandidate(data []byte, minimized bool) error {
+	p, err := fuzzer.target.Deserialize(data)
+	if err != nil {
+		return fmt.Errorf("failed to deserialize candidate: %v", err)
+	}
+	if fuzzer.noCover {
+		fuzzer.addInputToCorpus(p, hash.Hash(data), nil, nil)
+	} else {
+		fuzzer.queueMu.Lock()
+		fuzzer.candidates = append(fuzzer.candidates, candidate{p, minimized})
+		fuzzer.queueMu.Unlock()
+	}
+	return nil
+}
+
+// CandidateCount returns number of candidates that are not yet executed.
+func (fuzzer *Fuzzer) CandidateCount() int {
+	fuzzer.queueMu.RLock()
+	defer fuzzer.queueMu.RUnlock()
+	return len(fuzzer.candidates)
+}
+
+// AddMaxSignal merges signal observed elsewhere (e.g. by other fuzzers) into max signal.
+func (fuzzer *Fuzzer) AddMaxSignal(signal []uint32) {
+	if len(signal) == 0 {
+		return
+	}
+	fuzzer.signalMu.Lock()
+	defer fuzzer.signalMu.Unlock()
+	for _, s := range signal {
+		fuzzer.maxSignal[s] = struct{}{}
+	}
+}
+
+// AddNoisySignal excludes signal from novelty checks: programs that give only noisy
+// new signal are not triaged and noisy signal is not reported in inputs.
+func (fuzzer *Fuzzer) AddNoisySignal(signal []uint32) {
+	if len(signal) == 0 {
+		return
+	}
+	fuzzer.signalMu.Lock()
+	defer fuzzer.signalMu.Unlock()
+	for _, s := range signal {
+		fuzzer.noisySignal[s] = struct{}{}
+		fuzzer.maxSignal[s] = struct{}{}
+		fuzzer.corpusSignal[s] = struct{}{}
+	}
+}
+
+// GrabFlakySignal returns how many times each signal was flaky during triage since the previous call.
+func (fuzzer *Fuzzer) GrabFlakySignal() map[uint32]uint64 {
+	fuzzer.signalMu.Lock()
+	defer fuzzer.signalMu.Unlock()
+	flaky := fuzzer.flakySignal
+	fuzzer.flakySignal = make(map[uint32]uint64)
+	return flaky
+}
+
+// GrabNewSignal returns max signal observed since the previous call.
+func (fuzzer *Fuzzer) GrabNewSignal() []uint32 {
+	fuzzer.signalMu.Lock()
+	defer fuzzer.signalMu.Unlock()
+	signal := make([]uint32, 0, len(fuzzer.newSignal))
+	for s := range fuzzer.newSignal {
+		signal = append(signal, s)
+	}
+	fuzzer.newSignal = make(map[uint32]struct{})
+	return signal
+}
+
+// GrabStats returns non-zero stats accumulated since the previous call.
+func (fuzzer *Fuzzer) GrabStats() map[string]uint64 {
+	stats := make(map[string]uint64)
+	for stat := range fuzzer.stats {
+		if v := atomic.SwapUint64(&fuzzer.stats[stat], 0); v != 0 {
+			stats[Stat(stat).String()] = v
+		}
+	}
+	fuzzer.sched.grabStats(stats)
+	return stats
+}
+
+// SetFocus sets directed fuzzing weights of coverage PCs (from 0 to 1, 1 for the focus targets).
+// Corpus inputs with coverage close to the targets are chosen for mutation more frequently.
+// Weights of inputs that are already in corpus are recomputed.
+func (fuzzer *Fuzzer) SetFocus(weights map[uint32]float32) {
+	fuzzer.corpusMu.Lock()
+	defer fuzzer.corpusMu.Unlock()
+	fuzzer.focus = weights
+	for _, inp := range fuzzer.corpusInputs {
+		inp.focus = focusWeight(weights, inp.cover)
+	}
+	if fuzzer.chooser == nil {
+		fuzzer.chooser = &chooser{power: powerFuncs[PowerUniform]}
+	}
+	fuzzer.chooser.reset()
+}
+
+// SetEnabledCalls changes the set of enabled syscalls at runtime. New programs are generated
+// and mutated with the choice table ct, programs that contain calls that are not in enabled
+// (e.g. candidates and corpus inputs) are not executed.
+func (fuzzer *Fuzzer) SetEnabledCalls(ct *prog.ChoiceTable, enabled map[*prog.Syscall]bool) {
+	fuzzer.ctMu.Lock()
+	defer fuzzer.ctMu.Unlock()
+	fuzzer.ct = ct
+	fuzzer.enabled = enabled
+}
+
+func (fuzzer *Fuzzer) choiceTable() *prog.ChoiceTable {
+	fuzzer.ctMu.RLock()
+	defer fuzzer.ctMu.RUnlock()
+	return fuzzer.ct
+}
+
+// callsEnabled returns whether all calls of p are enabled.
+func (fuzzer *Fuzzer) callsEnabled(p *prog.Prog) bool {
+	fuzzer.ctMu.RLock()
+	defer fuzzer.ctMu.RUnlock()
+	if fuzzer.enabled == nil {
+		return true
+	}
+	for _, c := range p.Calls {
+		if !fuzzer.enabled[c.Meta] {
+			return false
+		}
+	}
+	return true
+}
+
+// SetPaused pauses or resumes all procs. Paused procs don't execute programs.
+func (fuzzer *Fuzzer) SetPaused(paused bool) {
+	v := uint32(0)
+	if paused {
+		v = 1
+	}
+	atomic.StoreUint32(&fuzzer.paused, v)
+}
+
+// Paused returns whether procs are paused.
+func (fuzzer *Fuzzer) Paused() bool {
+	return atomic.LoadUint32(&fuzzer.paused) != 0
+}
+
+// GrabInputStats returns power schedule counters of corpus inputs accumulated since the previous call.
+func (fuzzer *Fuzzer) GrabInputStats() []InputStats {
+	fuzzer.corpusMu.RLock()
+	defer fuzzer.corpusMu.RUnlock()
+	var stats []InputStats
+	for _, inp := range fuzzer.corpusInputs {
+		chosen := atomic.SwapUint64(&inp.statChosen, 0)
+		found := atomic.SwapUint64(&inp.statFound, 0)
+		if chosen != 0 || found != 0 {
+			stats = append(stats, InputStats{inp.sig, chosen, found})
+		}
+	}
+	return stats
+}
+
+// addInputToCorpus adds program p to corpus if it is not there yet,
+// returns metadata of the corpus input.
+func (fuzzer *Fuzzer) addInputToCorpus(p *prog.Prog, sig hash.Sig, signal, cov []uint32) *corpusInput {
+	fuzzer.corpusMu.Lock()
+	defer fuzzer.corpusMu.Unlock()
+	if inp := fuzzer.corpusHashes[sig]; inp != nil {
+		return inp
+	}
+	inp := &corpusInput{
+		p:      p,
+		sig:    sig,
+		signal: signal,
+		cover:  cov,
+		focus:  focusWeight(fuzzer.focus, cov),
+	}
+	fuzzer.corpus = append(fuzzer.corpus, p)
+	fuzzer.corpusInputs = append(fuzzer.corpusInputs, inp)
+	fuzzer.corpusHashes[sig] = inp
+	for _, s := range signal {
+		fuzzer.signalFreq[s]++
+	}
+	return inp
+}
+
+// focusWeight returns max focus weight of coverage cov.
+func focusWeight(weights map[uint32]float32, cov []uint32) float64 {
+	focus := 0.0
+	for _, pc := range cov {
+		if w := float64(weights[pc]); w > focus {
+			focus = w
+		}
+	}
+	return focus
+}
+
+// chooseInput chooses a corpus program for mutation according to the power schedule.
+// Returns nil if corpus is empty.
+func (fuzzer *Fuzzer) chooseInput(rnd *rand.Rand) *corpusInput {
+	fuzzer.corpusMu.RLock()
+	defer fuzzer.corpusMu.RUnlock()
+	inputs := fuzzer.corpusInputs
+	if len(inputs) == 0 {
+		return nil
+	}
+	if fuzzer.chooser == nil {
+		return inputs[rnd.Intn(len(inputs))]
+	}
+	return fuzzer.chooser.choose(rnd, inputs, fuzzer.signalFreq)
+}
+
+func (fuzzer *Fuzzer) corpusSnapshot() []*prog.Prog {
+	fuzzer.corpusMu.RLock()
+	defer fuzzer.corpusMu.RUnlock()
+	return fuzzer.corpus
+}
+
+// checkNewSignal adds new signal of all calls in info to max signal
+// and queues the corresponding programs for triage. Returns amount of new signal.
+// opts are non-default execution options that info was obtained with (nil for default),
+// triage and minimization use the same options, otherwise the signal would not reproduce.
+func (fuzzer *Fuzzer) checkNewSignal(p *prog.Prog, info []ipc.CallInfo, opts *ipc.ExecOpts,
+	minimized, isCandidate bool) uint64 {
+	newSignal := uint64(0)
+	fuzzer.signalMu.RLock()
+	defer fuzzer.signalMu.RUnlock()
+	for i, inf := range info {
+		if !cover.SignalNew(fuzzer.maxSignal, inf.Signal) {
+			continue
+		}
+		diff := cover.SignalDiff(fuzzer.maxSignal, inf.Signal)
+		newSignal += uint64(len(diff))
+		fuzzer.recordCallNewSignal(p.Calls[i].Meta)
+
+		fuzzer.signalMu.RUnlock()
+		fuzzer.signalMu.Lock()
+		cover.SignalAdd(fuzzer.maxSignal, diff)
+		cover.SignalAdd(fuzzer.newSignal, diff)
+		fuzzer.signalMu.Unlock()
+		fuzzer.signalMu.RLock()
+
+		inp := workInput{
+			p:         p.Clone(),
+			call:      i,
+			signal:    append([]uint32{}, inf.Signal...),
+			minimized: minimized,
+		}
+		if opts != nil {
+			inp.opts = new(ipc.ExecOpts)
+			*inp.opts = *opts
+		}
+		fuzzer.queueMu.Lock()
+		if isCandidate {
+			fuzzer.triageCandidate = append(fuzzer.triageCandidate, inp)
+		} else {
+			fuzzer.triage = append(fuzzer.triage, inp)
+		}
+		fuzzer.queueMu.Unlock()
+	}
+	return newSignal
+}
+
+// queueSmash queues a new corpus input for smashing.
+func (fuzzer *Fuzzer) queueSmash(inp workInput) {
+	fuzzer.queueMu.Lock()
+	defer fuzzer.queueMu.Unlock()
+	if !fuzzer.adaptive {
+		fuzzer.smashQueue = append(fuzzer.smashQueue, inp)
+		return
+	}
+	fuzzer.smashQueue = appendBounded(fuzzer.smashQueue, inp)
+	if fuzzer.comps {
+		fuzzer.hintQueue = appendBounded(fuzzer.hintQueue, inp)
+	}
+	if fuzzer.fault {
+		fuzzer.faultQueue = appendBounded(fuzzer.faultQueue, inp)
+	}
+}
+
+// appendBounded appends inp to the queue q dropping the oldest input if q has maxSmashQueue inputs.
+func appendBounded(q []workInput, inp workInput) []workInput {
+	if len(q) < maxSmashQueue {
+		return append(q, inp)
+	}
+	copy(q, q[1:])
+	q[len(q)-1] = inp
+	return q
+}
+
+// popQueue removes the last input from the queue q, returns false if the queue is empty.
+func (fuzzer *Fuzzer) popQueue(q *[]workInput) (workInput, bool) {
+	fuzzer.queueMu.Lock()
+	defer fuzzer.queueMu.Unlock()
+	if len(*q) == 0 {
+		return workInput{}, false
+	}
+	last := len(*q) - 1
+	inp := (*q)[last]
+	*q = (*q)[:last]
+	return inp, true
+}
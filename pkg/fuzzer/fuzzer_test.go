@@ -0,0 +1,413 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/pkg/ipc"
+	"github.com/google/syzkaller/prog"
+	_ "github.com/google/syzkaller/sys"
+)
+
+// testExecutor pretends that every syscall produces own stable signal.
+type testExecutor struct {
+	flaky  *rand.Rand // if set, signal is random
+	broken bool       // executor detects a bug in every program
+	faults int        // number of fault points in every call
+	execs  int
+}
+
+func callSignal(c *prog.Syscall) []uint32 {
+	return []uint32{uint32(c.ID)<<8 | 1, uint32(c.ID)<<8 | 2}
+}
+
+func (exec *testExecutor) Exec(opts *ipc.ExecOpts, p *prog.Prog) []ipc.CallInfo {
+	exec.execs++
+	if exec.broken {
+		return nil
+	}
+	info := make([]ipc.CallInfo, len(p.Calls))
+	for i, c := range p.Calls {
+		info[i].Signal = callSignal(c.Meta)
+		if exec.flaky != nil {
+			info[i].Signal = []uint32{exec.flaky.Uint32()}
+		}
+		if opts.Flags&ipc.FlagCollectCover != 0 {
+			info[i].Cover = info[i].Signal
+		}
+	}
+	if opts.Flags&ipc.FlagRace != 0 && opts.RaceCall2 < len(info) {
+		// Racing calls give signal that depends on the pair.
+		info[opts.RaceCall2].Signal = append(info[opts.RaceCall2].Signal,
+			0xf0000000|uint32(opts.RaceCall1)<<8|uint32(opts.RaceCall2))
+	}
+	if exec.faults != 0 && opts.Flags&ipc.FlagCollectCover != 0 {
+		// Coverage trace of calls is 1, 2, ..., faults+1, fault path diverges after nth PC.
+		for i, c := range p.Calls {
+			trace := make([]uint32, exec.faults+1)
+			for j := range trace {
+				trace[j] = uint32(c.Meta.ID)<<8 | uint32(j+1)
+			}
+			if opts.Flags&ipc.FlagInjectFault != 0 && opts.FaultCall == i && opts.FaultNth < exec.faults {
+				trace = append(trace[:opts.FaultNth+1], uint32(c.Meta.ID)<<8|0x80)
+				info[i].FaultInjected = true
+			}
+			info[i].Cover = trace
+		}
+	}
+	return info
+}
+
+type testManager struct {
+	mu             sync.Mutex
+	inputs         []Input
+	needCandidates int
+}
+
+func (mgr *testManager) NewInput(inp Input) error {
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	mgr.inputs = append(mgr.inputs, inp)
+	return nil
+}
+
+func (mgr *testManager) NeedCandidates() {
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	mgr.needCandidates++
+}
+
+var (
+	testTargetOnce sync.Once
+	testTarget     *prog.Target
+	testCT         *prog.ChoiceTable
+)
+
+func initTest(t *testing.T, exec *testExecutor) (*Fuzzer, *Proc, *testManager) {
+	return initTestSchedule(t, exec, ScheduleFixed)
+}
+
+func initTestSchedule(t *testing.T, exec *testExecutor, schedule string) (*Fuzzer, *Proc, *testManager) {
+	testTargetOnce.Do(func() {
+		target, err := prog.GetTarget("linux", runtime.GOARCH)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testTarget = target
+		testCT = target.BuildChoiceTable(target.CalculatePriorities(nil), nil)
+	})
+	if testTarget == nil {
+		t.Fatal("failed to init target")
+	}
+	mgr := new(testManager)
+	fuzzer, err := NewFuzzer(&Config{
+		Target:         testTarget,
+		ChoiceTable:    testCT,
+		Manager:        mgr,
+		Procs:          1,
+		FaultInjection: true,
+		Comps:          true,
+		Schedule:       schedule,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	seed := time.Now().UnixNano()
+	t.Logf("seed=%v", seed)
+	proc := fuzzer.NewProc(0, exec, rand.NewSource(seed))
+	return fuzzer, proc, mgr
+}
+
+func TestFuzzerTriage(t *testing.T) {
+	exec := new(testExecutor)
+	fuzzer, proc, mgr := initTest(t, exec)
+	iters := 100
+	if testing.Short() {
+		iters = 30
+	}
+	for i := 0; i < iters; i++ {
+		proc.Step()
+	}
+	if len(mgr.inputs) == 0 {
+		t.Fatalf("no new inputs after %v steps", iters)
+	}
+	calls := make(map[*prog.Syscall]bool)
+	for _, inp := range mgr.inputs {
+		p, err := testTarget.Deserialize(inp.Prog)
+		if err != nil {
+			t.Fatalf("failed to deserialize input: %v\n%s", err, inp.Prog)
+		}
+		// All other calls are not needed for the signal, so minimization must remove them
+		// (except for the mmap call that minimization always leaves).
+		meta := p.Calls[len(p.Calls)-1].Meta
+		if len(p.Calls) > 2 || meta.CallName != inp.Call {
+			t.Fatalf("input for %v is not minimized:\n%s", inp.Call, inp.Prog)
+		}
+		if calls[meta] {
+			t.Errorf("duplicate input for %v", meta.Name)
+		}
+		calls[meta] = true
+		for _, s := range callSignal(meta) {
+			if _, ok := fuzzer.corpusSignal[s]; !ok {
+				t.Errorf("signal of %v is not in corpus signal", inp.Call)
+			}
+		}
+	}
+	if len(fuzzer.corpus) != len(mgr.inputs) {
+		t.Errorf("corpus has %v programs, manager received %v inputs", len(fuzzer.corpus), len(mgr.inputs))
+	}
+	stats := fuzzer.GrabStats()
+	if stats[StatGenerate.String()] == 0 || stats[StatTriage.String()] == 0 || stats[StatMinimize.String()] == 0 {
+		t.Errorf("missing exec stats: %+v", stats)
+	}
+	if got, want := stats[StatNewInput.String()], uint64(len(mgr.inputs)); got != want {
+		t.Errorf("new input stat %v, want %v", got, want)
+	}
+	var total uint64
+	for stat := StatGenerate; stat < StatNewInput; stat++ {
+		total += stats[stat.String()]
+	}
+	if total != uint64(exec.execs) {
+		t.Errorf("exec stats sum up to %v, want %v", total, exec.execs)
+	}
+	if stats = fuzzer.GrabStats(); len(stats) != 0 {
+		t.Errorf("stats are not reset: %+v", stats)
+	}
+	if len(fuzzer.GrabNewSignal()) == 0 {
+		t.Errorf("no new max signal")
+	}
+	if signal := fuzzer.GrabNewSignal(); len(signal) != 0 {
+		t.Errorf("new signal is not reset: %v", signal)
+	}
+}
+
+func TestFuzzerCandidate(t *testing.T) {
+	fuzzer, proc, mgr := initTest(t, new(testExecutor))
+	data := []byte("mmap(&(0x7f0000000000/0x1000)=nil, 0x1000, 0x3, 0x32, 0xffffffffffffffff, 0x0)\n")
+	if err := fuzzer.AddCandidate(data, true); err != nil {
+		t.Fatal(err)
+	}
+	if n := fuzzer.CandidateCount(); n != 1 {
+		t.Fatalf("candidate count %v, want 1", n)
+	}
+	proc.Step() // execute the candidate
+	if n := fuzzer.CandidateCount(); n != 0 {
+		t.Fatalf("candidate count %v, want 0", n)
+	}
+	if mgr.needCandidates != 1 {
+		t.Fatalf("manager was not asked for more candidates")
+	}
+	proc.Step() // triage the candidate
+	if len(mgr.inputs) != 1 || string(mgr.inputs[0].Prog) != string(data) {
+		t.Fatalf("candidate was not added to corpus as is: %+v", mgr.inputs)
+	}
+	if len(fuzzer.smashQueue) != 0 {
+		t.Fatalf("minimized candidate is queued for smashing")
+	}
+	// Candidate inputs are already known to manager.
+	if err := fuzzer.AddInput(mgr.inputs[0]); err != nil {
+		t.Fatal(err)
+	}
+	if len(fuzzer.corpus) != 1 {
+		t.Fatalf("corpus has %v programs, want 1", len(fuzzer.corpus))
+	}
+}
+
+func TestFuzzerEnabledCalls(t *testing.T) {
+	exec := new(testExecutor)
+	fuzzer, proc, mgr := initTest(t, exec)
+	enabled := map[*prog.Syscall]bool{
+		testTarget.MmapSyscall:          true,
+		testTarget.SyscallMap["getpid"]: true,
+	}
+	ct := testTarget.BuildChoiceTable(testTarget.CalculatePriorities(nil), enabled)
+	fuzzer.SetEnabledCalls(ct, enabled)
+	if err := fuzzer.AddCandidate([]byte("getuid()\n"), true); err != nil {
+		t.Fatal(err)
+	}
+	proc.Step()
+	if exec.execs != 0 {
+		t.Fatalf("executed a candidate with a disabled call")
+	}
+	if stats := fuzzer.GrabStats(); stats[StatDisabled.String()] != 1 {
+		t.Fatalf("disabled stat %v, want 1", stats[StatDisabled.String()])
+	}
+	for i := 0; i < 100; i++ {
+		proc.Step()
+	}
+	if exec.execs == 0 {
+		t.Fatalf("no programs executed")
+	}
+	for _, inp := range mgr.inputs {
+		if meta := testTarget.SyscallMap[inp.Call]; !enabled[meta] {
+			t.Fatalf("got input for disabled call %v", inp.Call)
+		}
+	}
+}
+
+func TestFuzzerPause(t *testing.T) {
+	exec := new(testExecutor)
+	fuzzer, proc, _ := initTest(t, exec)
+	fuzzer.SetPaused(true)
+	done := make(chan bool)
+	go func() {
+		proc.Step()
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatalf("paused proc executed a program")
+	case <-time.After(100 * time.Millisecond):
+	}
+	fuzzer.SetPaused(false)
+	<-done
+	if exec.execs != 1 {
+		t.Fatalf("executed %v programs, want 1", exec.execs)
+	}
+}
+
+func TestFuzzerMaxSignal(t *testing.T) {
+	fuzzer, proc, mgr := initTest(t, new(testExecutor))
+	var signal []uint32
+	for _, c := range testTarget.Syscalls {
+		signal = append(signal, callSignal(c)...)
+	}
+	fuzzer.AddMaxSignal(signal)
+	for i := 0; i < 10; i++ {
+		proc.Step()
+	}
+	if len(mgr.inputs) != 0 || len(fuzzer.GrabNewSignal()) != 0 {
+		t.Fatalf("got new inputs for known signal")
+	}
+}
+
+func TestFuzzerFlakySignal(t *testing.T) {
+	fuzzer, proc, mgr := initTest(t, &testExecutor{flaky: rand.New(rand.NewSource(0))})
+	for i := 0; i < 10; i++ {
+		proc.Step()
+	}
+	if len(mgr.inputs) != 0 {
+		t.Fatalf("flaky signal was added to corpus: %+v", mgr.inputs)
+	}
+	if len(fuzzer.GrabFlakySignal()) == 0 {
+		t.Fatalf("flaky signal is not reported")
+	}
+	if flaky := fuzzer.GrabFlakySignal(); len(flaky) != 0 {
+		t.Fatalf("flaky signal is not reset: %v", flaky)
+	}
+}
+
+func TestFuzzerNoisySignal(t *testing.T) {
+	fuzzer, proc, mgr := initTest(t, new(testExecutor))
+	// Every call has 2 signals, the first one is noisy.
+	var noisy []uint32
+	for _, c := range testTarget.Syscalls {
+		noisy = append(noisy, callSignal(c)[0])
+	}
+	fuzzer.AddNoisySignal(noisy)
+	for i := 0; i < 30; i++ {
+		proc.Step()
+	}
+	if len(mgr.inputs) == 0 {
+		t.Fatalf("no new inputs")
+	}
+	for _, inp := range mgr.inputs {
+		for _, s := range inp.Signal {
+			if s&0xff == 1 {
+				t.Fatalf("input for %v contains noisy signal", inp.Call)
+			}
+		}
+	}
+	for _, s := range fuzzer.GrabNewSignal() {
+		if s&0xff == 1 {
+			t.Fatalf("noisy signal is reported as new max signal")
+		}
+	}
+}
+
+func TestFuzzerBrokenExecutor(t *testing.T) {
+	fuzzer, proc, mgr := initTest(t, &testExecutor{broken: true})
+	for i := 0; i < 10; i++ {
+		proc.Step()
+	}
+	if len(mgr.inputs) != 0 || len(fuzzer.corpus) != 0 || len(fuzzer.GrabNewSignal()) != 0 {
+		t.Fatalf("results of a broken executor are used")
+	}
+}
+
+func TestFuzzerAdaptive(t *testing.T) {
+	exec := new(testExecutor)
+	fuzzer, proc, mgr := initTestSchedule(t, exec, ScheduleAdaptive)
+	for i := 0; i < 100; i++ {
+		proc.Step()
+	}
+	if len(mgr.inputs) == 0 {
+		t.Fatalf("no new inputs")
+	}
+	stats := fuzzer.GrabStats()
+	var total uint64
+	for arm := Arm(0); arm < ArmCount; arm++ {
+		total += stats["schedule "+arm.String()]
+	}
+	if total == 0 || stats["schedule generate"] == 0 {
+		t.Fatalf("no schedule stats: %v", stats)
+	}
+	// Triage and candidates are not accounted to arms.
+	if total >= uint64(exec.execs) {
+		t.Fatalf("arms executed %v programs, total executions %v", total, exec.execs)
+	}
+}
+
+func TestFuzzerAdaptiveQueues(t *testing.T) {
+	fuzzer, _, _ := initTestSchedule(t, new(testExecutor), ScheduleAdaptive)
+	for i := 0; i < maxSmashQueue+10; i++ {
+		fuzzer.queueSmash(workInput{call: i})
+	}
+	for _, q := range [][]workInput{fuzzer.smashQueue, fuzzer.hintQueue, fuzzer.faultQueue} {
+		if len(q) != maxSmashQueue {
+			t.Fatalf("queue has %v inputs, want %v", len(q), maxSmashQueue)
+		}
+		// The oldest inputs are dropped.
+		if q[0].call != 10 || q[len(q)-1].call != maxSmashQueue+9 {
+			t.Fatalf("bad queue: first %v, last %v", q[0].call, q[len(q)-1].call)
+		}
+	}
+}
+
+func TestFuzzerBadSchedule(t *testing.T) {
+	if _, err := NewFuzzer(&Config{Schedule: "foo"}); err == nil {
+		t.Fatalf("unknown schedule is accepted")
+	}
+}
+
+func TestFuzzerCallStats(t *testing.T) {
+	exec := new(testExecutor)
+	fuzzer, proc, _ := initTest(t, exec)
+	for i := 0; i < 30; i++ {
+		proc.Step()
+	}
+	stats := fuzzer.GrabCallStats()
+	if len(stats) == 0 {
+		t.Fatalf("no call stats")
+	}
+	var execs, newSignal uint64
+	for name, s := range stats {
+		if s.Errnos[0] != s.Execs {
+			t.Errorf("%v: %v executions, %v successful", name, s.Execs, s.Errnos[0])
+		}
+		execs += s.Execs
+		newSignal += s.NewSignal
+	}
+	if execs < uint64(exec.execs) || newSignal == 0 {
+		t.Errorf("bad call stats: %v call executions for %v programs, %v new signal", execs, exec.execs, newSignal)
+	}
+	if stats := fuzzer.GrabCallStats(); len(stats) != 0 {
+		t.Errorf("call stats are not reset")
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/google/syzkaller/pkg/hash"
+	"github.com/google/syzkaller/prog"
+)
+
+const (
+	// PowerUniform chooses all corpus programs with equal probability.
+	PowerUniform = "uniform"
+	// PowerFast is similar to AFL fast schedule: programs whose mutants found new signal
+	// get more energy, programs that were chosen many times without result get less,
+	// short programs are preferred.
+	PowerFast = "fast"
+	// PowerExplore prefers programs that were chosen the least number of times.
+	PowerExplore = "explore"
+	// PowerRarity prefers programs with signal that few other corpus programs have.
+	PowerRarity = "rarity"
+)
+
+var PowerSchedules = []string{PowerUniform, PowerFast, PowerExplore, PowerRarity}
+
+// powerFunc returns energy (non-normalized probability to be chosen) of a corpus input.
+// rarity is sum of 1/N over signal of the input, where N is number of corpus inputs with that signal.
+type powerFunc func(inp *corpusInput, rarity float64) float64
+
+var powerFuncs = map[string]powerFunc{
+	PowerUniform: func(inp *corpusInput, rarity float64) float64 {
+		return 1
+	},
+	PowerFast: func(inp *corpusInput, rarity float64) float64 {
+		chosen, found := inp.counters()
+		length := float64(programLength) / float64(programLength+len(inp.p.Calls))
+		return float64(1+found) / float64(1+chosen) * length
+	},
+	PowerExplore: func(inp *corpusInput, rarity float64) float64 {
+		chosen, _ := inp.counters()
+		return 1 / float64(1+chosen)
+	},
+	PowerRarity: func(inp *corpusInput, rarity float64) float64 {
+		return 1 + rarity
+	},
+}
+
+const (
+	// Energy of all inputs is recomputed after this number of choices,
+	// inputs added in between get energy computed when they are added.
+	powerRecompute = 1000
+	// Energy of inputs that cover focus targets is multiplied by 1+focusBoost,
+	// energy of inputs with coverage close to the targets proportionally less.
+	focusBoost = 10
+)
+
+// corpusInput is a corpus program along with the metadata used by power schedules.
+type corpusInput struct {
+	p      *prog.Prog
+	sig    hash.Sig
+	signal []uint32
+	cover  []uint32 // needed to recompute focus when focus weights change
+	focus  float64  // max focus weight of the input coverage
+	// Accessed atomically.
+	chosen uint64 // number of times the input was chosen for mutation
+	found  uint64 // amount of new signal found by mutants of the input
+	// Deltas since the previous GrabInputStats, accessed atomically.
+	statChosen uint64
+	statFound  uint64
+}
+
+func (inp *corpusInput) counters() (chosen, found uint64) {
+	return atomic.LoadUint64(&inp.chosen), atomic.LoadUint64(&inp.found)
+}
+
+// update accounts one choice of the input that gave found new signal.
+func (inp *corpusInput) update(found uint64) {
+	atomic.AddUint64(&inp.chosen, 1)
+	atomic.AddUint64(&inp.statChosen, 1)
+	if found != 0 {
+		atomic.AddUint64(&inp.found, found)
+		atomic.AddUint64(&inp.statFound, found)
+	}
+}
+
+// InputStats are power schedule counters of a corpus input.
+type InputStats struct {
+	Sig    hash.Sig
+	Chosen uint64
+	Found  uint64
+}
+
+// chooser chooses corpus inputs proportionally to energy given by the power function.
+type chooser struct {
+	mu      sync.Mutex
+	power   powerFunc
+	prefix  []float64 // prefix sums of energy of inputs
+	choices int       // choices since the last recompute
+}
+
+// reset forces recompute of energy of all inputs on the next choice.
+func (ch *chooser) reset() {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	ch.resetLocked()
+}
+
+func (ch *chooser) resetLocked() {
+	ch.choices = 0
+	ch.prefix = ch.prefix[:0]
+}
+
+// choose returns a random input, signalFreq maps signal to number of inputs that have it.
+// The caller must ensure that inputs and signalFreq are not modified concurrently.
+func (ch *chooser) choose(rnd *rand.Rand, inputs []*corpusInput, signalFreq map[uint32]uint32) *corpusInput {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	ch.choices++
+	if ch.choices >= powerRecompute {
+		ch.resetLocked()
+	}
+	for i := len(ch.prefix); i < len(inputs); i++ {
+		inp := inputs[i]
+		rarity := 0.0
+		for _, s := range inp.signal {
+			if n := signalFreq[s]; n != 0 {
+				rarity += 1 / float64(n)
+			}
+		}
+		total := 0.0
+		if i != 0 {
+			total = ch.prefix[i-1]
+		}
+		ch.prefix = append(ch.prefix, total+ch.power(inp, rarity)*(1+focusBoost*inp.focus))
+	}
+	total := ch.prefix[len(inputs)-1]
+	v := rnd.Float64() * total
+	idx := sort.Search(len(inputs), func(i int) bool { return ch.prefix[i] > v })
+	if idx == len(inputs) {
+		idx = len(inputs) - 1
+	}
+	return inputs[idx]
+}
@@ -0,0 +1,171 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/hash"
+	"github.com/google/syzkaller/prog"
+)
+
+func TestChooserRarity(t *testing.T) {
+	ch := &chooser{power: powerFuncs[PowerRarity]}
+	signalFreq := make(map[uint32]uint32)
+	var inputs []*corpusInput
+	for i := 0; i < 10; i++ {
+		// Input 0 has 10 unique signals, all other inputs share the same signal.
+		signal := []uint32{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+		if i != 0 {
+			signal = []uint32{100}
+		}
+		for _, s := range signal {
+			signalFreq[s]++
+		}
+		inputs = append(inputs, &corpusInput{p: new(prog.Prog), signal: signal})
+	}
+	rnd := rand.New(rand.NewSource(0))
+	counts := make(map[*corpusInput]int)
+	const iters = 10000
+	for i := 0; i < iters; i++ {
+		counts[ch.choose(rnd, inputs, signalFreq)]++
+	}
+	// Input 0 has energy 11, all other inputs have energy 1+1/9.
+	if n := counts[inputs[0]]; n < iters/2 {
+		t.Fatalf("input with rare signal is chosen %v times out of %v", n, iters)
+	}
+	for _, inp := range inputs[1:] {
+		if counts[inp] == 0 {
+			t.Fatalf("input is never chosen")
+		}
+	}
+}
+
+func TestChooserFast(t *testing.T) {
+	ch := &chooser{power: powerFuncs[PowerFast]}
+	var inputs []*corpusInput
+	for i := 0; i < 3; i++ {
+		inputs = append(inputs, &corpusInput{p: new(prog.Prog)})
+	}
+	inputs[0].update(100)
+	inputs[1].update(0)
+	rnd := rand.New(rand.NewSource(0))
+	counts := make(map[*corpusInput]int)
+	// Inputs added between recomputes must be choosable as well.
+	for i := 0; i < 2*powerRecompute; i++ {
+		n := 1 + i*len(inputs)/powerRecompute
+		if n > len(inputs) {
+			n = len(inputs)
+		}
+		counts[ch.choose(rnd, inputs[:n], nil)]++
+	}
+	if counts[inputs[0]] <= counts[inputs[1]] || counts[inputs[0]] <= counts[inputs[2]] {
+		t.Fatalf("productive input is not preferred: %v %v %v",
+			counts[inputs[0]], counts[inputs[1]], counts[inputs[2]])
+	}
+	if counts[inputs[1]] == 0 || counts[inputs[2]] == 0 {
+		t.Fatalf("input is never chosen")
+	}
+}
+
+func TestFuzzerInputStats(t *testing.T) {
+	fuzzer, proc, _ := initTest(t, new(testExecutor))
+	fuzzer.chooser = &chooser{power: powerFuncs[PowerExplore]}
+	data := []byte("mmap(&(0x7f0000000000/0x1000)=nil, 0x1000, 0x3, 0x32, 0xffffffffffffffff, 0x0)\n")
+	err := fuzzer.AddInput(Input{
+		Call:   "mmap",
+		Prog:   data,
+		Signal: []uint32{1},
+		Chosen: 10,
+		Found:  5,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	inp := fuzzer.corpusHashes[hash.Hash(data)]
+	if chosen, found := inp.counters(); chosen != 10 || found != 5 {
+		t.Fatalf("counters are not restored: chosen=%v found=%v", chosen, found)
+	}
+	if stats := fuzzer.GrabInputStats(); len(stats) != 0 {
+		t.Fatalf("restored counters are reported: %+v", stats)
+	}
+	// Steps that are not multiple of 100 mutate the only corpus program.
+	proc.iter = 1
+	for i := 0; i < 5; i++ {
+		proc.Step()
+	}
+	stats := fuzzer.GrabInputStats()
+	var chosen uint64
+	for _, stat := range stats {
+		if stat.Sig == inp.sig {
+			chosen = stat.Chosen
+		}
+	}
+	if chosen == 0 {
+		t.Fatalf("mutated input is not accounted: %+v", stats)
+	}
+	if total, _ := inp.counters(); total != 10+chosen {
+		t.Fatalf("chosen %v times, want %v", total, 10+chosen)
+	}
+	if stats := fuzzer.GrabInputStats(); len(stats) != 0 {
+		t.Fatalf("input stats are not reset: %+v", stats)
+	}
+}
+
+func TestFuzzerBadPower(t *testing.T) {
+	if _, err := NewFuzzer(&Config{Power: "foo"}); err == nil {
+		t.Fatalf("unknown power schedule is accepted")
+	}
+}
+
+func TestFuzzerFocus(t *testing.T) {
+	fuzzer, _, _ := initTest(t, new(testExecutor))
+	fuzzer.SetFocus(map[uint32]float32{100: 1, 200: 0.5})
+	var inputs []*corpusInput
+	for i, pc := range []uint32{100, 200, 300} {
+		data := []byte(fmt.Sprintf("mmap(&(0x7f0000000000/0x%x)=nil, 0x1000, 0x3, 0x32, 0xffffffffffffffff, 0x0)\n",
+			(i+1)*0x1000))
+		if err := fuzzer.AddInput(Input{Call: "mmap", Prog: data, Cover: []uint32{1, pc}}); err != nil {
+			t.Fatal(err)
+		}
+		inputs = append(inputs, fuzzer.corpusHashes[hash.Hash(data)])
+	}
+	if inputs[0].focus != 1 || inputs[1].focus != 0.5 || inputs[2].focus != 0 {
+		t.Fatalf("bad focus weights: %v %v %v", inputs[0].focus, inputs[1].focus, inputs[2].focus)
+	}
+	rnd := rand.New(rand.NewSource(0))
+	counts := make(map[*corpusInput]int)
+	for i := 0; i < 1000; i++ {
+		counts[fuzzer.chooseInput(rnd)]++
+	}
+	if counts[inputs[0]] <= counts[inputs[1]] || counts[inputs[1]] <= counts[inputs[2]] {
+		t.Fatalf("inputs close to focus are not preferred: %v %v %v",
+			counts[inputs[0]], counts[inputs[1]], counts[inputs[2]])
+	}
+}
+
+func TestFuzzerFocusExistingCorpus(t *testing.T) {
+	fuzzer, _, _ := initTest(t, new(testExecutor))
+	var inputs []*corpusInput
+	for i, pc := range []uint32{100, 200, 300} {
+		data := []byte(fmt.Sprintf("mmap(&(0x7f0000000000/0x%x)=nil, 0x1000, 0x3, 0x32, 0xffffffffffffffff, 0x0)\n",
+			(i+1)*0x1000))
+		p, err := testTarget.Deserialize(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		inputs = append(inputs, fuzzer.addInputToCorpus(p, hash.Hash(data), nil, []uint32{1, pc}))
+	}
+	// Focus weights normally arrive from manager after the corpus is loaded.
+	fuzzer.SetFocus(map[uint32]float32{100: 1, 200: 0.5})
+	if inputs[0].focus != 1 || inputs[1].focus != 0.5 || inputs[2].focus != 0 {
+		t.Fatalf("bad focus weights: %v %v %v", inputs[0].focus, inputs[1].focus, inputs[2].focus)
+	}
+	fuzzer.SetFocus(map[uint32]float32{300: 1})
+	if inputs[0].focus != 0 || inputs[1].focus != 0 || inputs[2].focus != 1 {
+		t.Fatalf("bad focus weights after update: %v %v %v", inputs[0].focus, inputs[1].focus, inputs[2].focus)
+	}
+}
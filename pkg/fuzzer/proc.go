@@ -0,0 +1,466 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/syzkaller/pkg/cover"
+	"github.com/google/syzkaller/pkg/hash"
+	"github.com/google/syzkaller/pkg/ipc"
+	. "github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/prog"
+)
+
+// pausePeriod is how often paused procs check if they are resumed.
+const pausePeriod = time.Second
+
+// Proc is a single fuzzing loop that executes programs with own Executor.
+// A Fuzzer can have any number of procs running concurrently.
+type Proc struct {
+	fuzzer *Fuzzer
+	pid    int
+	exec   Executor
+	rs     rand.Source
+	rnd    *rand.Rand
+	iter   int
+	// Number of executions and amount of new signal, used to reward scheduler arms.
+	execs  uint64
+	signal uint64
+}
+
+func (fuzzer *Fuzzer) NewProc(pid int, exec Executor, rs rand.Source) *Proc {
+	return &Proc{
+		fuzzer: fuzzer,
+		pid:    pid,
+		exec:   exec,
+		rs:     rs,
+		rnd:    rand.New(rs),
+	}
+}
+
+// Loop runs the fuzzing loop forever.
+func (proc *Proc) Loop() {
+	for {
+		proc.Step()
+	}
+}
+
+// Step does a single unit of work: executes, triages or smashes one program from
+// the work queues, or generates/mutates and executes a new program if the queues are empty.
+func (proc *Proc) Step() {
+	for proc.fuzzer.Paused() {
+		time.Sleep(pausePeriod)
+	}
+	i := proc.iter
+	proc.iter++
+	if proc.fuzzer.adaptive {
+		proc.stepAdaptive(i)
+		return
+	}
+	if proc.stepQueues(true) {
+		return
+	}
+	corpus := proc.fuzzer.corpusSnapshot()
+	if len(corpus) == 0 || i%100 == 0 {
+		proc.runArm(ArmGenerate, func() { proc.generate(i) })
+	} else {
+		parent := proc.fuzzer.chooseInput(proc.rnd)
+		proc.runArm(ArmMutate, func() { proc.mutate(i, parent, corpus) })
+	}
+}
+
+// stepAdaptive executes and triages candidates and new inputs first as the fixed schedule does,
+// then lets the scheduler choose the strategy.
+func (proc *Proc) stepAdaptive(i int) {
+	if proc.stepQueues(false) {
+		return
+	}
+	fuzzer := proc.fuzzer
+	corpus := fuzzer.corpusSnapshot()
+	var avail [ArmCount]bool
+	avail[ArmGenerate] = true
+	avail[ArmMutate] = len(corpus) != 0
+	avail[ArmSmash] = len(corpus) != 0
+	avail[ArmHints] = len(corpus) != 0 && fuzzer.comps
+	avail[ArmFault] = len(corpus) != 0 && fuzzer.fault
+	avail[ArmRace] = len(corpus) != 0 && fuzzer.race
+	arm := fuzzer.sched.choose(proc.rnd, avail)
+	// Smashing strategies prefer new inputs, but work on random corpus programs as well.
+	var queue *[]workInput
+	switch arm {
+	case ArmSmash:
+		queue = &fuzzer.smashQueue
+	case ArmHints:
+		queue = &fuzzer.hintQueue
+	case ArmFault:
+		queue = &fuzzer.faultQueue
+	}
+	var inp workInput
+	if arm == ArmMutate || arm == ArmRace {
+		inp.input = fuzzer.chooseInput(proc.rnd)
+	}
+	if queue != nil {
+		var ok bool
+		if inp, ok = fuzzer.popQueue(queue); !ok {
+			inp.input = fuzzer.chooseInput(proc.rnd)
+			inp.p = inp.input.p
+			inp.call = -1
+			if len(inp.p.Calls) != 0 {
+				inp.call = proc.rnd.Intn(len(inp.p.Calls))
+			}
+		}
+	}
+	proc.runArm(arm, func() {
+		switch arm {
+		case ArmGenerate:
+			proc.generate(i)
+		case ArmMutate:
+			proc.mutate(i, inp.input, corpus)
+		case ArmSmash:
+			proc.runInput(inp.input, func() { proc.smash(inp.p) })
+		case ArmHints:
+			proc.runInput(inp.input, func() { proc.executeHintSeed(inp.p) })
+		case ArmFault:
+			if inp.call != -1 {
+				proc.runInput(inp.input, func() { proc.failCall(inp.p, inp.call) })
+			}
+		case ArmRace:
+			proc.runInput(inp.input, func() { proc.race(inp.input.p) })
+		}
+	})
+}
+
+// runArm runs strategy f and rewards the arm with new signal that f has found.
+func (proc *Proc) runArm(arm Arm, f func()) {
+	execs, signal := proc.execs, proc.signal
+	f()
+	proc.fuzzer.sched.update(arm, proc.execs-execs, proc.signal-signal)
+}
+
+// runInput runs strategy f on corpus input inp and accounts new signal that f has found
+// in the input power schedule counters. inp can be nil if the program is not in corpus.
+func (proc *Proc) runInput(inp *corpusInput, f func()) {
+	signal := proc.signal
+	f()
+	if inp != nil {
+		inp.update(proc.signal - signal)
+	}
+}
+
+func (proc *Proc) generate(i int) {
+	fuzzer := proc.fuzzer
+	p := fuzzer.target.Generate(proc.rnd, programLength, fuzzer.choiceTable())
+	Logf(1, "#%v: generated: %s", i, p)
+	proc.execute(p, false, false, false, false, StatGenerate)
+}
+
+func (proc *Proc) mutate(i int, parent *corpusInput, corpus []*prog.Prog) {
+	proc.runInput(parent, func() {
+		p := parent.p.Clone()
+		p.Mutate(proc.rs, programLength, proc.fuzzer.choiceTable(), corpus)
+		Logf(1, "#%v: mutated: %s", i, p)
+		proc.execute(p, false, false, false, false, StatFuzz)
+	})
+}
+
+// stepQueues handles one item from the work queues, returns false if all queues are empty.
+// The smash queue is handled only if smash is set.
+func (proc *Proc) stepQueues(smash bool) bool {
+	fuzzer := proc.fuzzer
+	fuzzer.queueMu.RLock()
+	if len(fuzzer.triageCandidate) == 0 && len(fuzzer.candidates) == 0 &&
+		len(fuzzer.triage) == 0 && (!smash || len(fuzzer.smashQueue) == 0) {
+		fuzzer.queueMu.RUnlock()
+		return false
+	}
+	fuzzer.queueMu.RUnlock()
+	fuzzer.queueMu.Lock()
+	if len(fuzzer.triageCandidate) != 0 {
+		last := len(fuzzer.triageCandidate) - 1
+		inp := fuzzer.triageCandidate[last]
+		fuzzer.triageCandidate = fuzzer.triageCandidate[:last]
+		fuzzer.queueMu.Unlock()
+		Logf(1, "triaging candidate: %s", inp.p)
+		proc.triageInput(inp)
+	} else if len(fuzzer.candidates) != 0 {
+		last := len(fuzzer.candidates) - 1
+		candidate := fuzzer.candidates[last]
+		fuzzer.candidates = fuzzer.candidates[:last]
+		needCandidates := len(fuzzer.candidates) < fuzzer.procs
+		fuzzer.queueMu.Unlock()
+		if needCandidates {
+			fuzzer.manager.NeedCandidates()
+		}
+		Logf(1, "executing candidate: %s", candidate.p)
+		proc.execute(candidate.p, false, false, candidate.minimized, true, StatCandidate)
+	} else if len(fuzzer.triage) != 0 {
+		last := len(fuzzer.triage) - 1
+		inp := fuzzer.triage[last]
+		fuzzer.triage = fuzzer.triage[:last]
+		fuzzer.queueMu.Unlock()
+		Logf(1, "triaging : %s", inp.p)
+		proc.triageInput(inp)
+	} else if smash && len(fuzzer.smashQueue) != 0 {
+		last := len(fuzzer.smashQueue) - 1
+		inp := fuzzer.smashQueue[last]
+		fuzzer.smashQueue = fuzzer.smashQueue[:last]
+		fuzzer.queueMu.Unlock()
+		Logf(1, "%v: smashing call %v in program: %v", proc.pid, inp.call, inp.p.String())
+		proc.smashInput(inp)
+	} else {
+		// Somebody else has grabbed the work.
+		fuzzer.queueMu.Unlock()
+	}
+	return true
+}
+
+func (proc *Proc) triageInput(inp workInput) {
+	fuzzer := proc.fuzzer
+	if fuzzer.noCover {
+		panic("should not be called when coverage is disabled")
+	}
+
+	fuzzer.signalMu.RLock()
+	newSignal := cover.SignalDiff(fuzzer.corpusSignal, inp.signal)
+	fuzzer.signalMu.RUnlock()
+	if len(newSignal) == 0 {
+		return
+	}
+	newSignal = cover.Canonicalize(newSignal)
+
+	call := inp.p.Calls[inp.call].Meta
+	Logf(3, "triaging input for %v (new signal=%v):\n%s", call.CallName, len(newSignal), inp.p)
+	var inputCover cover.Cover
+	// Signal found with non-default options (racing calls) reproduces only with the same options.
+	triageOpts := func() *ipc.ExecOpts {
+		opts := new(ipc.ExecOpts)
+		if inp.opts != nil {
+			*opts = *inp.opts
+		}
+		opts.Flags |= ipc.FlagCollectCover
+		return opts
+	}
+	if inp.minimized {
+		// We just need to get input coverage.
+		for i := 0; i < 3; i++ {
+			info := proc.execute1(triageOpts(), inp.p, StatTriage)
+			if len(info) == 0 || len(info[inp.call].Cover) == 0 {
+				continue // The call was not executed. Happens sometimes.
+			}
+			inputCover = append([]uint32{}, info[inp.call].Cover...)
+			break
+		}
+	} else {
+		// We need to compute input coverage and non-flaky signal for minimization.
+		notexecuted := false
+		for i := 0; i < 3; i++ {
+			info := proc.execute1(triageOpts(), inp.p, StatTriage)
+			if len(info) == 0 || len(info[inp.call].Signal) == 0 {
+				// The call was not executed. Happens sometimes.
+				if notexecuted {
+					return // if it happened twice, give up
+				}
+				notexecuted = true
+				continue
+			}
+			inf := info[inp.call]
+			stable := cover.Intersection(newSignal, cover.Canonicalize(inf.Signal))
+			if flaky := cover.Difference(newSignal, stable); len(flaky) != 0 {
+				fuzzer.signalMu.Lock()
+				for _, s := range flaky {
+					fuzzer.flakySignal[s]++
+				}
+				fuzzer.signalMu.Unlock()
+			}
+			newSignal = stable
+			if len(newSignal) == 0 {
+				return
+			}
+			if len(inputCover) == 0 {
+				inputCover = append([]uint32{}, inf.Cover...)
+			} else {
+				inputCover = cover.Union(inputCover, inf.Cover)
+			}
+		}
+
+		ncalls := len(inp.p.Calls)
+		inp.p, inp.call = prog.Minimize(inp.p, inp.call, func(p1 *prog.Prog, call1 int) bool {
+			var info []ipc.CallInfo
+			if inp.opts == nil {
+				info = proc.execute(p1, false, false, false, false, StatMinimize)
+			} else {
+				// Options refer to calls by index, so calls can't be removed.
+				if len(p1.Calls) != ncalls {
+					return false
+				}
+				opts := new(ipc.ExecOpts)
+				*opts = *inp.opts
+				info = proc.execute1(opts, p1, StatMinimize)
+				proc.signal += proc.fuzzer.checkNewSignal(p1, info, inp.opts, false, false)
+			}
+			if len(info) == 0 || len(info[call1].Signal) == 0 {
+				return false // The call was not executed.
+			}
+			inf := info[call1]
+			signal := cover.Canonicalize(inf.Signal)
+			if len(cover.Intersection(newSignal, signal)) != len(newSignal) {
+				return false
+			}
+			return true
+		}, false)
+	}
+
+	data := inp.p.Serialize()
+	sig := hash.Hash(data)
+	fuzzer.signalMu.RLock()
+	signal := cover.Canonicalize(cover.SignalDiff(fuzzer.noisySignal, inp.signal))
+	fuzzer.signalMu.RUnlock()
+	atomic.AddUint64(&fuzzer.stats[StatNewInput], 1)
+	Logf(2, "added new input for %v to corpus:\n%s", call.CallName, data)
+	err := fuzzer.manager.NewInput(Input{
+		Call:   call.CallName,
+		Prog:   data,
+		Signal: []uint32(signal),
+		Cover:  []uint32(inputCover),
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	fuzzer.signalMu.Lock()
+	cover.SignalAdd(fuzzer.corpusSignal, inp.signal)
+	fuzzer.signalMu.Unlock()
+
+	inp.input = fuzzer.addInputToCorpus(inp.p, sig, signal, inputCover)
+
+	if !inp.minimized {
+		fuzzer.queueSmash(inp)
+	}
+}
+
+// smashInput smashes a new input with all strategies (used by the fixed schedule).
+func (proc *Proc) smashInput(inp workInput) {
+	fuzzer := proc.fuzzer
+	proc.runInput(inp.input, func() {
+		if fuzzer.fault {
+			proc.runArm(ArmFault, func() { proc.failCall(inp.p, inp.call) })
+		}
+		proc.runArm(ArmSmash, func() { proc.smash(inp.p) })
+		if fuzzer.comps {
+			proc.runArm(ArmHints, func() { proc.executeHintSeed(inp.p) })
+		}
+		if fuzzer.race {
+			proc.runArm(ArmRace, func() { proc.race(inp.p) })
+		}
+	})
+}
+
+func (proc *Proc) smash(p0 *prog.Prog) {
+	fuzzer := proc.fuzzer
+	corpus := fuzzer.corpusSnapshot()
+	for i := 0; i < 100; i++ {
+		p := p0.Clone()
+		p.Mutate(proc.rs, programLength, fuzzer.choiceTable(), corpus)
+		Logf(1, "#%v: mutated: %s", proc.pid, p)
+		proc.execute(p, false, false, false, false, StatSmash)
+	}
+}
+
+// failCall injects faults into consecutive fault points of call and records the hit fault sites.
+// Fault points that are known to hit already exercised sites are mostly skipped.
+func (proc *Proc) failCall(p *prog.Prog, call int) {
+	fuzzer := proc.fuzzer
+	meta := p.Calls[call].Meta
+	// Coverage trace without faults, fault sites are where fault paths diverge from it.
+	var base []uint32
+	info := proc.executeRaw(&ipc.ExecOpts{Flags: ipc.FlagCollectCover}, p, StatSmash)
+	if len(info) > call {
+		base = info[call].Cover
+	}
+	for nth := 0; nth < 100; nth++ {
+		if fuzzer.skipFault(proc.rnd, meta.ID, nth) {
+			continue
+		}
+		Logf(1, "%v: injecting fault into call %v/%v in program: %v", proc.pid, call, nth, p.String())
+		opts := &ipc.ExecOpts{
+			Flags:     ipc.FlagInjectFault | ipc.FlagCollectCover,
+			FaultCall: call,
+			FaultNth:  nth,
+		}
+		info := proc.executeRaw(opts, p, StatSmash)
+		if info == nil || len(info) <= call {
+			continue
+		}
+		if !info[call].FaultInjected {
+			break
+		}
+		if pc, ok := faultSite(base, info[call].Cover); ok {
+			if fuzzer.recordFault(meta.ID, meta.Name, nth, pc) {
+				atomic.AddUint64(&fuzzer.stats[StatFaultSite], 1)
+			}
+		}
+	}
+}
+
+func (proc *Proc) executeHintSeed(p *prog.Prog) {
+	if !proc.fuzzer.comps {
+		panic("comparisons are not supported and executeHintSeed() called")
+	}
+	// First execute the original program to dump comparisons from KCOV.
+	info := proc.execute(p, false, true, false, false, StatSeed)
+
+	// Then extract the comparisons data.
+	compMaps := ipc.GetCompMaps(info)
+	stringCompMaps := ipc.GetStringCompMaps(info)
+
+	// Then mutate the initial program for every match between
+	// a syscall argument and a comparison operand.
+	// Execute each of such mutants to check if it gives new coverage.
+	p.MutateWithHints(compMaps, stringCompMaps, func(p *prog.Prog) {
+		proc.execute(p, false, false, false, false, StatHint)
+	})
+}
+
+func (proc *Proc) execute(p *prog.Prog, needCover, needComps, minimized, candidate bool, stat Stat) []ipc.CallInfo {
+	opts := &ipc.ExecOpts{}
+	if needComps {
+		if !proc.fuzzer.comps {
+			panic("comparisons are not supported and execute() called with needComps")
+		}
+		if needCover {
+			// Currently KCOV is able to dump only the coverage data or only
+			// the comparisons data. We can't enable both modes at same time.
+			panic("only one of the needComps and needCover should be true")
+		}
+		opts.Flags |= ipc.FlagCollectComps
+	}
+	if needCover {
+		opts.Flags |= ipc.FlagCollectCover
+	}
+	info := proc.execute1(opts, p, stat)
+	proc.signal += proc.fuzzer.checkNewSignal(p, info, nil, minimized, candidate)
+	return info
+}
+
+func (proc *Proc) execute1(opts *ipc.ExecOpts, p *prog.Prog, stat Stat) []ipc.CallInfo {
+	opts.Flags |= ipc.FlagDedupCover
+	return proc.executeRaw(opts, p, stat)
+}
+
+// executeRaw executes p without coverage deduplication, so coverage is in execution order.
+func (proc *Proc) executeRaw(opts *ipc.ExecOpts, p *prog.Prog, stat Stat) []ipc.CallInfo {
+	// Executor can block for a long time, so this must not be called with fuzzer locks held.
+	if !proc.fuzzer.callsEnabled(p) {
+		atomic.AddUint64(&proc.fuzzer.stats[StatDisabled], 1)
+		return nil
+	}
+	atomic.AddUint64(&proc.fuzzer.stats[stat], 1)
+	proc.execs++
+	info := proc.exec.Exec(opts, p)
+	proc.fuzzer.recordCallStats(p, info)
+	return info
+}
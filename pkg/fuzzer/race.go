@@ -0,0 +1,145 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"math/rand"
+	"sort"
+	"sync/atomic"
+
+	"github.com/google/syzkaller/pkg/cover"
+	"github.com/google/syzkaller/pkg/ipc"
+	. "github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/prog"
+)
+
+const (
+	// Number of racing executions of pairs of calls of a single program.
+	raceIters = 10
+	// Sharing a resource counts as sharing this number of coverage PCs.
+	raceResourceScore = 100
+	// Racing executions start the second call after a random delay up to 2^raceMaxDelayLog microseconds.
+	raceMaxDelayLog = 12
+)
+
+// RacePair is a pair of syscalls executed concurrently by the race strategy.
+type RacePair struct {
+	Call1     string
+	Call2     string
+	Execs     uint64
+	NewSignal uint64
+}
+
+type racePairKey struct {
+	call1 string
+	call2 string
+}
+
+// racePair is a pair of calls of a program that are likely to race:
+// they share coverage PCs or resources.
+type racePair struct {
+	call1 int
+	call2 int
+	score int
+}
+
+// racePairs returns pairs of calls of p that share coverage PCs (according to info)
+// or resources, the most promising pairs first.
+func racePairs(p *prog.Prog, info []ipc.CallInfo) []racePair {
+	covers := make([]cover.Cover, len(p.Calls))
+	for i := range covers {
+		if i < len(info) && info[i].Errno != -1 {
+			covers[i] = cover.Canonicalize(append([]uint32{}, info[i].Cover...))
+		}
+	}
+	var pairs []racePair
+	for i := range p.Calls {
+		for j := i + 1; j < len(p.Calls); j++ {
+			score := len(cover.Intersection(covers[i], covers[j]))
+			if prog.SharedResources(p, i, j) {
+				score += raceResourceScore
+			}
+			if score != 0 {
+				pairs = append(pairs, racePair{i, j, score})
+			}
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i].score > pairs[j].score
+	})
+	return pairs
+}
+
+// chooseRacePair returns a random pair with probability proportional to its score.
+func chooseRacePair(rnd *rand.Rand, pairs []racePair) racePair {
+	total := 0
+	for _, pair := range pairs {
+		total += pair.score
+	}
+	v := rnd.Intn(total)
+	for _, pair := range pairs {
+		if v < pair.score {
+			return pair
+		}
+		v -= pair.score
+	}
+	return pairs[len(pairs)-1]
+}
+
+// race executes pairs of calls of p that share coverage or resources concurrently
+// with random delays and CPU affinity, and records pairs that give new signal.
+func (proc *Proc) race(p *prog.Prog) {
+	fuzzer := proc.fuzzer
+	info := proc.execute1(&ipc.ExecOpts{Flags: ipc.FlagCollectCover}, p, StatRace)
+	pairs := racePairs(p, info)
+	if len(pairs) == 0 {
+		return
+	}
+	for i := 0; i < raceIters; i++ {
+		pair := chooseRacePair(proc.rnd, pairs)
+		opts := &ipc.ExecOpts{
+			Flags:     ipc.FlagRace,
+			RaceCall1: pair.call1,
+			RaceCall2: pair.call2,
+			RaceDelay: proc.rnd.Intn(1 << uint(proc.rnd.Intn(raceMaxDelayLog+1))),
+		}
+		if proc.rnd.Intn(2) == 0 {
+			opts.Flags |= ipc.FlagRaceAffinity
+		}
+		Logf(1, "%v: racing calls %v and %v (delay %vus) in program: %v",
+			proc.pid, pair.call1, pair.call2, opts.RaceDelay, p.String())
+		info := proc.execute1(opts, p, StatRace)
+		signal := fuzzer.checkNewSignal(p, info, opts, false, false)
+		proc.signal += signal
+		if signal != 0 {
+			atomic.AddUint64(&fuzzer.stats[StatRaceSignal], 1)
+		}
+		fuzzer.recordRace(p.Calls[pair.call1].Meta.Name, p.Calls[pair.call2].Meta.Name, signal)
+	}
+}
+
+func (fuzzer *Fuzzer) recordRace(call1, call2 string, signal uint64) {
+	fuzzer.raceMu.Lock()
+	defer fuzzer.raceMu.Unlock()
+	key := racePairKey{call1, call2}
+	pair := fuzzer.racePairs[key]
+	if pair == nil {
+		pair = &RacePair{Call1: call1, Call2: call2}
+		fuzzer.racePairs[key] = pair
+	}
+	pair.Execs++
+	pair.NewSignal += signal
+}
+
+// GrabRacePairs returns pairs of syscalls raced since the previous call.
+func (fuzzer *Fuzzer) GrabRacePairs() []RacePair {
+	fuzzer.raceMu.Lock()
+	defer fuzzer.raceMu.Unlock()
+	var pairs []RacePair
+	for _, pair := range fuzzer.racePairs {
+		pairs = append(pairs, *pair)
+	}
+	fuzzer.racePairs = make(map[racePairKey]*RacePair)
+	return pairs
+}
@@ -0,0 +1,112 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"testing"
+
+	"github.com/google/syzkaller/pkg/ipc"
+)
+
+func TestRacePairs(t *testing.T) {
+	_, _, _ = initTest(t, new(testExecutor))
+	p, err := testTarget.Deserialize([]byte(`
+r0 = open(&(0x7f0000000000)="2e2f66696c653000", 0x0, 0x0)
+read(r0, &(0x7f0000001000)="", 0x1)
+getpid()
+gettid()
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := []ipc.CallInfo{
+		{Cover: []uint32{1, 2}},
+		{Cover: []uint32{3, 4}},
+		{Cover: []uint32{5, 6, 7}},
+		{Cover: []uint32{7, 6, 8}},
+	}
+	pairs := racePairs(p, info)
+	if len(pairs) != 2 {
+		t.Fatalf("got %v pairs, want 2: %+v", len(pairs), pairs)
+	}
+	// Shared resource scores more than shared coverage.
+	if pairs[0] != (racePair{0, 1, raceResourceScore}) || pairs[1] != (racePair{2, 3, 2}) {
+		t.Fatalf("bad pairs: %+v", pairs)
+	}
+	// Calls that were not executed do not share coverage.
+	info[3].Errno = -1
+	if pairs := racePairs(p, info); len(pairs) != 1 {
+		t.Fatalf("got %v pairs, want 1: %+v", len(pairs), pairs)
+	}
+}
+
+func TestFuzzerRace(t *testing.T) {
+	exec := new(testExecutor)
+	fuzzer, proc, _ := initTest(t, exec)
+	fuzzer.race = true
+	p, err := testTarget.Deserialize([]byte(`
+r0 = open(&(0x7f0000000000)="2e2f66696c653000", 0x0, 0x0)
+read(r0, &(0x7f0000001000)="", 0x1)
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	proc.race(p)
+	if exec.execs != 1+raceIters {
+		t.Fatalf("executed %v programs, want %v", exec.execs, 1+raceIters)
+	}
+	pairs := fuzzer.GrabRacePairs()
+	if len(pairs) != 1 {
+		t.Fatalf("got %v race pairs, want 1: %+v", len(pairs), pairs)
+	}
+	pair := pairs[0]
+	if pair.Call1 != "open" || pair.Call2 != "read" || pair.Execs != raceIters || pair.NewSignal == 0 {
+		t.Fatalf("bad race pair: %+v", pair)
+	}
+	stats := fuzzer.GrabStats()
+	if stats[StatRace.String()] != 1+raceIters || stats[StatRaceSignal.String()] == 0 {
+		t.Fatalf("bad race stats: %+v", stats)
+	}
+	if pairs := fuzzer.GrabRacePairs(); len(pairs) != 0 {
+		t.Fatalf("race pairs are not reset: %+v", pairs)
+	}
+}
+
+func TestFuzzerRaceTriage(t *testing.T) {
+	exec := new(testExecutor)
+	fuzzer, proc, mgr := initTest(t, exec)
+	fuzzer.race = true
+	p, err := testTarget.Deserialize([]byte(`
+r0 = open(&(0x7f0000000000)="2e2f66696c653000", 0x0, 0x0)
+read(r0, &(0x7f0000001000)="", 0x1)
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Signal of the calls without racing is already known.
+	fuzzer.AddMaxSignal(append(callSignal(p.Calls[0].Meta), callSignal(p.Calls[1].Meta)...))
+	fuzzer.AddNoisySignal(append(callSignal(p.Calls[0].Meta), callSignal(p.Calls[1].Meta)...))
+	proc.race(p)
+	for len(fuzzer.triage) != 0 {
+		proc.stepQueues(false)
+	}
+	const raceSignal = 0xf0000000 | 0<<8 | 1
+	if len(mgr.inputs) != 1 {
+		t.Fatalf("got %v inputs, want 1: %+v", len(mgr.inputs), mgr.inputs)
+	}
+	inp := mgr.inputs[0]
+	if inp.Call != "read" || len(inp.Signal) != 1 || inp.Signal[0] != raceSignal {
+		t.Fatalf("bad race input: %+v\n%s", inp, inp.Prog)
+	}
+	p1, err := testTarget.Deserialize(inp.Prog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p1.Calls) != len(p.Calls) {
+		t.Fatalf("minimization removed racing calls:\n%s", inp.Prog)
+	}
+	if flaky := fuzzer.GrabFlakySignal(); len(flaky) != 0 {
+		t.Fatalf("race signal is reported as flaky: %v", flaky)
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+)
+
+// Arm is a fuzzing strategy that the scheduler chooses from.
+type Arm int
+
+const (
+	ArmGenerate Arm = iota // generate a new program
+	ArmMutate              // mutate a corpus program
+	ArmSmash               // mutate a new input (or a corpus program) many times
+	ArmHints               // mutate a program with comparison hints
+	ArmFault               // inject faults into calls of a program
+	ArmRace                // execute pairs of calls of a program concurrently
+	ArmCount
+)
+
+var armNames = [ArmCount]string{
+	ArmGenerate: "generate",
+	ArmMutate:   "mutate",
+	ArmSmash:    "smash",
+	ArmHints:    "hints",
+	ArmFault:    "fault",
+	ArmRace:     "race",
+}
+
+func (arm Arm) String() string {
+	return armNames[arm]
+}
+
+const (
+	// ScheduleFixed uses fixed rules: smash every new input with all strategies,
+	// otherwise generate every 100-th program and mutate corpus programs.
+	ScheduleFixed = "fixed"
+	// ScheduleAdaptive treats strategies as arms of a multi-armed bandit
+	// and chooses them proportionally to new signal per execution they recently gave.
+	ScheduleAdaptive = "adaptive"
+)
+
+var Schedules = []string{ScheduleFixed, ScheduleAdaptive}
+
+const (
+	// Every available arm is chosen with at least this probability.
+	explorationFloor = 0.05
+	// Optimistic prior for arms that were not chosen yet: 1 new signal per priorExecs executions.
+	priorExecs = 100
+	// Rewards are halved every decayExecs executions, so that the scheduler follows
+	// what is currently paying off rather than what paid off at the beginning.
+	decayExecs = 1e4
+)
+
+// scheduler keeps statistics of arms and chooses the next arm.
+type scheduler struct {
+	mu    sync.Mutex
+	arms  [ArmCount]armStats
+	execs float64 // executions since the last decay
+}
+
+type armStats struct {
+	execs  float64 // decayed number of executions
+	signal float64 // decayed amount of new signal
+	// Not decayed, reset by grabStats.
+	statExecs  uint64
+	statSignal uint64
+}
+
+// probabilities returns probabilities of choosing arms, only arms in avail can be chosen.
+func (sched *scheduler) probabilities(avail [ArmCount]bool) [ArmCount]float64 {
+	sched.mu.Lock()
+	defer sched.mu.Unlock()
+	var prob [ArmCount]float64
+	n, total := 0, 0.0
+	for arm := range sched.arms {
+		if !avail[arm] {
+			continue
+		}
+		stat := &sched.arms[arm]
+		prob[arm] = (stat.signal + 1) / (stat.execs + priorExecs)
+		total += prob[arm]
+		n++
+	}
+	if n == 0 {
+		return prob
+	}
+	floor := explorationFloor
+	if floor*float64(n) > 1 {
+		floor = 1 / float64(n)
+	}
+	for arm := range prob {
+		if avail[arm] {
+			prob[arm] = floor + (1-floor*float64(n))*prob[arm]/total
+		}
+	}
+	return prob
+}
+
+func (sched *scheduler) choose(rnd *rand.Rand, avail [ArmCount]bool) Arm {
+	prob := sched.probabilities(avail)
+	v := rnd.Float64()
+	last := ArmCount
+	for arm, p := range prob {
+		if !avail[arm] {
+			continue
+		}
+		last = Arm(arm)
+		if v < p {
+			return last
+		}
+		v -= p
+	}
+	if last == ArmCount {
+		panic("no available arms")
+	}
+	return last
+}
+
+// update accounts execs executions that gave signal new signal to arm.
+func (sched *scheduler) update(arm Arm, execs, signal uint64) {
+	sched.mu.Lock()
+	defer sched.mu.Unlock()
+	stat := &sched.arms[arm]
+	stat.execs += float64(execs)
+	stat.signal += float64(signal)
+	stat.statExecs += execs
+	stat.statSignal += signal
+	sched.execs += float64(execs)
+	if sched.execs >= decayExecs {
+		sched.execs = 0
+		for i := range sched.arms {
+			sched.arms[i].execs /= 2
+			sched.arms[i].signal /= 2
+		}
+	}
+}
+
+// grabStats returns executions and new signal per arm since the previous call.
+func (sched *scheduler) grabStats(stats map[string]uint64) {
+	sched.mu.Lock()
+	defer sched.mu.Unlock()
+	for arm := range sched.arms {
+		stat := &sched.arms[arm]
+		if stat.statExecs != 0 {
+			stats[fmt.Sprintf("schedule %v", Arm(arm))] = stat.statExecs
+		}
+		if stat.statSignal != 0 {
+			stats[fmt.Sprintf("schedule %v signal", Arm(arm))] = stat.statSignal
+		}
+		stat.statExecs = 0
+		stat.statSignal = 0
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzer
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func allArms() [ArmCount]bool {
+	var avail [ArmCount]bool
+	for arm := range avail {
+		avail[arm] = true
+	}
+	return avail
+}
+
+func checkProbabilities(t *testing.T, prob [ArmCount]float64, avail [ArmCount]bool) {
+	total := 0.0
+	for arm, p := range prob {
+		if !avail[arm] && p != 0 {
+			t.Errorf("unavailable arm %v has probability %v", Arm(arm), p)
+		}
+		if avail[arm] && p < explorationFloor-1e-9 {
+			t.Errorf("arm %v has probability %v below exploration floor", Arm(arm), p)
+		}
+		total += p
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Errorf("probabilities sum up to %v: %v", total, prob)
+	}
+}
+
+func TestSchedulerProbabilities(t *testing.T) {
+	var sched scheduler
+	avail := allArms()
+	prob := sched.probabilities(avail)
+	checkProbabilities(t, prob, avail)
+	for arm := range prob {
+		if math.Abs(prob[arm]-prob[0]) > 1e-9 {
+			t.Fatalf("arms without history have different probabilities: %v", prob)
+		}
+	}
+	sched.update(ArmMutate, 1000, 100)
+	sched.update(ArmGenerate, 1000, 1)
+	sched.update(ArmSmash, 1000, 0)
+	prob = sched.probabilities(avail)
+	checkProbabilities(t, prob, avail)
+	if prob[ArmMutate] <= prob[ArmGenerate] || prob[ArmGenerate] <= prob[ArmSmash] {
+		t.Fatalf("probabilities don't follow rewards: %v", prob)
+	}
+	avail[ArmHints] = false
+	avail[ArmFault] = false
+	prob = sched.probabilities(avail)
+	checkProbabilities(t, prob, avail)
+}
+
+func TestSchedulerChoose(t *testing.T) {
+	var sched scheduler
+	sched.update(ArmMutate, 1000, 1000)
+	avail := allArms()
+	avail[ArmFault] = false
+	rnd := rand.New(rand.NewSource(0))
+	var count [ArmCount]int
+	const iters = 1e5
+	for i := 0; i < iters; i++ {
+		count[sched.choose(rnd, avail)]++
+	}
+	prob := sched.probabilities(avail)
+	for arm := range count {
+		if got := float64(count[arm]) / iters; math.Abs(got-prob[arm]) > 0.01 {
+			t.Errorf("arm %v chosen with frequency %v, want %v", Arm(arm), got, prob[arm])
+		}
+	}
+}
+
+func TestSchedulerDecay(t *testing.T) {
+	var sched scheduler
+	// Generate used to be good, but now mutate is better.
+	sched.update(ArmGenerate, decayExecs/2, 1000)
+	for i := 0; i < 10; i++ {
+		sched.update(ArmMutate, decayExecs/2, 1000)
+	}
+	prob := sched.probabilities(allArms())
+	if prob[ArmMutate] <= prob[ArmGenerate] {
+		t.Fatalf("old rewards are not decayed: %v", prob)
+	}
+	stats := make(map[string]uint64)
+	sched.grabStats(stats)
+	if stats["schedule mutate"] != 10*decayExecs/2 || stats["schedule generate signal"] != 1000 {
+		t.Fatalf("bad stats: %v", stats)
+	}
+	stats = make(map[string]uint64)
+	if sched.grabStats(stats); len(stats) != 0 {
+		t.Fatalf("stats are not reset: %v", stats)
+	}
+}
@@ -6,6 +6,8 @@
 package host
 
 import (
+	"fmt"
+
 	"github.com/google/syzkaller/prog"
 )
 
@@ -17,3 +19,14 @@ func DetectSupportedSyscalls(target *prog.Target) (map[*prog.Syscall]bool, error
 	}
 	return supported, nil
 }
+
+func KmemleakInit(enable bool) error {
+	if enable {
+		return fmt.Errorf("leak checking is not supported on fuchsia")
+	}
+	return nil
+}
+
+func KmemleakScan(report bool) ([]byte, error) {
+	return nil, nil
+}
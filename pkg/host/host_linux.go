@@ -5,11 +5,13 @@ package host
 
 import (
 	"bytes"
+	"fmt"
 	"io/ioutil"
 	"runtime"
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/google/syzkaller/pkg/osutil"
 	"github.com/google/syzkaller/prog"
@@ -58,7 +60,7 @@ func isSupported(kallsyms []byte, c *prog.Syscall) bool {
 
 func isSupportedSyzkall(c *prog.Syscall) bool {
 	switch c.CallName {
-	case "syz_test":
+	case "syz_test", "syz_compare":
 		return false
 	case "syz_open_dev":
 		if _, ok := c.Args[0].(*prog.ConstType); ok {
@@ -159,3 +161,80 @@ func extractStringConst(typ prog.Type) (string, bool) {
 	v = v[:len(v)-1] // string terminating \x00
 	return v, true
 }
+
+// KmemleakInit turns off automatic kmemleak scanning if enable is set
+// (leaks are scanned for with KmemleakScan), or turns kmemleak off completely otherwise.
+func KmemleakInit(enable bool) error {
+	fd, err := syscall.Open("/sys/kernel/debug/kmemleak", syscall.O_RDWR, 0)
+	if err != nil {
+		if !enable {
+			return nil
+		}
+		return fmt.Errorf("/sys/kernel/debug/kmemleak is missing (%v), enable CONFIG_KMEMLEAK and mount debugfs", err)
+	}
+	defer syscall.Close(fd)
+	what := "scan=off"
+	if !enable {
+		what = "off"
+	}
+	if _, err := syscall.Write(fd, []byte(what)); err != nil {
+		// kmemleak returns EBUSY when kmemleak is already turned off.
+		if err != syscall.EBUSY {
+			return fmt.Errorf("failed to write %v to kmemleak: %v", what, err)
+		}
+	}
+	return nil
+}
+
+var kmemleakBuf []byte
+
+// KmemleakScan scans for memory leaks and clears the leak list.
+// If report is set, returns kmemleak output with the leaks found (empty if there are none).
+// Must not be called concurrently.
+func KmemleakScan(report bool) ([]byte, error) {
+	fd, err := syscall.Open("/sys/kernel/debug/kmemleak", syscall.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.Close(fd)
+	// Kmemleak has false positives. To mitigate most of them, it checksums
+	// potentially leaked objects, and reports them only on the next scan
+	// iff the checksum does not change. Because of that we do the following
+	// intricate dance:
+	// Scan, sleep, scan again. At this point we can get some leaks.
+	// If there are leaks, we sleep and scan again, this can remove
+	// false leaks. Then, read kmemleak again. If we get leaks now, then
+	// hopefully these are true positives during the previous testing cycle.
+	if _, err := syscall.Write(fd, []byte("scan")); err != nil {
+		return nil, err
+	}
+	time.Sleep(time.Second)
+	if _, err := syscall.Write(fd, []byte("scan")); err != nil {
+		return nil, err
+	}
+	var leaks []byte
+	if report {
+		if kmemleakBuf == nil {
+			kmemleakBuf = make([]byte, 128<<10)
+		}
+		n, err := syscall.Read(fd, kmemleakBuf)
+		if err != nil {
+			return nil, err
+		}
+		if n != 0 {
+			time.Sleep(time.Second)
+			if _, err := syscall.Write(fd, []byte("scan")); err != nil {
+				return nil, err
+			}
+			n, err := syscall.Read(fd, kmemleakBuf)
+			if err != nil {
+				return nil, err
+			}
+			leaks = append([]byte{}, kmemleakBuf[:n]...)
+		}
+	}
+	if _, err := syscall.Write(fd, []byte("clear")); err != nil {
+		return nil, err
+	}
+	return leaks, nil
+}
@@ -4,6 +4,8 @@
 package host
 
 import (
+	"fmt"
+
 	"github.com/google/syzkaller/prog"
 )
 
@@ -15,3 +17,14 @@ func DetectSupportedSyscalls(target *prog.Target) (map[*prog.Syscall]bool, error
 	}
 	return supported, nil
 }
+
+func KmemleakInit(enable bool) error {
+	if enable {
+		return fmt.Errorf("leak checking is not supported on windows")
+	}
+	return nil
+}
+
+func KmemleakScan(report bool) ([]byte, error) {
+	return nil, nil
+}
@@ -6,8 +6,10 @@ package ipc
 import (
 	"flag"
 	"fmt"
+	"os"
 	"time"
 
+	"github.com/google/syzkaller/pkg/cover"
 	"github.com/google/syzkaller/prog"
 )
 
@@ -21,6 +23,9 @@ const (
 	FlagSandboxNamespace                     // use namespaces for sandboxing
 	FlagEnableTun                            // initialize and use tun in executor
 	FlagEnableFault                          // enable fault injection support
+	FlagRawCover                             // executor returns raw coverage traces, signal is derived in Go (see Config.Signal)
+	FlagCgroups                              // account (and limit) memory and pids of programs with cgroups
+	FlagCallMarkers                          // write call markers for StringCompsSource, set by MakeEnv
 )
 
 // Per-exec flags for ExecOpts.Flags:
@@ -29,20 +34,27 @@ const (
 	FlagDedupCover                       // deduplicate coverage in executor
 	FlagInjectFault                      // inject a fault in this execution (see ExecOpts)
 	FlagCollectComps                     // collect KCOV comparisons
+	FlagRace                             // execute RaceCall1 and RaceCall2 concurrently (see ExecOpts)
+	FlagRaceAffinity                     // pin racing calls to different CPUs
 )
 
 const (
 	outputSize = 16 << 20
 
-	statusFail  = 67
-	statusError = 68
-	statusRetry = 69
+	statusFail        = 67
+	statusError       = 68
+	statusRetry       = 69
+	statusSetupFail   = 70
+	statusSandboxFail = 71
+	statusKcovFail    = 72
+	statusShmemFail   = 73
 )
 
 var (
 	flagThreaded = flag.Bool("threaded", true, "use threaded mode in executor")
 	flagCollide  = flag.Bool("collide", true, "collide syscalls to provoke data races")
 	flagSignal   = flag.Bool("cover", true, "collect feedback signals (coverage)")
+	flagSignalFn = flag.String("signal", cover.DefaultSignal, "feedback signal derivation function (executor/edge/pc/pc-errno/call-edge)")
 	flagSandbox  = flag.String("sandbox", "setuid", "sandbox for fuzzing (none/setuid/namespace)")
 	flagDebug    = flag.Bool("debug", false, "debug output from executor")
 	// Executor protects against most hangs, so we use quite large timeout here.
@@ -51,20 +63,129 @@ var (
 	flagTimeout     = flag.Duration("timeout", 1*time.Minute, "execution timeout")
 	flagAbortSignal = flag.Int("abort_signal", 0, "initial signal to send to executor in error conditions; upgrades to SIGKILL if executor does not exit")
 	flagBufferSize  = flag.Uint64("buffer_size", 0, "internal buffer size (in bytes) for executor output")
+	flagCgroups     = flag.Bool("cgroups", false, "use cgroups for per-program resource accounting and limits")
+	flagMemoryLimit = flag.Uint64("memory_limit", 0, "per-program memory limit (in MB, 0 for no limit)")
+	flagPidsLimit   = flag.Uint64("pids_limit", 0, "per-program limit on number of tasks (0 for no limit)")
+	flagFdsLimit    = flag.Uint64("fds_limit", 0, "per-program limit on number of open fds (0 for no limit)")
+	flagCPULimit    = flag.Duration("cpu_limit", 0, "per-program CPU time limit (0 for no limit)")
+	flagStringComps = flag.String("string_comps", NoStringComps, "source of byte-string comparison operands for hints (none/kprobe)")
 )
 
 type ExecOpts struct {
 	Flags     uint64
 	FaultCall int // call index for fault injection (0-based)
 	FaultNth  int // fault n-th operation in the call (0-based)
+	// With FlagRace RaceCall1 is not waited for and RaceCall2 is started
+	// RaceDelay microseconds after all preceding calls (call indices are 0-based).
+	RaceCall1 int
+	RaceCall2 int
+	RaceDelay int
 }
 
-// ExecutorFailure is returned from MakeEnv or from env.Exec when executor terminates by calling fail function.
-// This is considered a logical error (a failed assert).
-type ExecutorFailure string
+// FailureKind classifies executor failures.
+type FailureKind int
+
+const (
+	FailureAssert  FailureKind = iota // internal executor assert (fail function)
+	FailureSetup                      // failed to set up executor process
+	FailureSandbox                    // failed to set up sandbox
+	FailureKcov                       // failed to initialize KCOV
+	FailureShmem                      // failed to map shared memory regions
+	FailureTimeout                    // executor did not start serving in time
+
+	FailureKindCount = iota
+)
+
+var failureKindNames = [FailureKindCount]string{
+	FailureAssert:  "assert",
+	FailureSetup:   "setup",
+	FailureSandbox: "sandbox",
+	FailureKcov:    "kcov",
+	FailureShmem:   "shmem",
+	FailureTimeout: "timeout",
+}
+
+func (kind FailureKind) String() string {
+	if kind < 0 || kind >= FailureKindCount {
+		return fmt.Sprintf("unknown(%d)", int(kind))
+	}
+	return failureKindNames[kind]
+}
+
+// failureKindForStatus maps executor exit status to failure kind.
+func failureKindForStatus(status int) (FailureKind, bool) {
+	switch status {
+	case statusFail:
+		return FailureAssert, true
+	case statusSetupFail:
+		return FailureSetup, true
+	case statusSandboxFail:
+		return FailureSandbox, true
+	case statusKcovFail:
+		return FailureKcov, true
+	case statusShmemFail:
+		return FailureShmem, true
+	}
+	return 0, false
+}
+
+// ExecutorFailure is returned from MakeEnv or from env.Exec when executor terminates by calling fail function,
+// or does not start serving requests. Kind says in what phase the failure has happened.
+type ExecutorFailure struct {
+	Kind FailureKind
+	Msg  string
+}
 
 func (err ExecutorFailure) Error() string {
-	return string(err)
+	return err.Msg
+}
+
+// StringCompsSource collects byte-string comparison operands (e.g. arguments of strcmp/memcmp
+// in kernel) for programs executed with FlagCollectComps. KCOV collects only integer comparisons,
+// so the source is pluggable (see StringCompsSources).
+type StringCompsSource interface {
+	// Start is called right before a program is executed.
+	Start() error
+	// Collect is called after the program is executed and fills StringComps in info.
+	Collect(p *prog.Prog, info []CallInfo) error
+	// CallMarker returns a file that executor writes "syz-call N" before and "syz-call end"
+	// after every call to, so that operands can be attributed to calls (nil if not needed).
+	CallMarker() *os.File
+	Close() error
+}
+
+// NoStringComps means that byte-string comparison operands are not collected.
+const NoStringComps = "none"
+
+// StringCompsSources contains constructors of all supported byte-string comparison sources.
+// comm is the process name of executor that runs programs.
+var StringCompsSources = map[string]func(comm string) (StringCompsSource, error){}
+
+// Limits that a program can hit, for ResourceUsage.LimitsHit.
+const (
+	LimitMemory = uint32(1) << iota
+	LimitPids
+	LimitFds
+	LimitCPU
+
+	LimitCount = iota
+)
+
+var limitNames = [LimitCount]string{"memory", "pids", "fds", "cpu"}
+
+// LimitName returns name of the i-th limit (LimitMemory is 0-th).
+func LimitName(i int) string {
+	return limitNames[i]
+}
+
+// ResourceUsage describes resources consumed by a single program.
+type ResourceUsage struct {
+	MaxRSS    uint64        // peak resident set size of the test process (in bytes)
+	CPUTime   time.Duration // user+system CPU time of the test process
+	Fds       int           // number of open fds at the end of the program
+	Pids      int           // peak number of tasks, filled only with FlagCgroups
+	Memory    uint64        // peak memory usage (in bytes), filled only with FlagCgroups
+	LimitsHit uint32        // bitmask of Limit* constants
 }
 
 // Config is the configuration for Env.
@@ -80,6 +201,25 @@ type Config struct {
 
 	// BufferSize is the size of the internal buffer for executor output.
 	BufferSize uint64
+
+	// Signal is the name of the function used to derive feedback signal from coverage
+	// (see cover.SignalFuncs). Empty or cover.DefaultSignal means that executor
+	// computes signal itself, otherwise executor returns raw per-call coverage traces
+	// and signal is computed in Go.
+	Signal string
+
+	// Per-program resource limits, 0 means no limit.
+	// MemoryLimit and PidsLimit are enforced with cgroups if FlagCgroups is set.
+	// Without cgroups MemoryLimit can only lower the address space limit set by the sandbox,
+	// and PidsLimit is not enforced. CPULimit is rounded up to seconds.
+	MemoryLimit uint64 // in bytes
+	PidsLimit   uint64
+	FdsLimit    uint64
+	CPULimit    time.Duration
+
+	// StringComps is the name of the source of byte-string comparison operands
+	// (see StringCompsSources), empty or NoStringComps means that they are not collected.
+	StringComps string
 }
 
 func DefaultConfig() (Config, error) {
@@ -108,6 +248,21 @@ func DefaultConfig() (Config, error) {
 	c.Timeout = *flagTimeout
 	c.AbortSignal = *flagAbortSignal
 	c.BufferSize = *flagBufferSize
+	if _, err := cover.MakeSignalFunc(*flagSignalFn); err != nil {
+		return Config{}, err
+	}
+	c.Signal = *flagSignalFn
+	if *flagCgroups {
+		c.Flags |= FlagCgroups
+	}
+	c.MemoryLimit = *flagMemoryLimit << 20
+	c.PidsLimit = *flagPidsLimit
+	c.FdsLimit = *flagFdsLimit
+	c.CPULimit = *flagCPULimit
+	if *flagStringComps != NoStringComps && StringCompsSources[*flagStringComps] == nil {
+		return Config{}, fmt.Errorf("unknown string comparisons source %q", *flagStringComps)
+	}
+	c.StringComps = *flagStringComps
 	return c, nil
 }
 
@@ -115,8 +270,9 @@ type CallInfo struct {
 	Signal []uint32 // feedback signal, filled if FlagSignal is set
 	Cover  []uint32 // per-call coverage, filled if FlagSignal is set and cover == true,
 	//if dedup == false, then cov effectively contains a trace, otherwise duplicates are removed
-	Comps         prog.CompMap // per-call comparison operands
-	Errno         int          // call errno (0 if the call was successful)
+	Comps         prog.CompMap       // per-call comparison operands
+	StringComps   prog.StringCompMap // per-call byte-string comparison operands (see Config.StringComps)
+	Errno         int                // call errno (0 if the call was successful)
 	FaultInjected bool
 }
 
@@ -127,3 +283,11 @@ func GetCompMaps(info []CallInfo) []prog.CompMap {
 	}
 	return compMaps
 }
+
+func GetStringCompMaps(info []CallInfo) []prog.StringCompMap {
+	compMaps := make([]prog.StringCompMap, len(info))
+	for i, inf := range info {
+		compMaps[i] = inf.StringComps
+	}
+	return compMaps
+}
@@ -17,6 +17,7 @@ import (
 	"time"
 	"unsafe"
 
+	"github.com/google/syzkaller/pkg/cover"
 	"github.com/google/syzkaller/pkg/osutil"
 	"github.com/google/syzkaller/prog"
 )
@@ -31,6 +32,9 @@ type Env struct {
 	bin     []string
 	pid     int
 	config  Config
+	signal  cover.SignalFunc // nil if signal is computed by executor
+
+	strComps StringCompsSource // nil if byte-string comparisons are not collected
 
 	StatExecs    uint64
 	StatRestarts uint64
@@ -49,6 +53,16 @@ func MakeEnv(bin string, pid int, config Config) (*Env, error) {
 	if config.Timeout < 7*time.Second {
 		config.Timeout = 7 * time.Second
 	}
+	var signal cover.SignalFunc
+	if config.Flags&FlagSignal != 0 {
+		var err error
+		if signal, err = cover.MakeSignalFunc(config.Signal); err != nil {
+			return nil, err
+		}
+		if signal != nil {
+			config.Flags |= FlagRawCover
+		}
+	}
 	inf, inmem, err := createMapping(prog.ExecBufferSize)
 	if err != nil {
 		return nil, err
@@ -67,9 +81,14 @@ func MakeEnv(bin string, pid int, config Config) (*Env, error) {
 			closeMapping(outf, outmem)
 		}
 	}()
+	header := inmem
 	serializeUint64(inmem[0:], config.Flags)
 	serializeUint64(inmem[8:], uint64(pid))
-	inmem = inmem[16:]
+	serializeUint64(inmem[16:], config.MemoryLimit)
+	serializeUint64(inmem[24:], config.PidsLimit)
+	serializeUint64(inmem[32:], config.FdsLimit)
+	serializeUint64(inmem[40:], uint64((config.CPULimit+time.Second-1)/time.Second))
+	inmem = inmem[48:]
 	env := &Env{
 		in:      inmem,
 		out:     outmem,
@@ -78,6 +97,7 @@ func MakeEnv(bin string, pid int, config Config) (*Env, error) {
 		bin:     strings.Split(bin, " "),
 		pid:     pid,
 		config:  config,
+		signal:  signal,
 	}
 	if len(env.bin) == 0 {
 		return nil, fmt.Errorf("binary is empty string")
@@ -98,6 +118,24 @@ func MakeEnv(bin string, pid int, config Config) (*Env, error) {
 	if err := os.Link(env.bin[0], binCopy); err == nil {
 		env.bin[0] = binCopy
 	}
+	if config.StringComps != "" && config.StringComps != NoStringComps {
+		makeSource := StringCompsSources[config.StringComps]
+		if makeSource == nil {
+			return nil, fmt.Errorf("unknown string comparisons source %q", config.StringComps)
+		}
+		comm := filepath.Base(env.bin[0])
+		if len(comm) >= 16 {
+			comm = comm[:15]
+		}
+		var err error
+		if env.strComps, err = makeSource(comm); err != nil {
+			return nil, err
+		}
+		if env.strComps.CallMarker() != nil {
+			env.config.Flags |= FlagCallMarkers
+			serializeUint64(header[0:], env.config.Flags)
+		}
+	}
 	inf = nil
 	outf = nil
 	return env, nil
@@ -107,6 +145,9 @@ func (env *Env) Close() error {
 	if env.cmd != nil {
 		env.cmd.close()
 	}
+	if env.strComps != nil {
+		env.strComps.Close()
+	}
 	err1 := closeMapping(env.inFile, env.in)
 	err2 := closeMapping(env.outFile, env.out)
 	switch {
@@ -122,10 +163,12 @@ func (env *Env) Close() error {
 // Exec starts executor binary to execute program p and returns information about the execution:
 // output: process output
 // info: per-call info
+// usage: resources consumed by the program
 // failed: true if executor has detected a kernel bug
 // hanged: program hanged and was killed
 // err0: failed to start process, or executor has detected a logical error
-func (env *Env) Exec(opts *ExecOpts, p *prog.Prog) (output []byte, info []CallInfo, failed, hanged bool, err0 error) {
+func (env *Env) Exec(opts *ExecOpts, p *prog.Prog) (output []byte, info []CallInfo, usage ResourceUsage,
+	failed, hanged bool, err0 error) {
 	if p != nil {
 		// Copy-in serialized program.
 		if _, err := p.SerializeForExec(env.in, env.pid); err != nil {
@@ -133,22 +176,30 @@ func (env *Env) Exec(opts *ExecOpts, p *prog.Prog) (output []byte, info []CallIn
 			return
 		}
 	}
-	if env.config.Flags&FlagSignal != 0 {
-		// Zero out the first two words (ncmd and nsig), so that we don't have garbage there
-		// if executor crashes before writing non-garbage there.
-		for i := 0; i < 4; i++ {
-			env.out[i] = 0
-		}
+	// Zero out ncmd and resource usage, so that we don't have garbage there
+	// if executor crashes before writing non-garbage there.
+	for i := 0; i < (1+usageWords)*4; i++ {
+		env.out[i] = 0
 	}
 
 	atomic.AddUint64(&env.StatExecs, 1)
 	if env.cmd == nil {
 		atomic.AddUint64(&env.StatRestarts, 1)
-		env.cmd, err0 = makeCommand(env.pid, env.bin, env.config, env.inFile, env.outFile)
+		var callMarker *os.File
+		if env.strComps != nil {
+			callMarker = env.strComps.CallMarker()
+		}
+		env.cmd, err0 = makeCommand(env.pid, env.bin, env.config, env.inFile, env.outFile, callMarker)
 		if err0 != nil {
 			return
 		}
 	}
+	collectStrings := p != nil && env.strComps != nil && opts.Flags&FlagCollectComps != 0
+	if collectStrings {
+		if err0 = env.strComps.Start(); err0 != nil {
+			return
+		}
+	}
 	var restart bool
 	output, failed, hanged, restart, err0 = env.cmd.exec(opts)
 	if err0 != nil || restart {
@@ -157,15 +208,51 @@ func (env *Env) Exec(opts *ExecOpts, p *prog.Prog) (output []byte, info []CallIn
 		return
 	}
 
-	if p == nil || env.config.Flags&FlagSignal == 0 &&
-		env.config.Flags&FlagCollectComps == 0 {
+	if p == nil {
 		return
 	}
-	info, err0 = env.readOutCoverage(p)
+	usage = env.readOutUsage()
+	// Executor always writes per-call results (even without coverage),
+	// so info contains at least errno for every executed call.
+	info, err0 = env.readOutCoverage(opts, p)
+	if err0 == nil && collectStrings {
+		err0 = env.strComps.Collect(p, info)
+	}
+	if env.config.FdsLimit != 0 {
+		for _, inf := range info {
+			if inf.Errno == int(syscall.EMFILE) {
+				usage.LimitsHit |= LimitFds
+			}
+		}
+	}
 	return
 }
 
-func (env *Env) readOutCoverage(p *prog.Prog) (info []CallInfo, err0 error) {
+// Resource usage words that executor writes after the number of executed calls
+// (see kUsage* in executor.h).
+const (
+	usageMaxRSS = iota
+	usageCPUTime
+	usageFds
+	usagePids
+	usageMemory
+	usageLimits
+	usageWords
+)
+
+func (env *Env) readOutUsage() ResourceUsage {
+	out := ((*[1 << 28]uint32)(unsafe.Pointer(&env.out[0])))[1 : 1+usageWords]
+	return ResourceUsage{
+		MaxRSS:    uint64(out[usageMaxRSS]) << 10,
+		CPUTime:   time.Duration(out[usageCPUTime]) * time.Millisecond,
+		Fds:       int(out[usageFds]),
+		Pids:      int(out[usagePids]),
+		Memory:    uint64(out[usageMemory]) << 10,
+		LimitsHit: out[usageLimits],
+	}
+}
+
+func (env *Env) readOutCoverage(opts *ExecOpts, p *prog.Prog) (info []CallInfo, err0 error) {
 	out := ((*[1 << 28]uint32)(unsafe.Pointer(&env.out[0])))[:len(env.out)/int(unsafe.Sizeof(uint32(0)))]
 	readOut := func(v *uint32) bool {
 		if len(out) == 0 {
@@ -199,6 +286,11 @@ func (env *Env) readOutCoverage(p *prog.Prog) (info []CallInfo, err0 error) {
 		"executor %v: failed to read output coverage", env.pid) {
 		return
 	}
+	if len(out) < usageWords {
+		err0 = fmt.Errorf("executor %v: failed to read resource usage", env.pid)
+		return
+	}
+	out = out[usageWords:]
 	info = make([]CallInfo, len(p.Calls))
 	for i := range info {
 		info[i].Errno = -1 // not executed
@@ -254,6 +346,9 @@ func (env *Env) readOutCoverage(p *prog.Prog) (info []CallInfo, err0 error) {
 		}
 		info[callIndex].Cover = out[:coverSize:coverSize]
 		out = out[coverSize:]
+		if env.signal != nil && opts.Flags&FlagCollectComps == 0 {
+			env.deriveSignal(opts, &info[callIndex], int(callNum))
+		}
 		// Read out comparisons.
 		compMap := make(prog.CompMap)
 		for j := uint32(0); j < compsSize; j++ {
@@ -306,6 +401,22 @@ func (env *Env) readOutCoverage(p *prog.Prog) (info []CallInfo, err0 error) {
 	return
 }
 
+// deriveSignal computes signal from the raw coverage trace returned by executor
+// and then brings coverage into the form requested in opts.
+func (env *Env) deriveSignal(opts *ExecOpts, inf *CallInfo, call int) {
+	inf.Signal = env.signal(call, inf.Errno, inf.Cover)
+	if inf.Signal == nil {
+		// Non-nil signal is used to detect double coverage for a call.
+		inf.Signal = []uint32{}
+	}
+	switch {
+	case opts.Flags&FlagCollectCover == 0:
+		inf.Cover = nil
+	case opts.Flags&FlagDedupCover != 0:
+		inf.Cover = cover.Canonicalize(inf.Cover)
+	}
+}
+
 func createMapping(size int) (f *os.File, mem []byte, err error) {
 	f, err = ioutil.TempFile("./", "syzkaller-shm")
 	if err != nil {
@@ -363,7 +474,7 @@ type command struct {
 	outwp    *os.File
 }
 
-func makeCommand(pid int, bin []string, config Config, inFile *os.File, outFile *os.File) (*command, error) {
+func makeCommand(pid int, bin []string, config Config, inFile, outFile, callMarker *os.File) (*command, error) {
 	dir, err := ioutil.TempDir("./", "syzkaller-testdir")
 	if err != nil {
 		return nil, fmt.Errorf("failed to create temp dir: %v", err)
@@ -414,6 +525,9 @@ func makeCommand(pid int, bin []string, config Config, inFile *os.File, outFile
 
 	cmd := exec.Command(bin[0], bin[1:]...)
 	cmd.ExtraFiles = []*os.File{inFile, outFile, outrp, inwp}
+	if callMarker != nil {
+		cmd.ExtraFiles = append(cmd.ExtraFiles, callMarker)
+	}
 	cmd.Env = []string{}
 	cmd.Dir = dir
 	if config.Flags&FlagDebug == 0 {
@@ -479,36 +593,53 @@ func (c *command) close() {
 	}
 }
 
-// Wait for executor to start serving (sandbox setup can take significant time).
+// waitServing waits for executor to report that it has started serving
+// (sandbox setup can take significant time). If executor exits or reports
+// a non-zero status instead, it returns ExecutorFailure classified by the exit status,
+// or a plain error with executor output if the status is not recognized.
+// It returns ExecutorFailure with FailureTimeout if executor does not respond within a minute.
 func (c *command) waitServing() error {
-	read := make(chan error, 1)
+	type readResult struct {
+		status int
+		err    error
+	}
+	read := make(chan readResult, 1)
 	go func() {
 		var buf [1]byte
 		_, err := c.inrp.Read(buf[:])
-		read <- err
+		read <- readResult{int(buf[0]), err}
 	}()
 	timeout := time.NewTimer(time.Minute)
 	select {
-	case err := <-read:
+	case res := <-read:
 		timeout.Stop()
-		if err != nil {
-			c.abort()
-			output := <-c.readDone
-			err = fmt.Errorf("executor is not serving: %v\n%s", err, output)
-			c.wait()
-			if c.cmd.ProcessState != nil {
-				sys := c.cmd.ProcessState.Sys()
-				if ws, ok := sys.(syscall.WaitStatus); ok {
-					// Magic values returned by executor.
-					if ws.ExitStatus() == statusFail {
-						err = ExecutorFailure(fmt.Sprintf("executor is not serving:\n%s", output))
-					}
-				}
+		if res.err == nil && res.status == 0 {
+			return nil
+		}
+		// Either executor has exited, or it has written its exit status to the pipe.
+		c.abort()
+		output := <-c.readDone
+		err := fmt.Errorf("executor is not serving: %v\n%s", res.err, output)
+		c.wait()
+		status := res.status
+		if c.cmd.ProcessState != nil {
+			if ws, ok := c.cmd.ProcessState.Sys().(syscall.WaitStatus); ok && ws.Exited() {
+				status = ws.ExitStatus()
+			}
+		}
+		// Magic values returned by executor.
+		if kind, ok := failureKindForStatus(status); ok {
+			err = ExecutorFailure{
+				Kind: kind,
+				Msg:  fmt.Sprintf("executor is not serving:\n%s", output),
 			}
 		}
 		return err
 	case <-timeout.C:
-		return fmt.Errorf("executor is not serving")
+		return ExecutorFailure{
+			Kind: FailureTimeout,
+			Msg:  "executor is not serving",
+		}
 	}
 }
 
@@ -548,10 +679,13 @@ func (c *command) exec(opts *ExecOpts) (output []byte, failed, hanged, restart b
 	if opts.Flags&FlagInjectFault != 0 {
 		enableFaultOnce.Do(enableFaultInjection)
 	}
-	var inCmd [24]byte
+	var inCmd [48]byte
 	serializeUint64(inCmd[0:], opts.Flags)
 	serializeUint64(inCmd[8:], uint64(opts.FaultCall))
 	serializeUint64(inCmd[16:], uint64(opts.FaultNth))
+	serializeUint64(inCmd[24:], uint64(opts.RaceCall1))
+	serializeUint64(inCmd[32:], uint64(opts.RaceCall2))
+	serializeUint64(inCmd[40:], uint64(opts.RaceDelay))
 	if _, err := c.outwp.Write(inCmd[:]); err != nil {
 		output = <-c.readDone
 		err0 = fmt.Errorf("failed to write control pipe: %v", err)
@@ -597,9 +731,14 @@ func (c *command) exec(opts *ExecOpts) (output []byte, failed, hanged, restart b
 		output = append(output, '\n')
 	}
 	// Handle magic values returned by executor.
+	if kind, ok := failureKindForStatus(status); ok {
+		err0 = ExecutorFailure{
+			Kind: kind,
+			Msg:  fmt.Sprintf("executor failed: %s", output),
+		}
+		return
+	}
 	switch status {
-	case statusFail:
-		err0 = ExecutorFailure(fmt.Sprintf("executor failed: %s", output))
 	case statusError:
 		err0 = fmt.Errorf("executor detected kernel bug")
 		failed = true
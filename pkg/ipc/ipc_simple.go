@@ -60,7 +60,9 @@ func (env *Env) Close() error {
 	return nil
 }
 
-func (env *Env) Exec(opts *ExecOpts, p *prog.Prog) (output []byte, info []CallInfo, failed, hanged bool, err0 error) {
+// Exec executes program p, resource usage is not supported on this OS and is always zero.
+func (env *Env) Exec(opts *ExecOpts, p *prog.Prog) (output []byte, info []CallInfo, usage ResourceUsage,
+	failed, hanged bool, err0 error) {
 	atomic.AddUint64(&env.StatExecs, 1)
 	dir, err := ioutil.TempDir("./", "syzkaller-testdir")
 	if err != nil {
@@ -74,7 +74,7 @@ func TestEmptyProg(t *testing.T) {
 
 	p := new(prog.Prog)
 	opts := &ExecOpts{}
-	output, _, failed, hanged, err := env.Exec(opts, p)
+	output, _, _, failed, hanged, err := env.Exec(opts, p)
 	if err != nil {
 		t.Fatalf("failed to run executor: %v", err)
 	}
@@ -113,7 +113,7 @@ func TestExecute(t *testing.T) {
 		for i := 0; i < iters/len(flags); i++ {
 			p := target.Generate(rs, 10, nil)
 			opts := &ExecOpts{}
-			output, _, _, _, err := env.Exec(opts, p)
+			output, _, _, _, _, err := env.Exec(opts, p)
 			if err != nil {
 				t.Logf("program:\n%s\n", p.Serialize())
 				t.Fatalf("failed to run executor: %v\n%s", err, output)
@@ -121,3 +121,63 @@ func TestExecute(t *testing.T) {
 		}
 	}
 }
+
+func TestExecutorFailureKind(t *testing.T) {
+	if osutil.IsExist("/sys/kernel/debug/kcov") {
+		t.Skip("kcov is present, can't provoke kcov failure")
+	}
+	target, err := prog.GetTarget("linux", runtime.GOARCH)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bin := buildExecutor(t, target)
+	defer os.Remove(bin)
+
+	cfg := Config{
+		Flags:   FlagSignal,
+		Timeout: timeout,
+	}
+	env, err := MakeEnv(bin, 0, cfg)
+	if err != nil {
+		t.Fatalf("failed to create env: %v", err)
+	}
+	defer env.Close()
+	_, _, _, _, _, err = env.Exec(&ExecOpts{}, new(prog.Prog))
+	failure, ok := err.(ExecutorFailure)
+	if !ok {
+		t.Fatalf("want ExecutorFailure, got: %v", err)
+	}
+	if failure.Kind != FailureKcov {
+		t.Fatalf("want %v failure, got %v: %v", FailureKcov, failure.Kind, failure)
+	}
+}
+
+func TestResourceUsage(t *testing.T) {
+	target, err := prog.GetTarget("linux", runtime.GOARCH)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bin := buildExecutor(t, target)
+	defer os.Remove(bin)
+
+	cfg := Config{
+		Timeout:  timeout,
+		FdsLimit: 20,
+		CPULimit: 2 * time.Second,
+	}
+	env, err := MakeEnv(bin, 0, cfg)
+	if err != nil {
+		t.Fatalf("failed to create env: %v", err)
+	}
+	defer env.Close()
+	_, _, usage, _, _, err := env.Exec(&ExecOpts{}, new(prog.Prog))
+	if err != nil {
+		t.Fatalf("failed to run executor: %v", err)
+	}
+	if usage.MaxRSS == 0 || usage.Fds == 0 || usage.Fds > 20 {
+		t.Fatalf("bad resource usage: %+v", usage)
+	}
+	if usage.LimitsHit != 0 {
+		t.Fatalf("empty program hit limits: %+v", usage)
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package ipc
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/prog"
+)
+
+func init() {
+	StringCompsSources["kprobe"] = makeKprobeStringComps
+}
+
+// kprobeStringComps collects operands of string comparison functions in kernel
+// with kprobe trace events. Every Env uses own tracing instance filtered by executor process name.
+// Executor writes call markers to the instance trace_marker, operands are attributed
+// to the call that was running in the same thread.
+type kprobeStringComps struct {
+	dir    string   // tracing instance directory
+	marker *os.File // trace_marker of the instance, passed to executor
+}
+
+const maxStringComps = 1000 // per call
+
+var (
+	kprobeFuncs = []string{"strcmp", "strncmp", "memcmp"}
+	// Registers that hold the first 3 function arguments.
+	kprobeRegs = map[string][3]string{
+		"amd64":   {"%di", "%si", "%dx"},
+		"arm64":   {"%x0", "%x1", "%x2"},
+		"ppc64le": {"%r3", "%r4", "%r5"},
+	}
+	kprobeOnce sync.Once
+	kprobeErr  error
+	// Example: syz-executor0-1234  [001] ....  12.345: syz_strncmp: (strncmp+0x0/0x60) a="ext4" b="vfat" n=4
+	kprobeTraceRe = regexp.MustCompile(`^\s*.*?-([0-9]+)\s.*: syz_[a-z]+: \(.*?\) a="(.*)" b="(.*)"(?: n=([0-9]+))?$`)
+	// Example: syz-executor0-1234  [001] ....  12.345: tracing_mark_write: syz-call 2
+	kprobeMarkerRe = regexp.MustCompile(`^\s*.*?-([0-9]+)\s.*: tracing_mark_write: syz-call (end|[0-9]+)$`)
+)
+
+func tracingDir() string {
+	if osutil.IsExist("/sys/kernel/tracing/kprobe_events") {
+		return "/sys/kernel/tracing"
+	}
+	return "/sys/kernel/debug/tracing"
+}
+
+func setupKprobes(tracing string) error {
+	regs, ok := kprobeRegs[runtime.GOARCH]
+	if !ok {
+		return fmt.Errorf("kprobe string comparisons are not supported on %v", runtime.GOARCH)
+	}
+	f, err := os.OpenFile(filepath.Join(tracing, "kprobe_events"), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open kprobe_events: %v", err)
+	}
+	defer f.Close()
+	for _, fn := range kprobeFuncs {
+		event := fmt.Sprintf("p:syz_%v %v a=+0(%v):string b=+0(%v):string", fn, fn, regs[0], regs[1])
+		if fn != "strcmp" {
+			event += fmt.Sprintf(" n=%v:u64", regs[2])
+		}
+		// Write fails if the event already exists (e.g. created by a previous fuzzer),
+		// so we check for existence of the event below instead.
+		f.WriteString(event + "\n")
+		if !osutil.IsExist(filepath.Join(tracing, "events", "kprobes", "syz_"+fn)) {
+			return fmt.Errorf("failed to create kprobe event for %v", fn)
+		}
+	}
+	return nil
+}
+
+func makeKprobeStringComps(comm string) (StringCompsSource, error) {
+	tracing := tracingDir()
+	kprobeOnce.Do(func() {
+		kprobeErr = setupKprobes(tracing)
+	})
+	if kprobeErr != nil {
+		return nil, kprobeErr
+	}
+	dir := filepath.Join(tracing, "instances", "syz-"+comm)
+	if err := os.Mkdir(dir, 0700); err != nil && !os.IsExist(err) {
+		return nil, fmt.Errorf("failed to create tracing instance: %v", err)
+	}
+	for _, fn := range kprobeFuncs {
+		event := filepath.Join(dir, "events", "kprobes", "syz_"+fn)
+		filter := fmt.Sprintf("comm == \"%v\"", comm)
+		if err := osutil.WriteFile(filepath.Join(event, "filter"), []byte(filter)); err != nil {
+			return nil, fmt.Errorf("failed to set kprobe event filter: %v", err)
+		}
+		if err := osutil.WriteFile(filepath.Join(event, "enable"), []byte("1")); err != nil {
+			return nil, fmt.Errorf("failed to enable kprobe event: %v", err)
+		}
+	}
+	marker, err := os.OpenFile(filepath.Join(dir, "trace_marker"), os.O_WRONLY, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open trace marker: %v", err)
+	}
+	return &kprobeStringComps{dir: dir, marker: marker}, nil
+}
+
+func (src *kprobeStringComps) Start() error {
+	// Opening trace with O_TRUNC clears the trace buffer.
+	f, err := os.OpenFile(filepath.Join(src.dir, "trace"), os.O_WRONLY|os.O_TRUNC, 0)
+	if err != nil {
+		return fmt.Errorf("failed to clear trace: %v", err)
+	}
+	return f.Close()
+}
+
+func (src *kprobeStringComps) Collect(p *prog.Prog, info []CallInfo) error {
+	data, err := ioutil.ReadFile(filepath.Join(src.dir, "trace"))
+	if err != nil {
+		return fmt.Errorf("failed to read trace: %v", err)
+	}
+	comps := parseKprobeTrace(data, len(info))
+	for i := range info {
+		info[i].StringComps = comps[i]
+	}
+	return nil
+}
+
+func (src *kprobeStringComps) CallMarker() *os.File {
+	return src.marker
+}
+
+func (src *kprobeStringComps) Close() error {
+	src.marker.Close()
+	for _, fn := range kprobeFuncs {
+		osutil.WriteFile(filepath.Join(src.dir, "events", "kprobes", "syz_"+fn, "enable"), []byte("0"))
+	}
+	return os.Remove(src.dir)
+}
+
+// parseKprobeTrace returns comparison operands of each of ncalls calls (nil if the call has none).
+// Comparisons are attributed to the call that was running in the same thread according to call markers,
+// comparisons outside of calls are dropped.
+func parseKprobeTrace(data []byte, ncalls int) []prog.StringCompMap {
+	comps := make([]prog.StringCompMap, ncalls)
+	running := make(map[string]int) // thread id -> index of the call it executes
+	for _, line := range strings.Split(string(data), "\n") {
+		if match := kprobeMarkerRe.FindStringSubmatch(line); match != nil {
+			idx, err := strconv.Atoi(match[2])
+			if match[2] == "end" || err != nil || idx >= ncalls {
+				delete(running, match[1])
+			} else {
+				running[match[1]] = idx
+			}
+			continue
+		}
+		match := kprobeTraceRe.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		idx, ok := running[match[1]]
+		if !ok {
+			continue
+		}
+		if len(comps[idx]) >= maxStringComps {
+			continue
+		}
+		op1, op2 := match[2], match[3]
+		if match[4] != "" {
+			n, err := strconv.ParseUint(match[4], 10, 64)
+			if err != nil {
+				continue
+			}
+			if uint64(len(op1)) > n {
+				op1 = op1[:n]
+			}
+			if uint64(len(op2)) > n {
+				op2 = op2[:n]
+			}
+		}
+		if op1 == op2 || op1 == "" || op2 == "" {
+			continue
+		}
+		if comps[idx] == nil {
+			comps[idx] = make(prog.StringCompMap)
+		}
+		comps[idx].AddComp([]byte(op1), []byte(op2))
+		comps[idx].AddComp([]byte(op2), []byte(op1))
+	}
+	return comps
+}
@@ -0,0 +1,42 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package ipc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/syzkaller/prog"
+)
+
+func TestParseKprobeTrace(t *testing.T) {
+	trace := `# tracer: nop
+#
+ syz-executor0-1234  [001] ....    12.340: syz_strcmp: (strcmp+0x0/0x30) a="before" b="calls"
+ syz-executor0-1234  [001] ....    12.341: tracing_mark_write: syz-call 0
+ syz-executor0-1234  [001] ....    12.345: syz_strcmp: (strcmp+0x0/0x30) a="ext4" b="vfat"
+ syz-executor0-1235  [002] ....    12.345: tracing_mark_write: syz-call 2
+ syz-executor0-1234  [001] ....    12.346: syz_strncmp: (strncmp+0x0/0x60) a="ext3" b="ext4dev" n=4
+ syz-executor0-1235  [002] ....    12.346: syz_strcmp: (strcmp+0x0/0x30) a="ext4" b="vfat"
+ syz-executor0-1234  [001] ....    12.347: syz_memcmp: (memcmp+0x0/0x60) a="foo" b="foo" n=3
+ syz-executor0-1234  [001] ....    12.348: syz_memcmp: (memcmp+0x0/0x60) a="" b="bar" n=3
+ syz-executor0-1234  [001] ....    12.349: syz_strcmp: (strcmp+0x0/0x30) a="ext4" b="vfat"
+ syz-executor0-1234  [001] ....    12.350: tracing_mark_write: syz-call end
+ syz-executor0-1234  [001] ....    12.351: syz_strcmp: (strcmp+0x0/0x30) a="after" b="calls"
+ syz-executor0-1235  [002] ....    12.352: tracing_mark_write: syz-call end
+`
+	comps := parseKprobeTrace([]byte(trace), 3)
+	want0 := prog.StringCompMap{}
+	want0.AddComp([]byte("ext4"), []byte("vfat"))
+	want0.AddComp([]byte("vfat"), []byte("ext4"))
+	want0.AddComp([]byte("ext3"), []byte("ext4"))
+	want0.AddComp([]byte("ext4"), []byte("ext3"))
+	want2 := prog.StringCompMap{}
+	want2.AddComp([]byte("ext4"), []byte("vfat"))
+	want2.AddComp([]byte("vfat"), []byte("ext4"))
+	want := []prog.StringCompMap{want0, nil, want2}
+	if !reflect.DeepEqual(comps, want) {
+		t.Fatalf("got: %v\nwant: %v", comps, want)
+	}
+}
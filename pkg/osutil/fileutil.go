@@ -8,6 +8,7 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 )
 
 // CopyFile atomically copies oldFile to newFile preserving permissions and modification time.
@@ -54,3 +55,15 @@ func WriteTempFile(data []byte) (string, error) {
 	f.Close()
 	return f.Name(), nil
 }
+
+// DiskUsage returns total size of files in path (recursively if path is a dir).
+func DiskUsage(path string) int64 {
+	var size int64
+	filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
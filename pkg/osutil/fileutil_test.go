@@ -63,3 +63,38 @@ func TestProcessTempDir(t *testing.T) {
 		}()
 	}
 }
+
+func TestRemoveStaleProcessTempDirs(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "syz")
+	if err != nil {
+		t.Fatalf("failed to create a temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+	live, err := ProcessTempDir(tmp)
+	if err != nil {
+		t.Fatalf("failed to create process temp dir: %v", err)
+	}
+	stale, err := ProcessTempDir(tmp)
+	if err != nil {
+		t.Fatalf("failed to create process temp dir: %v", err)
+	}
+	if err := WriteFile(filepath.Join(stale, ".pid"), []byte(strconv.Itoa(999999999))); err != nil {
+		t.Fatalf("failed to write pid file: %v", err)
+	}
+	if err := WriteFile(filepath.Join(stale, "image"), make([]byte, 100)); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	reclaimed, err := RemoveStaleProcessTempDirs(tmp)
+	if err != nil {
+		t.Fatalf("failed to remove stale dirs: %v", err)
+	}
+	if reclaimed != 109 {
+		t.Errorf("reclaimed %v bytes, want 109", reclaimed)
+	}
+	if IsExist(stale) {
+		t.Errorf("stale dir %v is not removed", stale)
+	}
+	if !IsExist(live) {
+		t.Errorf("live dir %v is removed", live)
+	}
+}
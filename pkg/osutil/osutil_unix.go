@@ -19,16 +19,11 @@ import (
 // ProcessTempDir creates a new temp dir in where and returns its path and an unique index.
 // It also cleans up old, unused temp dirs after dead processes.
 func ProcessTempDir(where string) (string, error) {
-	lk := filepath.Join(where, "instance-lock")
-	lkf, err := syscall.Open(lk, syscall.O_RDWR|syscall.O_CREAT, DefaultFilePerm)
+	unlock, err := lockProcessTempDirs(where)
 	if err != nil {
 		return "", err
 	}
-	defer syscall.Close(lkf)
-	if err := syscall.Flock(lkf, syscall.LOCK_EX); err != nil {
-		return "", err
-	}
-	defer syscall.Flock(lkf, syscall.LOCK_UN)
+	defer unlock()
 
 	for i := 0; i < 1e3; i++ {
 		path := filepath.Join(where, fmt.Sprintf("instance-%v", i))
@@ -36,21 +31,9 @@ func ProcessTempDir(where string) (string, error) {
 		err := os.Mkdir(path, DefaultDirPerm)
 		if os.IsExist(err) {
 			// Try to clean up.
-			data, err := ioutil.ReadFile(pidfile)
-			if err == nil && len(data) > 0 {
-				pid, err := strconv.Atoi(string(data))
-				if err == nil && pid > 1 {
-					if err := syscall.Kill(pid, 0); err == syscall.ESRCH {
-						if os.Remove(pidfile) == nil {
-							if os.RemoveAll(path) == nil {
-								i--
-								continue
-							}
-						}
-					}
-				}
+			if removeStaleProcessTempDir(path) {
+				i--
 			}
-			// If err != nil, assume that the pid file is not created yet.
 			continue
 		}
 		if err != nil {
@@ -64,6 +47,62 @@ func ProcessTempDir(where string) (string, error) {
 	return "", fmt.Errorf("too many live instances")
 }
 
+// RemoveStaleProcessTempDirs removes temp dirs created by ProcessTempDir in where
+// that belong to processes that are not alive anymore. Returns the number of reclaimed bytes.
+func RemoveStaleProcessTempDirs(where string) (int64, error) {
+	unlock, err := lockProcessTempDirs(where)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+	dirs, err := filepath.Glob(filepath.Join(where, "instance-[0-9]*"))
+	if err != nil {
+		return 0, err
+	}
+	var reclaimed int64
+	for _, path := range dirs {
+		size := DiskUsage(path)
+		if removeStaleProcessTempDir(path) {
+			reclaimed += size
+		}
+	}
+	return reclaimed, nil
+}
+
+func lockProcessTempDirs(where string) (func(), error) {
+	lk := filepath.Join(where, "instance-lock")
+	lkf, err := syscall.Open(lk, syscall.O_RDWR|syscall.O_CREAT, DefaultFilePerm)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(lkf, syscall.LOCK_EX); err != nil {
+		syscall.Close(lkf)
+		return nil, err
+	}
+	return func() {
+		syscall.Flock(lkf, syscall.LOCK_UN)
+		syscall.Close(lkf)
+	}, nil
+}
+
+// removeStaleProcessTempDir removes path if the process that created it is dead.
+// If the pid file is not readable, assume that it is not created yet.
+func removeStaleProcessTempDir(path string) bool {
+	pidfile := filepath.Join(path, ".pid")
+	data, err := ioutil.ReadFile(pidfile)
+	if err != nil || len(data) == 0 {
+		return false
+	}
+	pid, err := strconv.Atoi(string(data))
+	if err != nil || pid <= 1 {
+		return false
+	}
+	if err := syscall.Kill(pid, 0); err != syscall.ESRCH {
+		return false
+	}
+	return os.Remove(pidfile) == nil && os.RemoveAll(path) == nil
+}
+
 // HandleInterrupts closes shutdown chan on first SIGINT
 // (expecting that the program will gracefully shutdown and exit)
 // and terminates the process on third SIGINT.
@@ -0,0 +1,72 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"bytes"
+	"regexp"
+)
+
+// Leak is a single object reported by kmemleak.
+type Leak struct {
+	// Site is the allocation site signature: the first function in the allocation
+	// backtrace that is not part of the memory allocator.
+	Site   string
+	Report []byte
+}
+
+var (
+	leakStart      = []byte("unreferenced object ")
+	leakBacktrace  = []byte("backtrace:")
+	leakFrameRe    = regexp.MustCompile(`^\s*\[\<[0-9a-f]+\>\]\s+([a-zA-Z0-9_.]+)\+0x[0-9a-f]+/0x[0-9a-f]+`)
+	leakAllocators = regexp.MustCompile(`^(create_object|kmemleak_alloc.*|kmem_cache_alloc.*|__kmalloc.*|kmalloc.*|` +
+		`__do_kmalloc.*|kzalloc.*|kcalloc.*|kmemdup.*|kstrdup.*|kstrndup|krealloc|__krealloc|kvmalloc.*|` +
+		`__vmalloc.*|vmalloc.*|vzalloc.*|slab_post_alloc_hook|slab_alloc.*|__slab_alloc.*|alloc_pages.*|` +
+		`__alloc_pages.*|__get_free_pages|kmem_cache_zalloc|kmem_cache_alloc_node.*)$`)
+)
+
+// ParseLeaks parses kmemleak output (contents of /sys/kernel/debug/kmemleak)
+// into individual leaked objects. Objects without a usable backtrace are skipped.
+func ParseLeaks(output []byte) []*Leak {
+	var leaks []*Leak
+	for pos := bytes.Index(output, leakStart); pos != -1; {
+		end := bytes.Index(output[pos+len(leakStart):], leakStart)
+		if end == -1 {
+			end = len(output)
+		} else {
+			end += pos + len(leakStart)
+		}
+		text := output[pos:end]
+		if site := leakSite(text); site != "" {
+			leaks = append(leaks, &Leak{
+				Site:   site,
+				Report: text,
+			})
+		}
+		if end == len(output) {
+			break
+		}
+		pos = end
+	}
+	return leaks
+}
+
+func leakSite(text []byte) string {
+	bt := bytes.Index(text, leakBacktrace)
+	if bt == -1 {
+		return ""
+	}
+	site := ""
+	for _, line := range bytes.Split(text[bt+len(leakBacktrace):], []byte{'\n'}) {
+		match := leakFrameRe.FindSubmatch(line)
+		if match == nil {
+			continue
+		}
+		site = string(match[1])
+		if !leakAllocators.MatchString(site) {
+			break
+		}
+	}
+	return site
+}
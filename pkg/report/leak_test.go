@@ -0,0 +1,58 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLeaks(t *testing.T) {
+	output := `
+unreferenced object 0xffff8800342540c0 (size 1864):
+  comm "a.out", pid 24109, jiffies 4299060398 (age 27.984s)
+  hex dump (first 32 bytes):
+    00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00  ................
+  backtrace:
+    [<ffffffff85c73a22>] kmemleak_alloc+0x72/0xc0 mm/kmemleak.c:915
+    [<ffffffff816cc14d>] kmem_cache_alloc+0x12d/0x2c0 mm/slub.c:2607
+    [<ffffffff84b642c9>] sk_prot_alloc+0x69/0x340 net/core/sock.c:1344
+    [<ffffffff84b6d36a>] sk_alloc+0x3a/0x6b0 net/core/sock.c:1419
+unreferenced object 0xffff880133c63800 (size 1024):
+  comm "exe", pid 1521, jiffies 4294894652
+  backtrace:
+    [<ffffffff810f8f36>] create_object+0x126/0x2b0
+    [<ffffffff810f91d5>] kmemleak_alloc+0x25/0x60
+    [<ffffffff810f32a3>] __kmalloc+0x113/0x200
+    [<ffffffff811aa061>] ext4_mb_init+0x1b1/0x570
+    [<ffffffff8119b3d2>] ext4_fill_super+0x1de2/0x26d0
+unreferenced object 0xffff880133c63000 (size 64):
+  comm "exe", pid 1521, jiffies 4294894652
+unreferenced object 0xdb8040c0 (size 20):
+  comm "swapper", pid 0, jiffies 4294667296
+  backtrace:
+    [<c04fd8b3>] kmemleak_alloc+0x193/0x2b8
+    [<c04f5e73>] kmem_cache_alloc+0x11e/0x174
+    [<c0aae5a7>] debug_objects_mem_init+0x63/0x1d9
+`
+	leaks := ParseLeaks([]byte(output))
+	want := []string{"sk_prot_alloc", "ext4_mb_init", "debug_objects_mem_init"}
+	if len(leaks) != len(want) {
+		t.Fatalf("got %v leaks, want %v", len(leaks), len(want))
+	}
+	for i, leak := range leaks {
+		if leak.Site != want[i] {
+			t.Errorf("leak #%v: got site %q, want %q", i, leak.Site, want[i])
+		}
+		if !strings.HasPrefix(string(leak.Report), "unreferenced object ") {
+			t.Errorf("leak #%v: bad report:\n%s", i, leak.Report)
+		}
+		if !strings.Contains(string(leak.Report), want[i]) {
+			t.Errorf("leak #%v: report does not contain the site:\n%s", i, leak.Report)
+		}
+	}
+	if leaks := ParseLeaks(nil); len(leaks) != 0 {
+		t.Fatalf("got leaks in empty output: %+v", leaks)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/hash"
+)
+
+// Number of frames used for stack signature.
+const signatureFrames = 5
+
+var (
+	// Either "func+0x1/0x2 [file:line]" or a symbolized inline frame "func file:line [inline]".
+	stackFrameRe = regexp.MustCompile(`^\s*(?:\[\<[0-9a-f]+\>\]\s*)?([a-zA-Z_][a-zA-Z0-9_.]*)` +
+		`(?:\+0x[0-9a-f]+/0x[0-9a-f]+(?:\s+\S+:[0-9]+)?|\s+\S+:[0-9]+\s+\[inline\])\s*$`)
+	stackStart = [][]byte{
+		[]byte("Call Trace:"),
+		[]byte("backtrace:"),
+	}
+	// Frames that are common to many unrelated crashes: reporting machinery,
+	// trap/fault handlers and syscall entry code.
+	genericFrames = regexp.MustCompile(`^(__dump_stack|dump_stack|show_stack|print_address_description|` +
+		`kasan_.*|__kasan_.*|__asan_.*|check_memory_region.*|kmsan_.*|__msan_.*|__ubsan_.*|ubsan_.*|` +
+		`handle_overflow|panic|__warn|warn_slowpath.*|report_bug|fixup_bug|do_trap.*|do_error_trap|` +
+		`do_invalid_op|invalid_op|do_general_protection|general_protection|page_fault|do_page_fault|` +
+		`__do_page_fault|no_context|bad_area.*|__bad_area.*|print_.*_bug|__might_sleep|___might_sleep|` +
+		`__might_fault|lockdep_.*|__lock_acquire|lock_acquire|check_.*_bug|debug_check_.*|` +
+		`kmemleak_.*|create_object|entry_SYSCALL.*|do_syscall_.*|do_fast_syscall.*|SyS_.*|SYSC_.*|` +
+		`__x64_sys_.*|__ia32_sys_.*|__se_sys_.*|__do_sys_.*|ret_from_fork|kthread)$`)
+)
+
+// StackFrames returns normalized top frames of the first stack trace in a symbolized report
+// that are used for the stack signature. Generic frames (reporting, fault handling,
+// syscall entry) and compiler suffixes like .isra.0 or .constprop.1 are removed.
+func StackFrames(report []byte) []string {
+	for _, start := range stackStart {
+		if pos := bytes.Index(report, start); pos != -1 {
+			report = report[pos+len(start):]
+			break
+		}
+	}
+	var frames []string
+	for _, line := range bytes.Split(report, []byte{'\n'}) {
+		match := stackFrameRe.FindSubmatch(line)
+		if match == nil {
+			continue
+		}
+		frame := string(match[1])
+		if dot := strings.IndexByte(frame, '.'); dot != -1 {
+			frame = frame[:dot]
+		}
+		if genericFrames.MatchString(frame) {
+			continue
+		}
+		if len(frames) != 0 && frames[len(frames)-1] == frame {
+			continue
+		}
+		frames = append(frames, frame)
+		if len(frames) == signatureFrames {
+			break
+		}
+	}
+	return frames
+}
+
+// StackSignature returns a hash of StackFrames of the report, or an empty string
+// if the report does not contain a stack trace. Crashes with the same title,
+// but different signatures are likely different bugs.
+func StackSignature(report []byte) string {
+	frames := StackFrames(report)
+	if len(frames) == 0 {
+		return ""
+	}
+	return hash.String([]byte(strings.Join(frames, "\n")))
+}
@@ -0,0 +1,108 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestStackFrames(t *testing.T) {
+	tests := []struct {
+		report string
+		frames []string
+	}{
+		{
+			report: `
+BUG: KASAN: use-after-free in ip6_send_skb+0x2f5/0x330 net/ipv6/ip6_output.c:1748
+Read of size 8 at addr ffff88004fab1858 by task syz-executor0/30168
+
+CPU: 0 PID: 30168 Comm: syz-executor0 Not tainted 4.12.0-rc3+ #3
+Call Trace:
+ __dump_stack lib/dump_stack.c:16 [inline]
+ dump_stack+0x292/0x395 lib/dump_stack.c:52
+ print_address_description+0x78/0x280 mm/kasan/report.c:252
+ kasan_report_error mm/kasan/report.c:351 [inline]
+ kasan_report+0x230/0x340 mm/kasan/report.c:408
+ __asan_report_load8_noabort+0x19/0x20 mm/kasan/report.c:429
+ ip6_send_skb+0x2f5/0x330 net/ipv6/ip6_output.c:1748
+ ip6_push_pending_frames+0xb8/0xe0 net/ipv6/ip6_output.c:1763
+ rawv6_push_pending_frames net/ipv6/raw.c:613 [inline]
+ rawv6_sendmsg+0x2ede/0x4400 net/ipv6/raw.c:932
+ inet_sendmsg+0x169/0x5c0 net/ipv4/af_inet.c:762
+ sock_sendmsg_nosec net/socket.c:633 [inline]
+ sock_sendmsg+0xcf/0x110 net/socket.c:643
+ SYSC_sendto+0x660/0x810 net/socket.c:1696
+ SyS_sendto+0x45/0x60 net/socket.c:1664
+ entry_SYSCALL_64_fastpath+0x1f/0xbe
+RIP: 0033:0x446179
+`,
+			frames: []string{"ip6_send_skb", "ip6_push_pending_frames", "rawv6_push_pending_frames",
+				"rawv6_sendmsg", "inet_sendmsg"},
+		},
+		{
+			report: `
+WARNING: CPU: 1 PID: 4150 at net/core/stream.c:207 sk_stream_kill_queues+0x3e3/0x4d0
+Kernel panic - not syncing: panic_on_warn set ...
+
+Call Trace:
+ [<ffffffff8195c8b3>] dump_stack+0x80/0xb3
+ [<ffffffff81127a31>] panic+0x1b1/0x3c4
+ [<ffffffff81127c15>] ? __warn+0x1cd/0x210
+ [<ffffffff81127c15>] __warn+0x1cd/0x210
+ [<ffffffff81127d1c>] warn_slowpath_null+0x2c/0x40
+ [<ffffffff82b1dd43>] sk_stream_kill_queues+0x3e3/0x4d0
+ [<ffffffff82c5e7bf>] inet_csk_destroy_sock.isra.12+0x14f/0x380
+ [<ffffffff82c5e7bf>] inet_csk_destroy_sock.isra.12+0x14f/0x380
+ [<ffffffff82c80b9f>] tcp_done+0x1ff/0x2a0
+`,
+			frames: []string{"sk_stream_kill_queues", "inet_csk_destroy_sock", "tcp_done"},
+		},
+		{
+			report: `
+BUG: soft lockup - CPU#0 stuck for 22s! [syz-executor0:4211]
+RIP: 0010:foo+0x10/0x20
+`,
+			frames: nil,
+		},
+	}
+	for i, test := range tests {
+		frames := StackFrames([]byte(test.report))
+		if !reflect.DeepEqual(frames, test.frames) {
+			t.Errorf("test #%v: got frames %q, want %q", i, frames, test.frames)
+		}
+	}
+}
+
+func TestStackSignature(t *testing.T) {
+	const warn = `
+WARNING: CPU: 0 PID: 1 at fs/foo.c:10 foo+0x10/0x20 fs/foo.c:10
+Call Trace:
+ __dump_stack lib/dump_stack.c:16 [inline]
+ dump_stack+0x292/0x395 lib/dump_stack.c:52
+ panic+0x1cb/0x3a9 kernel/panic.c:180
+ __warn+0x1c4/0x1e0 kernel/panic.c:541
+ report_bug+0x211/0x2d0 lib/bug.c:183
+ foo+0x%v/0x20 fs/foo.c:10
+ %v+0x10/0x100 fs/%v.c:20
+ do_syscall_64+0x26c/0x620 arch/x86/entry/common.c:287
+ entry_SYSCALL_64_after_hwframe+0x26/0x9b
+`
+	sig := func(offset, caller string) string {
+		return StackSignature([]byte(fmt.Sprintf(warn, offset, caller, caller)))
+	}
+	if sig("10", "bar") == "" {
+		t.Fatalf("empty signature")
+	}
+	if sig("10", "bar") != sig("18", "bar") {
+		t.Errorf("signatures differ for the same stack with different offsets")
+	}
+	if sig("10", "bar") == sig("10", "baz") {
+		t.Errorf("signatures are the same for different callers")
+	}
+	if StackSignature([]byte("BUG: soft lockup\n")) != "" {
+		t.Errorf("non-empty signature for report without stack")
+	}
+}
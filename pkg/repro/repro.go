@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -45,9 +46,11 @@ type Result struct {
 type context struct {
 	cfg          *mgrconfig.Config
 	crashDesc    string
+	leak         bool // the crash is a memory leak, programs need to be followed by a kmemleak scan
 	instances    chan *instance
 	bootRequests chan int
 	stats        Stats
+	mu           sync.Mutex // protects the fields below, tests may run in parallel
 	desc         string
 	log          []byte
 	report       []byte
@@ -78,13 +81,104 @@ func Run(crashLog []byte, cfg *mgrconfig.Config, vmPool *vm.Pool, vmIndexes []in
 		crashDesc = "hang"
 	}
 
+	ctx := newContext(cfg, crashDesc, vmPool, vmIndexes)
+	ctx.reproLog(0, "%v programs, %v VMs", len(entries), len(vmIndexes))
+	defer ctx.close()
+
+	res, err := ctx.repro(entries, crashStart)
+	if err != nil {
+		return nil, err
+	}
+	if res != nil {
+		ctx.reproLog(3, "repro crashed as:\n%s", string(ctx.report))
+		res.Stats = ctx.stats
+		res.Desc = ctx.desc
+		res.Log = ctx.log
+		res.Report = ctx.report
+	}
+	return res, err
+}
+
+// RetestResult is a result of re-running an existing reproducer.
+type RetestResult struct {
+	Attempts int
+	Crashed  int // number of attempts that crashed with the original title
+	Other    int // number of attempts that crashed with a different title (e.g. lost connection)
+	// Description, log and report of the last crash with the original title (if any),
+	// or of the last crash with a different title.
+	Desc   string
+	Log    []byte
+	Report []byte
+}
+
+// Retest runs reproducer p for crashDesc with opts attempts times in parallel on the given VMs
+// and returns how many attempts crashed with crashDesc (e.g. to check whether the crash is fixed in a new kernel).
+func Retest(p *prog.Prog, opts csource.Options, crashDesc string, duration time.Duration, attempts int,
+	cfg *mgrconfig.Config, vmPool *vm.Pool, vmIndexes []int) (*RetestResult, error) {
+	if len(vmIndexes) == 0 {
+		return nil, fmt.Errorf("no VMs provided")
+	}
+	ctx := newContext(cfg, crashDesc, vmPool, vmIndexes)
+	ctx.reproLog(0, "retesting reproducer %v times, %v VMs", attempts, len(vmIndexes))
+	defer ctx.close()
+
+	res := &RetestResult{Attempts: attempts}
+	var mu sync.Mutex
+	var firstErr error
+	var wg sync.WaitGroup
+	wg.Add(attempts)
+	for i := 0; i < attempts; i++ {
+		go func() {
+			defer wg.Done()
+			crash, err := ctx.runProg(p, duration, opts)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			res.addCrash(crashDesc, crash)
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	ctx.reproLog(0, "reproducer crashed %v out of %v times (%v times with a different title)",
+		res.Crashed, attempts, res.Other)
+	return res, nil
+}
+
+// addCrash accounts crash of a retest attempt (nil if the attempt did not crash).
+// Only crashes with the original title crashDesc count as reproduced.
+func (res *RetestResult) addCrash(crashDesc string, crash *crashInfo) {
+	if crash == nil {
+		return
+	}
+	if crash.desc != crashDesc {
+		res.Other++
+		if res.Crashed != 0 {
+			return
+		}
+	} else {
+		res.Crashed++
+	}
+	res.Desc = crash.desc
+	res.Log = crash.log
+	res.Report = crash.report
+}
+
+// newContext starts booting VMs vmIndexes, booted instances are sent to ctx.instances.
+func newContext(cfg *mgrconfig.Config, crashDesc string, vmPool *vm.Pool, vmIndexes []int) *context {
 	ctx := &context{
 		cfg:          cfg,
 		crashDesc:    crashDesc,
+		leak:         strings.Contains(crashDesc, "memory leak"),
 		instances:    make(chan *instance, len(vmIndexes)),
 		bootRequests: make(chan int, len(vmIndexes)),
 	}
-	ctx.reproLog(0, "%v programs, %v VMs", len(entries), len(vmIndexes))
 	var wg sync.WaitGroup
 	wg.Add(len(vmIndexes))
 	for _, vmIndex := range vmIndexes {
@@ -141,24 +235,14 @@ func Run(crashLog []byte, cfg *mgrconfig.Config, vmPool *vm.Pool, vmIndexes []in
 		wg.Wait()
 		close(ctx.instances)
 	}()
+	return ctx
+}
 
-	res, err := ctx.repro(entries, crashStart)
-	if err != nil {
-		return nil, err
-	}
-	if res != nil {
-		ctx.reproLog(3, "repro crashed as:\n%s", string(ctx.report))
-		res.Stats = ctx.stats
-		res.Desc = ctx.desc
-		res.Log = ctx.log
-		res.Report = ctx.report
-	}
-
+func (ctx *context) close() {
 	close(ctx.bootRequests)
 	for inst := range ctx.instances {
 		inst.Close()
 	}
-	return res, err
 }
 
 func (ctx *context) repro(entries []*prog.LogEntry, crashStart int) (*Result, error) {
@@ -283,6 +367,10 @@ func (ctx *context) createDefaultOps() csource.Options {
 		HandleSegv: true,
 		WaitRepeat: true,
 		Repro:      true,
+		Leak:       ctx.leak,
+	}
+	if opts.Leak && opts.Sandbox == "" {
+		opts.Sandbox = "none"
 	}
 	return opts
 }
@@ -290,15 +378,15 @@ func (ctx *context) createDefaultOps() csource.Options {
 func (ctx *context) extractProgSingle(entries []*prog.LogEntry, duration time.Duration) (*Result, error) {
 	ctx.reproLog(3, "single: executing %d programs separately with timeout %s", len(entries), duration)
 
-	opts := ctx.createDefaultOps()
-
 	for _, ent := range entries {
+		opts := ctx.createDefaultOps()
 		opts.Fault = ent.Fault
 		opts.FaultCall = ent.FaultCall
 		opts.FaultNth = ent.FaultNth
 		if opts.FaultCall < 0 || opts.FaultCall >= len(ent.P.Calls) {
 			opts.FaultCall = len(ent.P.Calls) - 1
 		}
+		setRaceOpts(&opts, ent, 0)
 		crashed, err := ctx.testProg(ent.P, duration, opts)
 		if err != nil {
 			return nil, err
@@ -394,6 +482,29 @@ func (ctx *context) extractProgBisect(entries []*prog.LogEntry, baseDuration tim
 		calls += len(entry.P.Calls)
 	}
 
+	// Try racing calls that were racing in the log.
+	calls = 0
+	for _, entry := range entries {
+		if entry.Race {
+			raceOpts := opts
+			setRaceOpts(&raceOpts, entry, calls)
+			crashed, err := ctx.testProg(prog, dur, raceOpts)
+			if err != nil {
+				return nil, err
+			}
+			if crashed {
+				res := &Result{
+					Prog:     prog,
+					Duration: dur,
+					Opts:     raceOpts,
+				}
+				ctx.reproLog(3, "bisect: concatenation succeeded with racing calls")
+				return res, nil
+			}
+		}
+		calls += len(entry.P.Calls)
+	}
+
 	ctx.reproLog(3, "bisect: concatenation failed")
 	return nil, nil
 }
@@ -410,14 +521,34 @@ func (ctx *context) minimizeProg(res *Result) (*Result, error) {
 	if res.Opts.Fault {
 		call = res.Opts.FaultCall
 	}
-	res.Prog, res.Opts.FaultCall = prog.Minimize(res.Prog, call, func(p1 *prog.Prog, callIndex int) bool {
-		crashed, err := ctx.testProg(p1, res.Duration, res.Opts)
+	if res.Opts.Race {
+		call = res.Opts.RaceCall2
+	}
+	raceDist := res.Opts.RaceCall2 - res.Opts.RaceCall1
+	var callIndex0 int
+	res.Prog, callIndex0 = prog.Minimize(res.Prog, call, func(p1 *prog.Prog, callIndex int) bool {
+		opts := res.Opts
+		if opts.Race {
+			// Minimize tracks only one call, the first racing call is kept at the same distance.
+			opts.RaceCall2 = callIndex
+			opts.RaceCall1 = callIndex - raceDist
+			if opts.RaceCall1 < 0 {
+				return false
+			}
+		}
+		crashed, err := ctx.testProg(p1, res.Duration, opts)
 		if err != nil {
 			ctx.reproLog(0, "minimization failed with %v", err)
 			return false
 		}
 		return crashed
 	}, true)
+	if res.Opts.Race {
+		res.Opts.RaceCall2 = callIndex0
+		res.Opts.RaceCall1 = callIndex0 - raceDist
+	} else {
+		res.Opts.FaultCall = callIndex0
+	}
 
 	return res, nil
 }
@@ -493,35 +624,60 @@ func (ctx *context) simplifyC(res *Result) (*Result, error) {
 	return res, nil
 }
 
+// crashInfo describes a crash that happened while testing a program.
+type crashInfo struct {
+	desc   string
+	log    []byte
+	report []byte
+}
+
 func (ctx *context) testProg(p *prog.Prog, duration time.Duration, opts csource.Options) (crashed bool, err error) {
+	crash, err := ctx.runProg(p, duration, opts)
+	return crash != nil, err
+}
+
+// runProg executes p and returns the crash it has caused (nil if p did not crash the kernel).
+func (ctx *context) runProg(p *prog.Prog, duration time.Duration, opts csource.Options) (*crashInfo, error) {
 	entry := prog.LogEntry{P: p}
 	if opts.Fault {
 		entry.Fault = true
 		entry.FaultCall = opts.FaultCall
 		entry.FaultNth = opts.FaultNth
 	}
-	return ctx.testProgs([]*prog.LogEntry{&entry}, duration, opts)
+	if opts.Race {
+		entry.Race = true
+		entry.RaceCall1 = opts.RaceCall1
+		entry.RaceCall2 = opts.RaceCall2
+		entry.RaceDelay = opts.RaceDelay
+		entry.RaceAffinity = opts.RaceAffinity
+	}
+	return ctx.runProgs([]*prog.LogEntry{&entry}, duration, opts)
 }
 
 func (ctx *context) testProgs(entries []*prog.LogEntry, duration time.Duration, opts csource.Options) (crashed bool, err error) {
+	crash, err := ctx.runProgs(entries, duration, opts)
+	return crash != nil, err
+}
+
+func (ctx *context) runProgs(entries []*prog.LogEntry, duration time.Duration, opts csource.Options) (*crashInfo, error) {
 	inst := <-ctx.instances
 	if inst == nil {
-		return false, fmt.Errorf("all VMs failed to boot")
+		return nil, fmt.Errorf("all VMs failed to boot")
 	}
 	defer ctx.returnInstance(inst)
 	if len(entries) == 0 {
-		return false, fmt.Errorf("no programs to execute")
+		return nil, fmt.Errorf("no programs to execute")
 	}
 
 	pstr := encodeEntries(entries)
 	progFile, err := osutil.WriteTempFile(pstr)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 	defer os.Remove(progFile)
 	vmProgFile, err := inst.Copy(progFile)
 	if err != nil {
-		return false, fmt.Errorf("failed to copy to VM: %v", err)
+		return nil, fmt.Errorf("failed to copy to VM: %v", err)
 	}
 
 	repeat := 1
@@ -542,12 +698,17 @@ func (ctx *context) testProgs(entries []*prog.LogEntry, duration time.Duration,
 		}
 		program += "]"
 	}
+	raceOpts := ""
+	if opts.Race {
+		raceOpts = fmt.Sprintf(" -race_call1=%v -race_call2=%v -race_delay=%v -race_affinity=%v",
+			opts.RaceCall1, opts.RaceCall2, opts.RaceDelay, opts.RaceAffinity)
+	}
 	command := fmt.Sprintf("%v -executor %v -arch=%v -cover=0 -procs=%v -repeat=%v"+
-		" -sandbox %v -threaded=%v -collide=%v %v",
+		" -sandbox %v -threaded=%v -collide=%v -leak=%v%v %v",
 		inst.execprogBin, inst.executorBin, ctx.cfg.TargetArch, opts.Procs, repeat,
-		opts.Sandbox, opts.Threaded, opts.Collide, vmProgFile)
+		opts.Sandbox, opts.Threaded, opts.Collide, opts.Leak, raceOpts, vmProgFile)
 	ctx.reproLog(2, "testing program (duration=%v, %+v): %s", duration, opts, program)
-	return ctx.testImpl(inst.Instance, command, duration)
+	return ctx.runImpl(inst.Instance, command, duration)
 }
 
 func (ctx *context) testCProg(p *prog.Prog, duration time.Duration, opts csource.Options) (crashed bool, err error) {
@@ -587,20 +748,27 @@ func (ctx *context) testBin(bin string, duration time.Duration) (crashed bool, e
 }
 
 func (ctx *context) testImpl(inst *vm.Instance, command string, duration time.Duration) (crashed bool, err error) {
+	crash, err := ctx.runImpl(inst, command, duration)
+	return crash != nil, err
+}
+
+func (ctx *context) runImpl(inst *vm.Instance, command string, duration time.Duration) (*crashInfo, error) {
 	outc, errc, err := inst.Run(duration, nil, command)
 	if err != nil {
-		return false, fmt.Errorf("failed to run command in VM: %v", err)
+		return nil, fmt.Errorf("failed to run command in VM: %v", err)
 	}
 	desc, report, output, crashed, _ := vm.MonitorExecution(outc, errc, false, ctx.cfg.ParsedIgnores)
 	if !crashed {
 		ctx.reproLog(2, "program did not crash")
-		return false, nil
+		return nil, nil
 	}
+	ctx.mu.Lock()
 	ctx.desc = desc
 	ctx.log = output
 	ctx.report = report
+	ctx.mu.Unlock()
 	ctx.reproLog(2, "program crashed: %v", desc)
-	return true, nil
+	return &crashInfo{desc, output, report}, nil
 }
 
 func (ctx *context) returnInstance(inst *instance) {
@@ -611,6 +779,8 @@ func (ctx *context) returnInstance(inst *instance) {
 func (ctx *context) reproLog(level int, format string, args ...interface{}) {
 	prefix := fmt.Sprintf("reproducing crash '%v': ", ctx.crashDesc)
 	Logf(level, prefix+format, args...)
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
 	ctx.stats.Log = append(ctx.stats.Log, []byte(fmt.Sprintf(format, args...)+"\n")...)
 }
 
@@ -737,14 +907,49 @@ func encodeEntries(entries []*prog.LogEntry) []byte {
 		if ent.Fault {
 			opts = fmt.Sprintf(" (fault-call:%v fault-nth:%v)", ent.FaultCall, ent.FaultNth)
 		}
+		if ent.Race {
+			affinity := 0
+			if ent.RaceAffinity {
+				affinity = 1
+			}
+			opts = fmt.Sprintf(" (race-call1:%v race-call2:%v race-delay:%v race-affinity:%v)",
+				ent.RaceCall1, ent.RaceCall2, ent.RaceDelay, affinity)
+		}
 		fmt.Fprintf(buf, "executing program %v%v:\n%v", ent.Proc, opts, string(ent.P.Serialize()))
 	}
 	return buf.Bytes()
 }
 
+// setRaceOpts sets racing calls from the log entry, calls is the index of the entry's first call in the program.
+// Racing requires threaded mode without collision.
+func setRaceOpts(opts *csource.Options, ent *prog.LogEntry, calls int) {
+	opts.Race = ent.Race && ent.RaceCall1 >= 0 && ent.RaceCall1 < ent.RaceCall2 && ent.RaceCall2 < len(ent.P.Calls)
+	opts.RaceCall1, opts.RaceCall2, opts.RaceDelay, opts.RaceAffinity = 0, 0, 0, false
+	if !opts.Race {
+		return
+	}
+	opts.Threaded = true
+	opts.Collide = false
+	opts.RaceCall1 = calls + ent.RaceCall1
+	opts.RaceCall2 = calls + ent.RaceCall2
+	opts.RaceDelay = ent.RaceDelay
+	opts.RaceAffinity = ent.RaceAffinity
+}
+
 type Simplify func(opts *csource.Options) bool
 
 var progSimplifies = []Simplify{
+	func(opts *csource.Options) bool {
+		if !opts.Race {
+			return false
+		}
+		opts.Race = false
+		opts.RaceCall1 = 0
+		opts.RaceCall2 = 0
+		opts.RaceDelay = 0
+		opts.RaceAffinity = false
+		return true
+	},
 	func(opts *csource.Options) bool {
 		if !opts.Fault {
 			return false
@@ -762,7 +967,7 @@ var progSimplifies = []Simplify{
 		return true
 	},
 	func(opts *csource.Options) bool {
-		if opts.Collide || !opts.Threaded {
+		if opts.Collide || opts.Race || !opts.Threaded {
 			return false
 		}
 		opts.Threaded = false
@@ -794,7 +999,7 @@ var progSimplifies = []Simplify{
 
 var cSimplifies = append(progSimplifies, []Simplify{
 	func(opts *csource.Options) bool {
-		if opts.Sandbox == "" {
+		if opts.Sandbox == "" || opts.Leak {
 			return false
 		}
 		opts.Sandbox = ""
@@ -93,3 +93,18 @@ func TestSimplifies(t *testing.T) {
 	}
 	check(opts, 0)
 }
+
+func TestRetestResult(t *testing.T) {
+	const desc = "KASAN: use-after-free Read in foo"
+	res := &RetestResult{Attempts: 4}
+	res.addCrash(desc, nil)
+	res.addCrash(desc, &crashInfo{desc: "lost connection to test machine", report: []byte("lost")})
+	if res.Crashed != 0 || res.Other != 1 || res.Desc != "lost connection to test machine" {
+		t.Fatalf("bad result: %+v", res)
+	}
+	res.addCrash(desc, &crashInfo{desc: desc, report: []byte("uaf")})
+	res.addCrash(desc, &crashInfo{desc: "WARNING in bar", report: []byte("warning")})
+	if res.Crashed != 1 || res.Other != 2 || res.Desc != desc || string(res.Report) != "uaf" {
+		t.Fatalf("bad result: %+v", res)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package rpctype
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+)
+
+func TestFlateConn(t *testing.T) {
+	c0, c1 := net.Pipe()
+	fc0, fc1 := newFlateConn(c0), newFlateConn(c1)
+	msgs := [][]byte{
+		[]byte("hello"),
+		bytes.Repeat([]byte("a"), 100<<10),
+		[]byte("bye"),
+	}
+	done := make(chan error)
+	go func() {
+		for _, msg := range msgs {
+			if _, err := fc0.Write(msg); err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	}()
+	// Every message must be readable without any further writes from the peer.
+	for i, msg := range msgs {
+		buf := make([]byte, len(msg))
+		if _, err := io.ReadFull(fc1, buf); err != nil {
+			t.Fatalf("msg #%v: %v", i, err)
+		}
+		if !bytes.Equal(buf, msg) {
+			t.Fatalf("msg #%v: got %q, want %q", i, buf[:10], msg[:10])
+		}
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	c0.Close()
+	c1.Close()
+}
+
+type TestReceiver struct{}
+
+func (*TestReceiver) Echo(a string, r *string) error {
+	*r = a
+	return nil
+}
+
+func TestRpcCompression(t *testing.T) {
+	for _, compression := range []bool{false, true} {
+		serv, err := NewRpcServer("localhost:0", new(TestReceiver), compression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		go serv.Serve()
+		cli, err := NewRpcClient(serv.Addr().String(), compression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, msg := range []string{"foo", string(bytes.Repeat([]byte("bar"), 1<<20))} {
+			var res string
+			if err := cli.Call("TestReceiver.Echo", msg, &res); err != nil {
+				t.Fatalf("compression=%v: %v", compression, err)
+			}
+			if res != msg {
+				t.Fatalf("compression=%v: got %v bytes, want %v", compression, len(res), len(msg))
+			}
+		}
+		cli.Close()
+	}
+}
@@ -10,6 +10,53 @@ type RpcInput struct {
 	Prog   []byte
 	Signal []uint32
 	Cover  []uint32
+	Chosen uint64 // number of times the input was chosen for mutation
+	Found  uint64 // amount of new signal found by mutants of the input
+}
+
+// RpcInputStats is a delta of power schedule counters of a corpus input.
+type RpcInputStats struct {
+	Sig    string
+	Chosen uint64
+	Found  uint64
+}
+
+// RpcFaultSite is a kernel fault injection site hit by a fuzzer.
+type RpcFaultSite struct {
+	PC   uint32
+	Call string
+	Nth  int // fault point of Call that hit the site
+	Hits uint64
+}
+
+// RpcCallStats are execution counters of a syscall.
+type RpcCallStats struct {
+	Execs     uint64
+	Errnos    map[int]uint64 // errno histogram, 0 means success
+	NewSignal uint64         // number of executions that gave new signal
+}
+
+// RpcCallUsage is peak resource usage of programs that contain a syscall.
+type RpcCallUsage struct {
+	MaxMemory uint64            // in bytes
+	MaxFds    int               // open fds at the end of the program
+	MaxPids   int               // tasks, only with cgroups
+	Limits    map[string]uint64 // number of programs that hit the limit, by ipc.LimitName
+}
+
+// RpcRacePair are counters of racing executions of a pair of syscalls.
+type RpcRacePair struct {
+	Call1     string
+	Call2     string
+	Execs     uint64
+	NewSignal uint64
+}
+
+// RpcLeak is a memory leak in an allocation site not known to manager yet.
+type RpcLeak struct {
+	Site   string
+	Report []byte // kmemleak report
+	Log    []byte // programs executed before the leak was detected in execution log format
 }
 
 type RpcCandidate struct {
@@ -19,6 +66,11 @@ type RpcCandidate struct {
 
 type ConnectArgs struct {
 	Name string
+	// Contents of the fuzzer-local corpus cache: hashes of inputs, manager run the cache
+	// was received from and length of the manager max signal log it contains.
+	CachedInputs    []string
+	CachedRun       int64
+	CachedMaxSignal int
 }
 
 type ConnectRes struct {
@@ -28,6 +80,17 @@ type ConnectRes struct {
 	Candidates   []RpcCandidate
 	EnabledCalls string
 	NeedCheck    bool
+	// Directed fuzzing weights of coverage PCs, nil if focus is not configured or not yet computed.
+	FocusWeights map[uint32]float32
+	FaultSites   []RpcFaultSite // already exercised fault sites
+	NoisySignal  []uint32       // signal excluded from novelty checks
+	// Cached inputs that are no longer in corpus, Inputs contains only inputs that are not cached.
+	DroppedInputs []string
+	// If Run is equal to CachedRun, MaxSignal contains only signal that is not cached.
+	Run          int64
+	MaxSignalLog int      // length of the manager max signal log after the reply
+	LeakSites    []string // allocation sites of already reported memory leaks
+	Paused       bool     // fuzzing is paused, see PollRes
 }
 
 type CheckArgs struct {
@@ -51,15 +114,31 @@ type NewInputArgs struct {
 }
 
 type PollArgs struct {
-	Name      string
-	MaxSignal []uint32
-	Stats     map[string]uint64
+	Name        string
+	MaxSignal   []uint32
+	Stats       map[string]uint64
+	InputStats  []RpcInputStats
+	FaultSites  []RpcFaultSite
+	FlakySignal map[uint32]uint64 // number of times signal was flaky in triage
+	CallStats   map[string]RpcCallStats
+	CallUsage   map[string]RpcCallUsage
+	RacePairs   []RpcRacePair
+	Leaks       []RpcLeak
 }
 
 type PollRes struct {
-	Candidates []RpcCandidate
-	NewInputs  []RpcInput
-	MaxSignal  []uint32
+	Candidates   []RpcCandidate
+	NewInputs    []RpcInput
+	MaxSignal    []uint32
+	FocusWeights map[uint32]float32 // sent once when computed after the fuzzer has connected
+	FaultSites   []RpcFaultSite     // fault sites exercised by other fuzzers
+	NoisySignal  []uint32           // new noisy signal
+	MaxSignalLog int                // see ConnectRes
+	LeakSites    []string           // new memory leak sites reported by other fuzzers
+	Paused       bool               // don't execute programs until resumed
+	// Set only when enabled syscalls have changed, Prios are sent along to rebuild the choice table.
+	EnabledCalls string
+	Prios        [][]float32
 }
 
 type HubConnectArgs struct {
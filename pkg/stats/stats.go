@@ -0,0 +1,164 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// Package stats exports metrics in Prometheus text exposition format.
+// All metrics are declared in this package so that names are consistent across binaries.
+// Binaries register collectors that fill in current metric values on every scrape.
+package stats
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type Type string
+
+const (
+	Counter Type = "counter"
+	Gauge   Type = "gauge"
+)
+
+type Metric struct {
+	Name   string
+	Help   string
+	Type   Type
+	Labels []string
+}
+
+// syz-manager metrics.
+var (
+	ManagerCounter = &Metric{"syz_manager_counter_total", "Manager counters by name.", Counter, []string{"name"}}
+	ManagerUptime  = &Metric{"syz_manager_uptime_seconds", "Time since manager start.", Gauge, nil}
+	FuzzingTime    = &Metric{"syz_manager_fuzzing_seconds_total", "Total fuzzing time across all VMs.", Counter, nil}
+	CorpusSize     = &Metric{"syz_manager_corpus_size", "Number of inputs in corpus.", Gauge, nil}
+	SignalSize     = &Metric{"syz_manager_signal_size", "Amount of corpus signal.", Gauge, nil}
+	CoverageSize   = &Metric{"syz_manager_coverage_size", "Number of covered PCs.", Gauge, nil}
+	FuzzerExecs    = &Metric{"syz_manager_fuzzer_execs_total", "Number of executed programs per fuzzer.", Counter, []string{"fuzzer"}}
+	Crashes        = &Metric{"syz_manager_crashes_total", "Number of crashes per title class.", Counter, []string{"class"}}
+	VMRestarts     = &Metric{"syz_manager_vm_restarts_total", "Number of VM restarts.", Counter, nil}
+)
+
+// syz-hub metrics.
+var (
+	HubCorpusSize    = &Metric{"syz_hub_corpus_size", "Number of inputs in hub corpus.", Gauge, nil}
+	HubRepros        = &Metric{"syz_hub_repros", "Number of repros in hub.", Gauge, nil}
+	HubManagerCorpus = &Metric{"syz_hub_manager_corpus_size", "Number of inputs in manager corpus.", Gauge, []string{"manager"}}
+	HubAdded         = &Metric{"syz_hub_added_total", "Number of inputs added by manager.", Counter, []string{"manager"}}
+	HubDeleted       = &Metric{"syz_hub_deleted_total", "Number of inputs deleted by manager.", Counter, []string{"manager"}}
+	HubNew           = &Metric{"syz_hub_new_total", "Number of new inputs sent to manager.", Counter, []string{"manager"}}
+	HubSentRepros    = &Metric{"syz_hub_sent_repros_total", "Number of repros sent to manager.", Counter, []string{"manager"}}
+	HubRecvRepros    = &Metric{"syz_hub_recv_repros_total", "Number of repros received from manager.", Counter, []string{"manager"}}
+)
+
+// syz-ci metrics.
+var (
+	BuildDuration = &Metric{"syz_ci_build_duration_seconds", "Duration of the last build.", Gauge, []string{"build"}}
+	Builds        = &Metric{"syz_ci_builds_total", "Number of builds by result (success/failure).", Counter, []string{"build", "result"}}
+)
+
+// Registry holds collectors of metrics for a binary.
+type Registry struct {
+	mu         sync.Mutex
+	collectors []func(*Set)
+}
+
+// Register adds a collector that is called on every scrape to fill in current metric values.
+func (r *Registry) Register(collect func(*Set)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, collect)
+}
+
+// Collect calls all collectors and returns the collected values.
+func (r *Registry) Collect() *Set {
+	r.mu.Lock()
+	collectors := r.collectors
+	r.mu.Unlock()
+	set := &Set{samples: make(map[*Metric][]sample)}
+	for _, collect := range collectors {
+		collect(set)
+	}
+	return set
+}
+
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	if err := r.Collect().Write(w); err != nil {
+		http.Error(w, fmt.Sprintf("failed to write metrics: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// Set is a set of metric values collected during a single scrape.
+type Set struct {
+	samples map[*Metric][]sample
+}
+
+type sample struct {
+	labels []string
+	value  float64
+}
+
+// Add adds value to the metric with the given label values.
+// Label values must be in the same order as Metric.Labels.
+func (s *Set) Add(m *Metric, value float64, labels ...string) {
+	if len(labels) != len(m.Labels) {
+		panic(fmt.Sprintf("metric %v: got %v labels, want %v", m.Name, len(labels), len(m.Labels)))
+	}
+	samples := s.samples[m]
+	for i := range samples {
+		if equalLabels(samples[i].labels, labels) {
+			samples[i].value += value
+			return
+		}
+	}
+	s.samples[m] = append(samples, sample{labels, value})
+}
+
+// Write writes the set in Prometheus text exposition format.
+func (s *Set) Write(w io.Writer) error {
+	var metrics []*Metric
+	for m := range s.samples {
+		metrics = append(metrics, m)
+	}
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].Name < metrics[j].Name })
+	buf := bufio.NewWriter(w)
+	for _, m := range metrics {
+		samples := s.samples[m]
+		sort.Slice(samples, func(i, j int) bool {
+			return strings.Join(samples[i].labels, "\x00") < strings.Join(samples[j].labels, "\x00")
+		})
+		fmt.Fprintf(buf, "# HELP %v %v\n", m.Name, m.Help)
+		fmt.Fprintf(buf, "# TYPE %v %v\n", m.Name, m.Type)
+		for _, smp := range samples {
+			buf.WriteString(m.Name)
+			if len(smp.labels) != 0 {
+				buf.WriteByte('{')
+				for i, val := range smp.labels {
+					if i != 0 {
+						buf.WriteByte(',')
+					}
+					fmt.Fprintf(buf, "%v=\"%v\"", m.Labels[i], labelEscaper.Replace(val))
+				}
+				buf.WriteByte('}')
+			}
+			fmt.Fprintf(buf, " %v\n", smp.value)
+		}
+	}
+	return buf.Flush()
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func equalLabels(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
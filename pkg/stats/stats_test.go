@@ -0,0 +1,50 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package stats
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	reg := new(Registry)
+	reg.Register(func(set *Set) {
+		set.Add(CorpusSize, 42)
+		set.Add(ManagerCounter, 10, "exec total")
+		set.Add(ManagerCounter, 1, `a "quoted"\name`)
+	})
+	reg.Register(func(set *Set) {
+		set.Add(ManagerCounter, 5, "exec total")
+		set.Add(Builds, 1, "syzkaller", "success")
+	})
+	buf := new(bytes.Buffer)
+	if err := reg.Collect().Write(buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP syz_ci_builds_total Number of builds by result (success/failure).
+# TYPE syz_ci_builds_total counter
+syz_ci_builds_total{build="syzkaller",result="success"} 1
+# HELP syz_manager_corpus_size Number of inputs in corpus.
+# TYPE syz_manager_corpus_size gauge
+syz_manager_corpus_size 42
+# HELP syz_manager_counter_total Manager counters by name.
+# TYPE syz_manager_counter_total counter
+syz_manager_counter_total{name="a \"quoted\"\\name"} 1
+syz_manager_counter_total{name="exec total"} 15
+`
+	if got := buf.String(); got != want {
+		t.Fatalf("got:\n%v\nwant:\n%v", got, want)
+	}
+}
+
+func TestBadLabels(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("no panic on wrong number of labels")
+		}
+	}()
+	set := new(Registry).Collect()
+	set.Add(FuzzerExecs, 1)
+}
@@ -153,6 +153,36 @@ func foreachSubargOffset(arg Arg, f func(arg Arg, offset uint64)) {
 	rec(arg, 0)
 }
 
+// SharedResources returns true if calls i and j of p produce or use the same resource
+// (e.g. both use an fd returned by an earlier call, or one uses a resource produced by the other).
+func SharedResources(p *Prog, i, j int) bool {
+	res := callResources(p.Calls[i])
+	for r := range callResources(p.Calls[j]) {
+		if res[r] {
+			return true
+		}
+	}
+	return false
+}
+
+func callResources(c *Call) map[Arg]bool {
+	res := make(map[Arg]bool)
+	if ret, ok := c.Ret.(*ReturnArg); ok && len(ret.uses) != 0 {
+		res[ret] = true
+	}
+	foreachArg(c, func(arg, _ Arg, _ *[]Arg) {
+		if a, ok := arg.(*ResultArg); ok {
+			if a.Res != nil {
+				res[a.Res] = true
+			}
+			if len(a.uses) != 0 {
+				res[a] = true
+			}
+		}
+	})
+	return res
+}
+
 func RequiresBitmasks(p *Prog) bool {
 	result := false
 	for _, c := range p.Calls {
@@ -16,9 +16,13 @@ package prog
 // replacing the pointed argument with the saved value.
 //		4. If a valid program is obtained, then fuzzer launches it and
 // checks if new coverage is obtained.
+// Besides integer comparisons, hints can use byte-string comparison operands
+// (e.g. arguments of strcmp/memcmp in kernel, see StringCompMap).
+// Matched byte strings are substituted in data arguments.
 // For more insights on particular mutations please see prog/hints_test.go.
 
 import (
+	"bytes"
 	"encoding/binary"
 )
 
@@ -32,6 +36,11 @@ type uint64Set map[uint64]bool
 // }.
 type CompMap map[uint64]uint64Set
 
+// StringCompMap is the same as CompMap, but for byte-string comparison operands.
+type StringCompMap map[string]stringSet
+
+type stringSet map[string]bool
+
 const (
 	maxDataLength = 100
 )
@@ -45,20 +54,33 @@ func (m CompMap) AddComp(arg1, arg2 uint64) {
 	m[arg1][arg2] = true
 }
 
-// Mutates the program using the comparison operands stored in compMaps.
+func (m StringCompMap) AddComp(arg1, arg2 []byte) {
+	if _, ok := m[string(arg1)]; !ok {
+		m[string(arg1)] = make(stringSet)
+	}
+	m[string(arg1)][string(arg2)] = true
+}
+
+// Mutates the program using the comparison operands stored in compMaps
+// and the byte-string comparison operands stored in stringCompMaps (can be nil).
 // For each of the mutants executes the exec callback.
-func (p *Prog) MutateWithHints(compMaps []CompMap, exec func(newP *Prog)) {
+func (p *Prog) MutateWithHints(compMaps []CompMap, stringCompMaps []StringCompMap, exec func(newP *Prog)) {
 	for i, c := range p.Calls {
 		if c.Meta == p.Target.MmapSyscall {
 			continue
 		}
+		var stringCompMap StringCompMap
+		if stringCompMaps != nil {
+			stringCompMap = stringCompMaps[i]
+		}
 		foreachArg(c, func(arg, _ Arg, _ *[]Arg) {
-			generateHints(p, compMaps[i], c, arg, exec)
+			generateHints(p, compMaps[i], stringCompMap, i, arg, exec)
 		})
 	}
 }
 
-func generateHints(p *Prog, compMap CompMap, c *Call, arg Arg, exec func(p *Prog)) {
+func generateHints(p *Prog, compMap CompMap, stringCompMap StringCompMap, callIndex int, arg Arg, exec func(p *Prog)) {
+	c := p.Calls[callIndex]
 	newP, argMap := p.cloneImpl(true)
 	var originalArg Arg
 	validateExec := func() {
@@ -82,6 +104,19 @@ func generateHints(p *Prog, compMap CompMap, c *Call, arg Arg, exec func(p *Prog
 		validateExec()
 	}
 
+	stringArgCandidate := func(newData []byte) {
+		// String replacements can change data length,
+		// so we replace data in the cloned program and update len fields.
+		newArg := argMap[arg].(*DataArg)
+		newCall := newP.Calls[callIndex]
+		oldData := newArg.Data
+		newArg.Data = newData
+		newP.Target.assignSizesCall(newCall)
+		validateExec()
+		newArg.Data = oldData
+		newP.Target.assignSizesCall(newCall)
+	}
+
 	switch a := arg.(type) {
 	case *ConstArg:
 		originalArg = MakeConstArg(a.Type(), a.Val)
@@ -89,6 +124,7 @@ func generateHints(p *Prog, compMap CompMap, c *Call, arg Arg, exec func(p *Prog
 	case *DataArg:
 		originalArg = dataArg(a.Type(), a.Data)
 		checkDataArg(a, compMap, dataArgCandidate)
+		checkDataArgStrings(a, stringCompMap, stringArgCandidate)
 	}
 }
 
@@ -117,6 +153,45 @@ func checkDataArg(arg *DataArg, compMap CompMap, cb func(newArg Arg)) {
 	}
 }
 
+// checkDataArgStrings substitutes byte strings in arg data that are equal
+// to one of the byte-string comparison operands with the other operand.
+// Varlen data (e.g. strings) can change length as the result,
+// for fixed-size data only replacements of the same length are done.
+func checkDataArgStrings(arg *DataArg, compMap StringCompMap, cb func(newData []byte)) {
+	if arg.Type().Dir() != DirIn && arg.Type().Dir() != DirInOut {
+		// We only want to scan userspace->kernel data.
+		return
+	}
+	data := arg.Data
+	varlen := arg.Type().Varlen()
+	for op, replacers := range compMap {
+		if len(op) == 0 {
+			continue
+		}
+		for start := 0; start < min(len(data), maxDataLength); {
+			i := bytes.Index(data[start:], []byte(op))
+			if i == -1 {
+				break
+			}
+			pos := start + i
+			if pos >= maxDataLength {
+				break
+			}
+			for replacer := range replacers {
+				if !varlen && len(replacer) != len(op) {
+					continue
+				}
+				newData := make([]byte, 0, len(data)-len(op)+len(replacer))
+				newData = append(newData, data[:pos]...)
+				newData = append(newData, replacer...)
+				newData = append(newData, data[pos+len(op):]...)
+				cb(newData)
+			}
+			start = pos + 1
+		}
+	}
+}
+
 // Shrink and expand mutations model the cases when the syscall arguments
 // are casted to narrower (and wider) integer types.
 // ======================================================================
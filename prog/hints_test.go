@@ -336,3 +336,109 @@ func TestHintsShrinkExpand(t *testing.T) {
 		})
 	}
 }
+
+type StringDataArgTest struct {
+	name   string
+	in     string
+	varlen bool
+	comps  StringCompMap
+	res    map[string]bool
+}
+
+// Tests checkDataArgStrings(). Is not intended to check correctness of any mutations.
+// Mutation are checked in their own tests.
+func TestHintsCheckDataArgStrings(t *testing.T) {
+	var tests = []StringDataArgTest{
+		{
+			"One replacer test",
+			"ext4\x00",
+			true,
+			StringCompMap{"ext4": stringSet{"vfat": true}},
+			map[string]bool{
+				"vfat\x00": true,
+			},
+		},
+		// Checks that varlen data can change length.
+		{
+			"Different length test",
+			"./file0\x00",
+			true,
+			StringCompMap{"file0": stringSet{"f": true, "file10": true}},
+			map[string]bool{
+				"./f\x00":      true,
+				"./file10\x00": true,
+			},
+		},
+		// Checks that fixed-size data keeps its length.
+		{
+			"Fixed size test",
+			"aes\x00\x00\x00\x00\x00",
+			false,
+			StringCompMap{"aes": stringSet{"des": true, "sha1": true}},
+			map[string]bool{
+				"des\x00\x00\x00\x00\x00": true,
+			},
+		},
+		// Checks that all occurrences of the operand are replaced (one at a time).
+		{
+			"Multiple occurrences test",
+			"abcab",
+			true,
+			StringCompMap{"ab": stringSet{"xyz": true}},
+			map[string]bool{
+				"xyzcab": true,
+				"abcxyz": true,
+			},
+		},
+		{
+			"No match test",
+			"abcd",
+			true,
+			StringCompMap{"xyz": stringSet{"ab": true}, "": stringSet{"ab": true}},
+			map[string]bool{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%v", test.name), func(t *testing.T) {
+			res := make(map[string]bool)
+			size := uint64(0)
+			if !test.varlen {
+				size = uint64(len(test.in))
+			}
+			typ := BufferType{TypeCommon: TypeCommon{"", "", size, DirIn, false}, Kind: BufferString}
+			dataArg := &DataArg{ArgCommon{&typ}, []byte(test.in)}
+			checkDataArgStrings(dataArg, test.comps, func(newData []byte) {
+				res[string(newData)] = true
+			})
+			if string(dataArg.Data) != test.in {
+				t.Fatalf("original data is changed: %q", dataArg.Data)
+			}
+			if !reflect.DeepEqual(res, test.res) {
+				t.Fatalf("\ngot : %v\nwant: %v", res, test.res)
+			}
+		})
+	}
+}
+
+func TestHintsMutateStrings(t *testing.T) {
+	target, _, _ := initTest(t)
+	p, err := target.Deserialize([]byte(`write(0xffffffffffffffff, &(0x7f0000000000)="0102030405", 0x5)`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	compMaps := []CompMap{make(CompMap)}
+	stringCompMaps := []StringCompMap{make(StringCompMap)}
+	stringCompMaps[0].AddComp([]byte{0x2, 0x3}, []byte{0xaa, 0xbb, 0xcc})
+	var res []string
+	p.MutateWithHints(compMaps, stringCompMaps, func(newP *Prog) {
+		res = append(res, string(newP.Serialize()))
+	})
+	want := []string{"write(0xffffffffffffffff, &(0x7f0000000000)=\"01aabbcc0405\", 0x6)\n"}
+	if !reflect.DeepEqual(res, want) {
+		t.Fatalf("\ngot : %q\nwant: %q", res, want)
+	}
+	// The original program must not be changed.
+	if data := string(p.Serialize()); data != "write(0xffffffffffffffff, &(0x7f0000000000)=\"0102030405\", 0x5)\n" {
+		t.Fatalf("original program is changed: %v", data)
+	}
+}
@@ -17,6 +17,12 @@ type LogEntry struct {
 	Fault     bool // program was executed with fault injection in FaultCall/FaultNth
 	FaultCall int
 	FaultNth  int
+	Race      bool // program was executed with RaceCall1 and RaceCall2 racing
+	RaceCall1 int
+	RaceCall2 int
+	RaceDelay int // in microseconds
+	// Racing calls were pinned to different CPUs.
+	RaceAffinity bool
 }
 
 func (target *Target) ParseLog(data []byte) []*LogEntry {
@@ -48,6 +54,14 @@ func (target *Target) ParseLog(data []byte) []*LogEntry {
 				ent.FaultCall = faultCall
 				ent.FaultNth, _ = extractInt(line, "fault-nth:")
 			}
+			if raceCall1, ok := extractInt(line, "race-call1:"); ok {
+				ent.Race = true
+				ent.RaceCall1 = raceCall1
+				ent.RaceCall2, _ = extractInt(line, "race-call2:")
+				ent.RaceDelay, _ = extractInt(line, "race-delay:")
+				affinity, _ := extractInt(line, "race-affinity:")
+				ent.RaceAffinity = affinity != 0
+			}
 			cur = nil
 			continue
 		}
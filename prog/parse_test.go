@@ -135,3 +135,26 @@ getpid()
 		t.Fatalf("bad program: %s, want %s", got, want)
 	}
 }
+
+func TestParseRace(t *testing.T) {
+	target, err := GetTarget("linux", "amd64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const execLog = `2015/12/21 12:18:05 executing program 1 (race-call1:0 race-call2:2 race-delay:150 race-affinity:1):
+gettid()
+getpid()
+gettid()
+`
+	entries := target.ParseLog([]byte(execLog))
+	if len(entries) != 1 {
+		t.Fatalf("got %v programs, want 1", len(entries))
+	}
+	ent := entries[0]
+	if !ent.Race || ent.Fault {
+		t.Fatalf("bad modes: race=%v fault=%v", ent.Race, ent.Fault)
+	}
+	if ent.RaceCall1 != 0 || ent.RaceCall2 != 2 || ent.RaceDelay != 150 || !ent.RaceAffinity {
+		t.Fatalf("bad race options: %+v", ent)
+	}
+}
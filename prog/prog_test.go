@@ -98,3 +98,34 @@ func TestVmaType(t *testing.T) {
 		check(c.Args[4], c.Args[5], 7, 9)
 	}
 }
+
+func TestSharedResources(t *testing.T) {
+	target, _, _ := initTest(t)
+	p, err := target.Deserialize([]byte(`
+r0 = open(&(0x7f0000000000)="2e2f66696c653000", 0x0, 0x0)
+read(r0, &(0x7f0000001000)="", 0x1)
+r1 = open(&(0x7f0000000000)="2e2f66696c653000", 0x0, 0x0)
+write(r0, &(0x7f0000001000)="00", 0x1)
+close(r1)
+getpid()
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		i, j   int
+		shared bool
+	}{
+		{0, 1, true},
+		{1, 3, true},
+		{0, 2, false},
+		{1, 4, false},
+		{2, 4, true},
+		{3, 5, false},
+	}
+	for _, test := range tests {
+		if shared := SharedResources(p, test.i, test.j); shared != test.shared {
+			t.Errorf("calls %v and %v: shared=%v, want %v", test.i, test.j, shared, test.shared)
+		}
+	}
+}
@@ -136,7 +136,10 @@ loop:
 					select {
 					case kernelBuildSem <- struct{}{}:
 						Logf(0, "%v: building kernel...", mgr.name)
-						if err := mgr.build(); err != nil {
+						buildStart := time.Now()
+						err := mgr.build()
+						builds.record(mgr.mgrcfg.Name, buildStart, err)
+						if err != nil {
 							Logf(0, "%v: %v", mgr.name, err)
 						} else {
 							Logf(0, "%v: build successful, [re]starting manager", mgr.name)
@@ -0,0 +1,65 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	. "github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/stats"
+)
+
+// buildStats tracks syzkaller and kernel builds for /metrics.
+// Builds are identified by "syzkaller" or by manager name.
+type buildStats struct {
+	mu        sync.Mutex
+	durations map[string]time.Duration
+	results   map[[2]string]uint64
+}
+
+var builds = &buildStats{
+	durations: make(map[string]time.Duration),
+	results:   make(map[[2]string]uint64),
+}
+
+func (bs *buildStats) record(build string, start time.Time, err error) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	bs.durations[build] = time.Since(start)
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	bs.results[[2]string{build, result}]++
+}
+
+func (bs *buildStats) collect(set *stats.Set) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	for build, d := range bs.durations {
+		set.Add(stats.BuildDuration, d.Seconds(), build)
+	}
+	for key, n := range bs.results {
+		set.Add(stats.Builds, float64(n), key[0], key[1])
+	}
+}
+
+func serveHttp(addr string) {
+	metrics := new(stats.Registry)
+	metrics.Register(builds.collect)
+	http.Handle("/metrics", metrics)
+
+	ln, err := net.Listen("tcp4", addr)
+	if err != nil {
+		Fatalf("failed to listen on %v: %v", addr, err)
+	}
+	Logf(0, "serving http on http://%v", ln.Addr())
+	go func() {
+		err := http.Serve(ln, nil)
+		Fatalf("failed to serve http: %v", err)
+	}()
+}
@@ -97,6 +97,8 @@ func main() {
 		Fatalf("failed to load config: %v", err)
 	}
 
+	serveHttp(cfg.Http)
+
 	shutdownPending := make(chan struct{})
 	osutil.HandleInterrupts(shutdownPending)
 
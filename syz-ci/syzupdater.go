@@ -197,7 +197,10 @@ func (upd *SyzUpdater) pollAndBuild(lastCommit string) string {
 		if lastCommit != commit {
 			Logf(0, "syzkaller: building ...")
 			lastCommit = commit
-			if err := upd.build(); err != nil {
+			buildStart := time.Now()
+			err := upd.build()
+			builds.record("syzkaller", buildStart, err)
+			if err != nil {
 				Logf(0, "syzkaller: %v", err)
 			}
 		}
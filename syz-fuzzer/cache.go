@@ -0,0 +1,203 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"compress/gzip"
+	"encoding/gob"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/google/syzkaller/pkg/hash"
+	. "github.com/google/syzkaller/pkg/log"
+	. "github.com/google/syzkaller/pkg/rpctype"
+)
+
+// corpusCache is a local copy of corpus inputs and max signal received from (or sent to) manager.
+// It is persisted in a file, so that after VM restart manager needs to send only what is missing.
+type corpusCache struct {
+	file string
+
+	mu           sync.Mutex
+	run          int64 // manager run the cache was received from
+	maxSignalLog int   // length of manager max signal log contained in maxSignal
+	inputs       map[string]RpcInput
+	maxSignal    map[uint32]struct{}
+	dirty        bool
+}
+
+// cacheData is the on-disk format of corpusCache.
+type cacheData struct {
+	Run          int64
+	MaxSignalLog int
+	Inputs       []RpcInput
+	MaxSignal    []uint32
+}
+
+// loadCache loads cache from file, a missing or corrupted file gives an empty cache.
+func loadCache(file string) *corpusCache {
+	cache := &corpusCache{
+		file:      file,
+		inputs:    make(map[string]RpcInput),
+		maxSignal: make(map[uint32]struct{}),
+	}
+	data, err := readCache(file)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			Logf(0, "failed to read corpus cache: %v", err)
+		}
+		return cache
+	}
+	cache.run = data.Run
+	cache.maxSignalLog = data.MaxSignalLog
+	for _, inp := range data.Inputs {
+		cache.inputs[hash.String(inp.Prog)] = inp
+	}
+	for _, s := range data.MaxSignal {
+		cache.maxSignal[s] = struct{}{}
+	}
+	Logf(0, "loaded corpus cache: %v inputs, %v max signal", len(cache.inputs), len(cache.maxSignal))
+	return cache
+}
+
+func readCache(file string) (*cacheData, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	data := new(cacheData)
+	if err := gob.NewDecoder(gz).Decode(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// connectArgs fills in cache contents for Manager.Connect.
+func (cache *corpusCache) connectArgs(a *ConnectArgs) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	for sig := range cache.inputs {
+		a.CachedInputs = append(a.CachedInputs, sig)
+	}
+	a.CachedRun = cache.run
+	a.CachedMaxSignal = cache.maxSignalLog
+}
+
+// connect applies Manager.Connect reply and returns all corpus inputs and max signal.
+func (cache *corpusCache) connect(r *ConnectRes) ([]RpcInput, []uint32) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	for _, sig := range r.DroppedInputs {
+		delete(cache.inputs, sig)
+	}
+	if r.Run != cache.run {
+		// Manager has restarted and its max signal has started from scratch.
+		cache.maxSignal = make(map[uint32]struct{})
+		cache.run = r.Run
+	}
+	cache.addInputsLocked(r.Inputs)
+	cache.addMaxSignalLocked(r.MaxSignal, r.NoisySignal)
+	cache.maxSignalLog = r.MaxSignalLog
+	cache.dirty = true
+	inputs := make([]RpcInput, 0, len(cache.inputs))
+	for _, inp := range cache.inputs {
+		inputs = append(inputs, inp)
+	}
+	maxSignal := make([]uint32, 0, len(cache.maxSignal))
+	for s := range cache.maxSignal {
+		maxSignal = append(maxSignal, s)
+	}
+	return inputs, maxSignal
+}
+
+// poll applies Manager.Poll request and reply.
+func (cache *corpusCache) poll(a *PollArgs, r *PollRes) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.addInputsLocked(r.NewInputs)
+	cache.addMaxSignalLocked(a.MaxSignal, r.MaxSignal, r.NoisySignal)
+	cache.maxSignalLog = r.MaxSignalLog
+}
+
+// addInput adds an input sent to manager.
+func (cache *corpusCache) addInput(inp RpcInput) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.addInputsLocked([]RpcInput{inp})
+}
+
+func (cache *corpusCache) addInputsLocked(inputs []RpcInput) {
+	for _, inp := range inputs {
+		cache.inputs[hash.String(inp.Prog)] = inp
+		cache.dirty = true
+	}
+}
+
+func (cache *corpusCache) addMaxSignalLocked(signals ...[]uint32) {
+	for _, signal := range signals {
+		for _, s := range signal {
+			cache.maxSignal[s] = struct{}{}
+			cache.dirty = true
+		}
+	}
+}
+
+// save writes the cache to the file if it has changed since the last save.
+func (cache *corpusCache) save() error {
+	cache.mu.Lock()
+	if !cache.dirty {
+		cache.mu.Unlock()
+		return nil
+	}
+	data := &cacheData{
+		Run:          cache.run,
+		MaxSignalLog: cache.maxSignalLog,
+		Inputs:       make([]RpcInput, 0, len(cache.inputs)),
+		MaxSignal:    make([]uint32, 0, len(cache.maxSignal)),
+	}
+	for _, inp := range cache.inputs {
+		data.Inputs = append(data.Inputs, inp)
+	}
+	for s := range cache.maxSignal {
+		data.MaxSignal = append(data.MaxSignal, s)
+	}
+	cache.dirty = false
+	cache.mu.Unlock()
+
+	tmp := cache.file + ".tmp"
+	err := writeCache(tmp, data)
+	if err == nil {
+		err = os.Rename(tmp, cache.file)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		cache.mu.Lock()
+		cache.dirty = true
+		cache.mu.Unlock()
+		return fmt.Errorf("failed to write corpus cache: %v", err)
+	}
+	return nil
+}
+
+func writeCache(file string, data *cacheData) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	if err := gob.NewEncoder(gz).Encode(data); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return f.Sync()
+}
@@ -0,0 +1,100 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/hash"
+	. "github.com/google/syzkaller/pkg/rpctype"
+)
+
+func sortedSignal(signal []uint32) []uint32 {
+	sort.Slice(signal, func(i, j int) bool { return signal[i] < signal[j] })
+	return signal
+}
+
+func TestCacheRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syz-fuzzer-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "cache")
+	inp1 := RpcInput{Call: "foo", Prog: []byte("foo()"), Signal: []uint32{1}}
+	inp2 := RpcInput{Call: "bar", Prog: []byte("bar()"), Signal: []uint32{2}}
+	inp3 := RpcInput{Call: "baz", Prog: []byte("baz()"), Signal: []uint32{3}}
+
+	cache := loadCache(file)
+	a := new(ConnectArgs)
+	cache.connectArgs(a)
+	if len(a.CachedInputs) != 0 || a.CachedRun != 0 || a.CachedMaxSignal != 0 {
+		t.Fatalf("empty cache gives non-empty connect args: %+v", a)
+	}
+	inputs, maxSignal := cache.connect(&ConnectRes{
+		Inputs:       []RpcInput{inp1, inp2},
+		MaxSignal:    []uint32{10, 11},
+		NoisySignal:  []uint32{12},
+		Run:          1,
+		MaxSignalLog: 2,
+	})
+	if len(inputs) != 2 || !reflect.DeepEqual(sortedSignal(maxSignal), []uint32{10, 11, 12}) {
+		t.Fatalf("bad connect result: %+v %v", inputs, maxSignal)
+	}
+	cache.addInput(inp3)
+	cache.poll(&PollArgs{MaxSignal: []uint32{13}}, &PollRes{MaxSignal: []uint32{14}, MaxSignalLog: 4})
+	if err := cache.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	cache = loadCache(file)
+	a = new(ConnectArgs)
+	cache.connectArgs(a)
+	sort.Strings(a.CachedInputs)
+	want := []string{hash.String(inp1.Prog), hash.String(inp2.Prog), hash.String(inp3.Prog)}
+	sort.Strings(want)
+	if !reflect.DeepEqual(a.CachedInputs, want) || a.CachedRun != 1 || a.CachedMaxSignal != 4 {
+		t.Fatalf("bad connect args: %+v", a)
+	}
+	// The same manager run: the reply contains only what is missing in the cache.
+	inputs, maxSignal = cache.connect(&ConnectRes{
+		DroppedInputs: []string{hash.String(inp2.Prog)},
+		MaxSignal:     []uint32{15},
+		Run:           1,
+		MaxSignalLog:  5,
+	})
+	if len(inputs) != 2 || !reflect.DeepEqual(sortedSignal(maxSignal), []uint32{10, 11, 12, 13, 14, 15}) {
+		t.Fatalf("bad connect result: %+v %v", inputs, maxSignal)
+	}
+	// Manager has restarted: max signal starts from scratch, inputs are kept.
+	inputs, maxSignal = cache.connect(&ConnectRes{
+		MaxSignal:    []uint32{20},
+		Run:          2,
+		MaxSignalLog: 1,
+	})
+	if len(inputs) != 2 || !reflect.DeepEqual(maxSignal, []uint32{20}) {
+		t.Fatalf("bad connect result: %+v %v", inputs, maxSignal)
+	}
+}
+
+func TestCacheCorrupted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syz-fuzzer-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "cache")
+	if err := ioutil.WriteFile(file, []byte("garbage"), 0640); err != nil {
+		t.Fatal(err)
+	}
+	cache := loadCache(file)
+	if len(cache.inputs) != 0 || len(cache.maxSignal) != 0 || cache.run != 0 {
+		t.Fatalf("corrupted cache is not empty")
+	}
+}
@@ -20,8 +20,7 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/google/syzkaller/pkg/cover"
-	"github.com/google/syzkaller/pkg/hash"
+	"github.com/google/syzkaller/pkg/fuzzer"
 	"github.com/google/syzkaller/pkg/host"
 	"github.com/google/syzkaller/pkg/ipc"
 	. "github.com/google/syzkaller/pkg/log"
@@ -40,59 +39,22 @@ var (
 	flagLeak     = flag.Bool("leak", false, "detect memory leaks")
 	flagOutput   = flag.String("output", "stdout", "write programs to none/stdout/dmesg/file")
 	flagPprof    = flag.String("pprof", "", "address to serve pprof profiles")
+	flagSchedule = flag.String("schedule", fuzzer.ScheduleFixed, "how to choose between fuzzing strategies (fixed/adaptive)")
+	flagPower    = flag.String("power", fuzzer.PowerUniform, "how to choose corpus programs for mutation (uniform/fast/explore/rarity)")
+	flagCache    = flag.String("cache", "", "corpus cache file that persists across VM restarts")
+	flagRace     = flag.Bool("race", false, "execute pairs of calls concurrently to trigger data races")
 )
 
-const (
-	programLength = 30
-)
-
-type Input struct {
-	p         *prog.Prog
-	call      int
-	signal    []uint32
-	minimized bool
-}
-
-type Candidate struct {
-	p         *prog.Prog
-	minimized bool
-}
-
 var (
 	manager *RpcClient
 	target  *prog.Target
+	gate    *ipc.Gate
+	cache   *corpusCache // nil if -cache is not specified
+	leaks   *leakTracker // nil if -leak is not specified
+
+	statExecutorFailures [ipc.FailureKindCount]uint64
 
-	signalMu     sync.RWMutex
-	corpusSignal map[uint32]struct{}
-	maxSignal    map[uint32]struct{}
-	newSignal    map[uint32]struct{}
-
-	corpusMu     sync.RWMutex
-	corpus       []*prog.Prog
-	corpusHashes map[hash.Sig]struct{}
-
-	triageMu        sync.RWMutex
-	triage          []Input
-	triageCandidate []Input
-	candidates      []Candidate
-	smashQueue      []Input
-
-	gate *ipc.Gate
-
-	statExecGen       uint64
-	statExecFuzz      uint64
-	statExecCandidate uint64
-	statExecTriage    uint64
-	statExecMinimize  uint64
-	statExecSmash     uint64
-	statNewInput      uint64
-	statExecHints     uint64
-	statExecHintSeeds uint64
-
-	allTriaged            uint32
-	noCover               bool
-	faultInjectionEnabled bool
-	compsSupported        bool
+	allTriaged uint32
 )
 
 func main() {
@@ -112,12 +74,21 @@ func main() {
 		Fatalf("%v", err)
 	}
 
+	if *flagCache != "" {
+		cache = loadCache(*flagCache)
+	}
+
 	shutdown := make(chan struct{})
 	osutil.HandleInterrupts(shutdown)
 	go func() {
 		// Handles graceful preemption on GCE.
 		<-shutdown
 		Logf(0, "SYZ-FUZZER: PREEMPTED")
+		if cache != nil {
+			if err := cache.save(); err != nil {
+				Logf(0, "%v", err)
+			}
+		}
 		os.Exit(1)
 	}()
 
@@ -130,42 +101,20 @@ func main() {
 		runtime.MemProfileRate = 0
 	}
 
-	corpusSignal = make(map[uint32]struct{})
-	maxSignal = make(map[uint32]struct{})
-	newSignal = make(map[uint32]struct{})
-	corpusHashes = make(map[hash.Sig]struct{})
-
 	Logf(0, "dialing manager at %v", *flagManager)
-	a := &ConnectArgs{*flagName}
+	a := &ConnectArgs{Name: *flagName}
+	if cache != nil {
+		cache.connectArgs(a)
+	}
 	r := &ConnectRes{}
-	if err := RpcCall(*flagManager, "Manager.Connect", a, r); err != nil {
+	if err := RpcCall(*flagManager, true, "Manager.Connect", a, r); err != nil {
 		panic(err)
 	}
 	calls := buildCallList(target, r.EnabledCalls)
 	ct := target.BuildChoiceTable(r.Prios, calls)
-	for _, inp := range r.Inputs {
-		addInput(inp)
-	}
-	for _, s := range r.MaxSignal {
-		maxSignal[s] = struct{}{}
-	}
-	for _, candidate := range r.Candidates {
-		p, err := target.Deserialize(candidate.Prog)
-		if err != nil {
-			panic(err)
-		}
-		if noCover {
-			corpusMu.Lock()
-			corpus = append(corpus, p)
-			corpusMu.Unlock()
-		} else {
-			triageMu.Lock()
-			candidates = append(candidates, Candidate{p, candidate.Minimized})
-			triageMu.Unlock()
-		}
-	}
 
 	// This requires "fault-inject: support systematic fault injection" kernel commit.
+	faultInjectionEnabled := false
 	if fd, err := syscall.Open("/proc/self/fail-nth", syscall.O_RDWR, 0); err == nil {
 		syscall.Close(fd)
 		faultInjectionEnabled = true
@@ -201,23 +150,11 @@ func main() {
 		for c := range calls {
 			a.Calls = append(a.Calls, c.Name)
 		}
-		if err := RpcCall(*flagManager, "Manager.Check", a, nil); err != nil {
+		if err := RpcCall(*flagManager, true, "Manager.Check", a, nil); err != nil {
 			panic(err)
 		}
 	}
 
-	// Manager.Connect reply can ve very large and that memory will be permanently cached in the connection.
-	// So we do the call on a transient connection, free all memory and reconnect.
-	// The rest of rpc requests have bounded size.
-	debug.FreeOSMemory()
-	if conn, err := NewRpcClient(*flagManager); err != nil {
-		panic(err)
-	} else {
-		manager = conn
-	}
-
-	kmemleakInit()
-
 	config, err := ipc.DefaultConfig()
 	if err != nil {
 		panic(err)
@@ -231,19 +168,82 @@ func main() {
 	if faultInjectionEnabled {
 		config.Flags |= ipc.FlagEnableFault
 	}
-	noCover = config.Flags&ipc.FlagSignal == 0
+	needPoll := make(chan struct{}, 1)
+	needPoll <- struct{}{}
+	engine, err := fuzzer.NewFuzzer(&fuzzer.Config{
+		Target:         target,
+		ChoiceTable:    ct,
+		Manager:        &rpcManager{needPoll},
+		Procs:          *flagProcs,
+		NoCover:        config.Flags&ipc.FlagSignal == 0,
+		FaultInjection: faultInjectionEnabled,
+		Comps:          compsSupported,
+		Schedule:       *flagSchedule,
+		Power:          *flagPower,
+		Race:           *flagRace && config.Flags&ipc.FlagThreaded != 0,
+	})
+	if err != nil {
+		Fatalf("%v", err)
+	}
+	if r.FocusWeights != nil {
+		engine.SetFocus(r.FocusWeights)
+	}
+	engine.AddFaultSites(faultSites(r.FaultSites))
+	engine.AddNoisySignal(r.NoisySignal)
+	engine.SetPaused(r.Paused)
+	inputs, maxSignal := r.Inputs, r.MaxSignal
+	if cache != nil {
+		inputs, maxSignal = cache.connect(r)
+	}
+	if *flagLeak {
+		leaks = newLeakTracker(*flagProcs)
+		leaks.addKnown(r.LeakSites)
+	}
+	for _, inp := range inputs {
+		if err := engine.AddInput(fuzzer.Input(inp)); err != nil {
+			panic(err)
+		}
+	}
+	engine.AddMaxSignal(maxSignal)
+	for _, candidate := range r.Candidates {
+		if err := engine.AddCandidate(candidate.Prog, candidate.Minimized); err != nil {
+			panic(err)
+		}
+	}
+
+	// Manager.Connect reply can ve very large and that memory will be permanently cached in the connection.
+	// So we do the call on a transient connection, free all memory and reconnect.
+	// The rest of rpc requests have bounded size.
+	r, inputs, maxSignal = nil, nil, nil
+	debug.FreeOSMemory()
+	if conn, err := NewRpcClient(*flagManager, true); err != nil {
+		panic(err)
+	} else {
+		manager = conn
+	}
+	if cache != nil {
+		// Save what we've got from manager right away,
+		// so that it is not lost if the VM crashes soon.
+		if err := cache.save(); err != nil {
+			Logf(0, "%v", err)
+		}
+	}
+
+	if err := host.KmemleakInit(*flagLeak); err != nil {
+		Fatalf("BUG: %v", err)
+	}
+
 	leakCallback := func() {
 		if atomic.LoadUint32(&allTriaged) != 0 {
 			// Scan for leaks once in a while (it is damn slow).
-			kmemleakScan(true)
+			leaks.scan(true)
 		}
 	}
 	if !*flagLeak {
 		leakCallback = nil
 	}
 	gate = ipc.NewGate(2**flagProcs, leakCallback)
-	needPoll := make(chan struct{}, 1)
-	needPoll <- struct{}{}
+	usage := newUsageTracker()
 	envs := make([]*ipc.Env, *flagProcs)
 	for pid := 0; pid < *flagProcs; pid++ {
 		env, err := ipc.MakeEnv(*flagExecutor, pid, config)
@@ -251,85 +251,15 @@ func main() {
 			panic(err)
 		}
 		envs[pid] = env
-
-		pid := pid
-		go func() {
-			rs := rand.NewSource(time.Now().UnixNano() + int64(pid)*1e12)
-			rnd := rand.New(rs)
-
-			for i := 0; ; i++ {
-				triageMu.RLock()
-				if len(triageCandidate) != 0 || len(candidates) != 0 || len(triage) != 0 || len(smashQueue) != 0 {
-					triageMu.RUnlock()
-					triageMu.Lock()
-					if len(triageCandidate) != 0 {
-						last := len(triageCandidate) - 1
-						inp := triageCandidate[last]
-						triageCandidate = triageCandidate[:last]
-						triageMu.Unlock()
-						Logf(1, "triaging candidate: %s", inp.p)
-						triageInput(pid, env, inp)
-						continue
-					} else if len(candidates) != 0 {
-						last := len(candidates) - 1
-						candidate := candidates[last]
-						candidates = candidates[:last]
-						wakePoll := len(candidates) < *flagProcs
-						triageMu.Unlock()
-						if wakePoll {
-							select {
-							case needPoll <- struct{}{}:
-							default:
-							}
-						}
-						Logf(1, "executing candidate: %s", candidate.p)
-						execute(pid, env, candidate.p, false, false, candidate.minimized, true, &statExecCandidate)
-						continue
-					} else if len(triage) != 0 {
-						last := len(triage) - 1
-						inp := triage[last]
-						triage = triage[:last]
-						triageMu.Unlock()
-						Logf(1, "triaging : %s", inp.p)
-						triageInput(pid, env, inp)
-						continue
-					} else if len(smashQueue) != 0 {
-						last := len(smashQueue) - 1
-						inp := smashQueue[last]
-						smashQueue = smashQueue[:last]
-						triageMu.Unlock()
-						Logf(1, "%v: smashing call %v in program: %v", pid, inp.call, inp.p.String())
-						smashInput(pid, env, ct, rs, inp)
-						continue
-					} else {
-						triageMu.Unlock()
-					}
-				} else {
-					triageMu.RUnlock()
-				}
-
-				corpusMu.RLock()
-				if len(corpus) == 0 || i%100 == 0 {
-					// Generate a new prog.
-					corpusMu.RUnlock()
-					p := target.Generate(rnd, programLength, ct)
-					Logf(1, "#%v: generated: %s", i, p)
-					execute(pid, env, p, false, false, false, false, &statExecGen)
-				} else {
-					// Mutate an existing prog.
-					p := corpus[rnd.Intn(len(corpus))].Clone()
-					corpusMu.RUnlock()
-					p.Mutate(rs, programLength, ct, corpus)
-					Logf(1, "#%v: mutated: %s", i, p)
-					execute(pid, env, p, false, false, false, false, &statExecFuzz)
-				}
-			}
-		}()
+		rs := rand.NewSource(time.Now().UnixNano() + int64(pid)*1e12)
+		proc := engine.NewProc(pid, &executor{pid, env, usage}, rs)
+		go proc.Loop()
 	}
 
 	var execTotal uint64
 	var lastPoll time.Time
 	var lastPrint time.Time
+	lastCacheSave := time.Now()
 	ticker := time.NewTicker(3 * time.Second).C
 	for {
 		poll := false
@@ -344,79 +274,95 @@ func main() {
 			lastPrint = time.Now()
 		}
 		if poll || time.Since(lastPoll) > 10*time.Second {
-			triageMu.RLock()
-			if len(candidates) > *flagProcs {
-				triageMu.RUnlock()
+			if engine.CandidateCount() > *flagProcs && !engine.Paused() {
 				continue
 			}
-			triageMu.RUnlock()
 
 			a := &PollArgs{
-				Name:  *flagName,
-				Stats: make(map[string]uint64),
+				Name:      *flagName,
+				MaxSignal: engine.GrabNewSignal(),
+				Stats:     engine.GrabStats(),
+			}
+			for _, stat := range engine.GrabInputStats() {
+				a.InputStats = append(a.InputStats, RpcInputStats{
+					Sig:    stat.Sig.String(),
+					Chosen: stat.Chosen,
+					Found:  stat.Found,
+				})
+			}
+			for _, site := range engine.GrabFaultSites() {
+				a.FaultSites = append(a.FaultSites, RpcFaultSite(site))
+			}
+			a.FlakySignal = engine.GrabFlakySignal()
+			a.CallStats = make(map[string]RpcCallStats)
+			for call, stats := range engine.GrabCallStats() {
+				a.CallStats[call] = RpcCallStats(*stats)
 			}
-			signalMu.Lock()
-			a.MaxSignal = make([]uint32, 0, len(newSignal))
-			for s := range newSignal {
-				a.MaxSignal = append(a.MaxSignal, s)
+			for _, pair := range engine.GrabRacePairs() {
+				a.RacePairs = append(a.RacePairs, RpcRacePair(pair))
 			}
-			newSignal = make(map[uint32]struct{})
-			signalMu.Unlock()
 			for _, env := range envs {
 				a.Stats["exec total"] += atomic.SwapUint64(&env.StatExecs, 0)
 				a.Stats["executor restarts"] += atomic.SwapUint64(&env.StatRestarts, 0)
 			}
-			execGen := atomic.SwapUint64(&statExecGen, 0)
-			a.Stats["exec gen"] = execGen
-			execTotal += execGen
-			execFuzz := atomic.SwapUint64(&statExecFuzz, 0)
-			a.Stats["exec fuzz"] = execFuzz
-			execTotal += execFuzz
-			execCandidate := atomic.SwapUint64(&statExecCandidate, 0)
-			a.Stats["exec candidate"] = execCandidate
-			execTotal += execCandidate
-			execTriage := atomic.SwapUint64(&statExecTriage, 0)
-			a.Stats["exec triage"] = execTriage
-			execTotal += execTriage
-			execMinimize := atomic.SwapUint64(&statExecMinimize, 0)
-			a.Stats["exec minimize"] = execMinimize
-			execTotal += execMinimize
-			execSmash := atomic.SwapUint64(&statExecSmash, 0)
-			a.Stats["exec smash"] = execSmash
-			execTotal += execSmash
-			a.Stats["fuzzer new inputs"] = atomic.SwapUint64(&statNewInput, 0)
+			execTotal += a.Stats["exec total"]
+			for kind := range statExecutorFailures {
+				if v := atomic.SwapUint64(&statExecutorFailures[kind], 0); v != 0 {
+					a.Stats[fmt.Sprintf("executor failure %v", ipc.FailureKind(kind))] = v
+				}
+			}
+			a.CallUsage = usage.grab(a.Stats)
+			if leaks != nil {
+				var suppressed uint64
+				a.Leaks, suppressed = leaks.grab()
+				if suppressed != 0 {
+					a.Stats["leaks suppressed"] = suppressed
+				}
+			}
 			r := &PollRes{}
 			if err := manager.Call("Manager.Poll", a, r); err != nil {
 				panic(err)
 			}
-			if len(r.MaxSignal) != 0 {
-				signalMu.Lock()
-				for _, s := range r.MaxSignal {
-					maxSignal[s] = struct{}{}
+			if cache != nil {
+				cache.poll(a, r)
+				if time.Since(lastCacheSave) > 10*time.Minute {
+					if err := cache.save(); err != nil {
+						Logf(0, "%v", err)
+					}
+					lastCacheSave = time.Now()
 				}
-				signalMu.Unlock()
+			}
+			engine.AddMaxSignal(r.MaxSignal)
+			if r.FocusWeights != nil {
+				engine.SetFocus(r.FocusWeights)
+			}
+			engine.AddFaultSites(faultSites(r.FaultSites))
+			engine.AddNoisySignal(r.NoisySignal)
+			if r.Paused != engine.Paused() {
+				Logf(0, "fuzzing paused: %v", r.Paused)
+				engine.SetPaused(r.Paused)
+			}
+			if r.EnabledCalls != "" {
+				calls := buildCallList(target, r.EnabledCalls)
+				Logf(0, "enabled syscalls changed: %v calls", len(calls))
+				engine.SetEnabledCalls(target.BuildChoiceTable(r.Prios, calls), calls)
+			}
+			if leaks != nil {
+				leaks.addKnown(r.LeakSites)
 			}
 			for _, inp := range r.NewInputs {
-				addInput(inp)
+				if err := engine.AddInput(fuzzer.Input(inp)); err != nil {
+					panic(err)
+				}
 			}
 			for _, candidate := range r.Candidates {
-				p, err := target.Deserialize(candidate.Prog)
-				if err != nil {
+				if err := engine.AddCandidate(candidate.Prog, candidate.Minimized); err != nil {
 					panic(err)
 				}
-				if noCover {
-					corpusMu.Lock()
-					corpus = append(corpus, p)
-					corpusMu.Unlock()
-				} else {
-					triageMu.Lock()
-					candidates = append(candidates, Candidate{p, candidate.Minimized})
-					triageMu.Unlock()
-				}
 			}
 			if len(r.Candidates) == 0 && atomic.LoadUint32(&allTriaged) == 0 {
-				if *flagLeak {
-					kmemleakScan(false)
+				if leaks != nil {
+					leaks.scan(false)
 				}
 				atomic.StoreUint32(&allTriaged, 1)
 			}
@@ -427,6 +373,14 @@ func main() {
 	}
 }
 
+func faultSites(sites []RpcFaultSite) []fuzzer.FaultSite {
+	res := make([]fuzzer.FaultSite, len(sites))
+	for i, site := range sites {
+		res[i] = fuzzer.FaultSite(site)
+	}
+	return res
+}
+
 func buildCallList(target *prog.Target, enabledCalls string) map[*prog.Syscall]bool {
 	calls := make(map[*prog.Syscall]bool)
 	if enabledCalls != "" {
@@ -464,249 +418,43 @@ func buildCallList(target *prog.Target, enabledCalls string) map[*prog.Syscall]b
 	return calls
 }
 
-func addInput(inp RpcInput) {
-	corpusMu.Lock()
-	defer corpusMu.Unlock()
-	signalMu.Lock()
-	defer signalMu.Unlock()
-
-	if noCover {
-		panic("should not be called when coverage is disabled")
-	}
-	p, err := target.Deserialize(inp.Prog)
-	if err != nil {
-		panic(err)
-	}
-	sig := hash.Hash(inp.Prog)
-	if _, ok := corpusHashes[sig]; !ok {
-		corpus = append(corpus, p)
-		corpusHashes[sig] = struct{}{}
-	}
-	if diff := cover.SignalDiff(maxSignal, inp.Signal); len(diff) != 0 {
-		cover.SignalAdd(corpusSignal, diff)
-		cover.SignalAdd(maxSignal, diff)
-	}
+// rpcManager implements fuzzer.Manager on top of the manager RPC connection.
+type rpcManager struct {
+	needPoll chan struct{}
 }
 
-func smashInput(pid int, env *ipc.Env, ct *prog.ChoiceTable, rs rand.Source, inp Input) {
-	if faultInjectionEnabled {
-		failCall(pid, env, inp.p, inp.call)
-	}
-	for i := 0; i < 100; i++ {
-		p := inp.p.Clone()
-		p.Mutate(rs, programLength, ct, corpus)
-		Logf(1, "#%v: mutated: %s", pid, p)
-		execute(pid, env, p, false, false, false, false, &statExecSmash)
-	}
-	if compsSupported {
-		executeHintSeed(pid, env, inp.p)
-	}
-}
-
-func failCall(pid int, env *ipc.Env, p *prog.Prog, call int) {
-	for nth := 0; nth < 100; nth++ {
-		Logf(1, "%v: injecting fault into call %v/%v in program: %v", pid, call, nth, p.String())
-		opts := &ipc.ExecOpts{
-			Flags:     ipc.FlagInjectFault,
-			FaultCall: call,
-			FaultNth:  nth,
-		}
-		info := execute1(pid, env, opts, p, &statExecSmash)
-		if info != nil && len(info) > call && !info[call].FaultInjected {
-			break
-		}
-	}
-}
-
-func triageInput(pid int, env *ipc.Env, inp Input) {
-	if noCover {
-		panic("should not be called when coverage is disabled")
-	}
-
-	signalMu.RLock()
-	newSignal := cover.SignalDiff(corpusSignal, inp.signal)
-	signalMu.RUnlock()
-	if len(newSignal) == 0 {
-		return
-	}
-	newSignal = cover.Canonicalize(newSignal)
-
-	call := inp.p.Calls[inp.call].Meta
-	data := inp.p.Serialize()
-	sig := hash.Hash(data)
-
-	Logf(3, "triaging input for %v (new signal=%v):\n%s", call.CallName, len(newSignal), data)
-	var inputCover cover.Cover
-	opts := &ipc.ExecOpts{
-		Flags: ipc.FlagCollectCover,
-	}
-	if inp.minimized {
-		// We just need to get input coverage.
-		for i := 0; i < 3; i++ {
-			info := execute1(pid, env, opts, inp.p, &statExecTriage)
-			if len(info) == 0 || len(info[inp.call].Cover) == 0 {
-				continue // The call was not executed. Happens sometimes.
-			}
-			inputCover = append([]uint32{}, info[inp.call].Cover...)
-			break
-		}
-	} else {
-		// We need to compute input coverage and non-flaky signal for minimization.
-		notexecuted := false
-		for i := 0; i < 3; i++ {
-			info := execute1(pid, env, opts, inp.p, &statExecTriage)
-			if len(info) == 0 || len(info[inp.call].Signal) == 0 {
-				// The call was not executed. Happens sometimes.
-				if notexecuted {
-					return // if it happened twice, give up
-				}
-				notexecuted = true
-				continue
-			}
-			inf := info[inp.call]
-			newSignal = cover.Intersection(newSignal, cover.Canonicalize(inf.Signal))
-			if len(newSignal) == 0 {
-				return
-			}
-			if len(inputCover) == 0 {
-				inputCover = append([]uint32{}, inf.Cover...)
-			} else {
-				inputCover = cover.Union(inputCover, inf.Cover)
-			}
-		}
-
-		inp.p, inp.call = prog.Minimize(inp.p, inp.call, func(p1 *prog.Prog, call1 int) bool {
-			info := execute(pid, env, p1, false, false, false, false, &statExecMinimize)
-			if len(info) == 0 || len(info[call1].Signal) == 0 {
-				return false // The call was not executed.
-			}
-			inf := info[call1]
-			signal := cover.Canonicalize(inf.Signal)
-			signalMu.RLock()
-			defer signalMu.RUnlock()
-			if len(cover.Intersection(newSignal, signal)) != len(newSignal) {
-				return false
-			}
-			return true
-		}, false)
-	}
-
-	atomic.AddUint64(&statNewInput, 1)
-	Logf(2, "added new input for %v to corpus:\n%s", call.CallName, data)
+func (mgr *rpcManager) NewInput(inp fuzzer.Input) error {
 	a := &NewInputArgs{
-		Name: *flagName,
-		RpcInput: RpcInput{
-			Call:   call.CallName,
-			Prog:   data,
-			Signal: []uint32(cover.Canonicalize(inp.signal)),
-			Cover:  []uint32(inputCover),
-		},
+		Name:     *flagName,
+		RpcInput: RpcInput(inp),
 	}
 	if err := manager.Call("Manager.NewInput", a, nil); err != nil {
-		panic(err)
+		return err
 	}
-
-	signalMu.Lock()
-	cover.SignalAdd(corpusSignal, inp.signal)
-	signalMu.Unlock()
-
-	corpusMu.Lock()
-	if _, ok := corpusHashes[sig]; !ok {
-		corpus = append(corpus, inp.p)
-		corpusHashes[sig] = struct{}{}
-	}
-	corpusMu.Unlock()
-
-	if !inp.minimized {
-		triageMu.Lock()
-		smashQueue = append(smashQueue, inp)
-		triageMu.Unlock()
+	if cache != nil {
+		cache.addInput(a.RpcInput)
 	}
+	return nil
 }
 
-func executeHintSeed(pid int, env *ipc.Env, p *prog.Prog) {
-	if !compsSupported {
-		panic("compsSupported==false and executeHintSeed() called")
+func (mgr *rpcManager) NeedCandidates() {
+	select {
+	case mgr.needPoll <- struct{}{}:
+	default:
 	}
-	// First execute the original program to dump comparisons from KCOV.
-	info := execute(pid, env, p, false, true, false, false, &statExecHintSeeds)
-
-	// Then extract the comparisons data.
-	compMaps := ipc.GetCompMaps(info)
-
-	// Then mutate the initial program for every match between
-	// a syscall argument and a comparison operand.
-	// Execute each of such mutants to check if it gives new coverage.
-	p.MutateWithHints(compMaps, func(p *prog.Prog) {
-		execute(pid, env, p, false, false, false, false, &statExecHints)
-	})
 }
 
-func execute(pid int, env *ipc.Env, p *prog.Prog, needCover, needComps, minimized, candidate bool, stat *uint64) []ipc.CallInfo {
-	opts := &ipc.ExecOpts{}
-	if needComps {
-		if !compsSupported {
-			panic("compsSupported==false and execute() called with needComps")
-		}
-		if needCover {
-			// Currently KCOV is able to dump only the coverage data or only
-			// the comparisons data. We can't enable both modes at same time.
-			panic("only one of the needComps and needCover should be true")
-		}
-		opts.Flags |= ipc.FlagCollectComps
-	}
-	if needCover {
-		opts.Flags |= ipc.FlagCollectCover
-	}
-	info := execute1(pid, env, opts, p, stat)
-	signalMu.RLock()
-	defer signalMu.RUnlock()
-
-	for i, inf := range info {
-		if !cover.SignalNew(maxSignal, inf.Signal) {
-			continue
-		}
-		diff := cover.SignalDiff(maxSignal, inf.Signal)
-
-		signalMu.RUnlock()
-		signalMu.Lock()
-		cover.SignalAdd(maxSignal, diff)
-		cover.SignalAdd(newSignal, diff)
-		signalMu.Unlock()
-		signalMu.RLock()
-
-		inp := Input{
-			p:         p.Clone(),
-			call:      i,
-			signal:    append([]uint32{}, inf.Signal...),
-			minimized: minimized,
-		}
-		triageMu.Lock()
-		if candidate {
-			triageCandidate = append(triageCandidate, inp)
-		} else {
-			triage = append(triage, inp)
-		}
-		triageMu.Unlock()
-	}
-	return info
+// executor implements fuzzer.Executor on top of ipc.Env.
+type executor struct {
+	pid   int
+	env   *ipc.Env
+	usage *usageTracker
 }
 
 var logMu sync.Mutex
 
-func execute1(pid int, env *ipc.Env, opts *ipc.ExecOpts, p *prog.Prog, stat *uint64) []ipc.CallInfo {
-	if false {
-		// For debugging, this function must not be executed with locks held.
-		corpusMu.Lock()
-		corpusMu.Unlock()
-		signalMu.Lock()
-		signalMu.Unlock()
-		triageMu.Lock()
-		triageMu.Unlock()
-	}
-
-	opts.Flags |= ipc.FlagDedupCover
-
+func (exec *executor) Exec(opts *ipc.ExecOpts, p *prog.Prog) []ipc.CallInfo {
+	pid, env := exec.pid, exec.env
 	// Limit concurrency window and do leak checking once in a while.
 	idx := gate.Enter()
 	defer gate.Leave(idx)
@@ -715,6 +463,18 @@ func execute1(pid int, env *ipc.Env, opts *ipc.ExecOpts, p *prog.Prog, stat *uin
 	if opts.Flags&ipc.FlagInjectFault != 0 {
 		strOpts = fmt.Sprintf(" (fault-call:%v fault-nth:%v)", opts.FaultCall, opts.FaultNth)
 	}
+	if opts.Flags&ipc.FlagRace != 0 {
+		affinity := 0
+		if opts.Flags&ipc.FlagRaceAffinity != 0 {
+			affinity = 1
+		}
+		strOpts = fmt.Sprintf(" (race-call1:%v race-call2:%v race-delay:%v race-affinity:%v)",
+			opts.RaceCall1, opts.RaceCall2, opts.RaceDelay, affinity)
+	}
+
+	if leaks != nil {
+		leaks.executing(pid, strOpts, p.Serialize())
+	}
 
 	// The following output helps to understand what program crashed kernel.
 	// It must not be intermixed.
@@ -747,8 +507,7 @@ func execute1(pid int, env *ipc.Env, opts *ipc.ExecOpts, p *prog.Prog, stat *uin
 
 	try := 0
 retry:
-	atomic.AddUint64(stat, 1)
-	output, info, failed, hanged, err := env.Exec(opts, p)
+	output, info, usage, failed, hanged, err := env.Exec(opts, p)
 	if failed {
 		// BUG in output should be recognized by manager.
 		Logf(0, "BUG: executor-detected bug:\n%s", output)
@@ -756,7 +515,16 @@ retry:
 		return nil
 	}
 	if err != nil {
-		if _, ok := err.(ipc.ExecutorFailure); ok || try > 10 {
+		if failure, ok := err.(ipc.ExecutorFailure); ok {
+			atomic.AddUint64(&statExecutorFailures[failure.Kind], 1)
+			// Timeouts are frequently transient (e.g. slow sandbox setup under load), so retry them.
+			if failure.Kind != ipc.FailureTimeout || try > 10 {
+				// Tell manager what kind of failure has happened,
+				// it decides whether this is a kernel bug or an infrastructure problem.
+				Logf(0, "SYZ-FUZZER: EXECUTOR FAILURE (%v)", failure.Kind)
+				panic(err)
+			}
+		} else if try > 10 {
 			panic(err)
 		}
 		try++
@@ -766,5 +534,6 @@ retry:
 		goto retry
 	}
 	Logf(2, "result failed=%v hanged=%v: %v\n", failed, hanged, string(output))
+	exec.usage.record(p, &usage)
 	return info
 }
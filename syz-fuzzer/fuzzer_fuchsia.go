@@ -5,19 +5,6 @@
 
 package main
 
-import (
-	"github.com/google/syzkaller/pkg/log"
-)
-
-func kmemleakInit() {
-	if *flagLeak {
-		log.Fatalf("leak checking is not supported on fuchsia")
-	}
-}
-
-func kmemleakScan(report bool) {
-}
-
 func checkCompsSupported() (kcov, comps bool) {
 	return false, false
 }
@@ -3,19 +3,6 @@
 
 package main
 
-import (
-	"github.com/google/syzkaller/pkg/log"
-)
-
-func kmemleakInit() {
-	if *flagLeak {
-		log.Fatalf("leak checking is not supported on windows")
-	}
-}
-
-func kmemleakScan(report bool) {
-}
-
 func checkCompsSupported() (kcov, comps bool) {
 	return false, false
 }
@@ -0,0 +1,108 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/google/syzkaller/pkg/host"
+	. "github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/report"
+	. "github.com/google/syzkaller/pkg/rpctype"
+)
+
+// leakTracker deduplicates kmemleak reports by allocation site.
+// Leaks in already known sites (found by this or other fuzzers) are suppressed,
+// leaks in new sites are sent to manager together with the recently executed programs,
+// so that the test machine does not need to be rebooted.
+type leakTracker struct {
+	mu         sync.Mutex
+	known      map[string]bool
+	pending    []RpcLeak
+	suppressed uint64
+	progs      [][]byte // ring buffer of recently executed programs in execution log format
+	pos        int
+}
+
+func newLeakTracker(procs int) *leakTracker {
+	return &leakTracker{
+		known: make(map[string]bool),
+		// Leaks are scanned for every 2*procs executions (see ipc.Gate),
+		// keep programs for the last 2 scan periods since kmemleak reports leaks with a delay.
+		progs: make([][]byte, 4*procs),
+	}
+}
+
+// addKnown adds leak sites known to manager.
+func (lt *leakTracker) addKnown(sites []string) {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	for _, site := range sites {
+		lt.known[site] = true
+	}
+}
+
+// executing records a program about to be executed.
+func (lt *leakTracker) executing(pid int, strOpts string, data []byte) {
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "executing program %v%v:\n%s\n", pid, strOpts, data)
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	lt.progs[lt.pos] = buf.Bytes()
+	lt.pos = (lt.pos + 1) % len(lt.progs)
+}
+
+// scan scans for leaks, must be called when no programs are running.
+func (lt *leakTracker) scan(report bool) {
+	output, err := host.KmemleakScan(report)
+	if err != nil {
+		panic(err)
+	}
+	if len(output) != 0 {
+		lt.add(output)
+	}
+}
+
+// add parses kmemleak output and queues leaks in new sites for manager.
+func (lt *leakTracker) add(output []byte) {
+	leaks := report.ParseLeaks(output)
+	if len(leaks) == 0 {
+		// BUG in output should be recognized by manager.
+		Logf(0, "BUG: memory leak:\n%s\n", output)
+		return
+	}
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	var log []byte
+	for _, leak := range leaks {
+		if lt.known[leak.Site] {
+			Logf(1, "suppressing known memory leak in %v", leak.Site)
+			lt.suppressed++
+			continue
+		}
+		Logf(0, "found memory leak in %v", leak.Site)
+		lt.known[leak.Site] = true
+		if log == nil {
+			for i := range lt.progs {
+				log = append(log, lt.progs[(lt.pos+i)%len(lt.progs)]...)
+			}
+		}
+		lt.pending = append(lt.pending, RpcLeak{
+			Site:   leak.Site,
+			Report: leak.Report,
+			Log:    log,
+		})
+	}
+}
+
+// grab returns leaks in new sites and the number of suppressed leaks since the previous call.
+func (lt *leakTracker) grab() ([]RpcLeak, uint64) {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	leaks, suppressed := lt.pending, lt.suppressed
+	lt.pending, lt.suppressed = nil, 0
+	return leaks, suppressed
+}
@@ -0,0 +1,85 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/syzkaller/pkg/ipc"
+	. "github.com/google/syzkaller/pkg/rpctype"
+	"github.com/google/syzkaller/prog"
+)
+
+// usageTracker accumulates resource usage of executed programs per syscall
+// until it is sent to manager with the next poll.
+type usageTracker struct {
+	mu        sync.Mutex
+	calls     map[string]*RpcCallUsage
+	limitsHit [ipc.LimitCount]uint64
+}
+
+func newUsageTracker() *usageTracker {
+	return &usageTracker{
+		calls: make(map[string]*RpcCallUsage),
+	}
+}
+
+// record attributes resource usage of program p to all syscalls in the program.
+func (ut *usageTracker) record(p *prog.Prog, usage *ipc.ResourceUsage) {
+	memory := usage.MaxRSS
+	if memory < usage.Memory {
+		memory = usage.Memory
+	}
+	ut.mu.Lock()
+	defer ut.mu.Unlock()
+	for i := 0; i < ipc.LimitCount; i++ {
+		if usage.LimitsHit&(1<<uint(i)) != 0 {
+			ut.limitsHit[i]++
+		}
+	}
+	for _, c := range p.Calls {
+		stat := ut.calls[c.Meta.Name]
+		if stat == nil {
+			stat = new(RpcCallUsage)
+			ut.calls[c.Meta.Name] = stat
+		}
+		if stat.MaxMemory < memory {
+			stat.MaxMemory = memory
+		}
+		if stat.MaxFds < usage.Fds {
+			stat.MaxFds = usage.Fds
+		}
+		if stat.MaxPids < usage.Pids {
+			stat.MaxPids = usage.Pids
+		}
+		for i := 0; i < ipc.LimitCount; i++ {
+			if usage.LimitsHit&(1<<uint(i)) != 0 {
+				if stat.Limits == nil {
+					stat.Limits = make(map[string]uint64)
+				}
+				stat.Limits[ipc.LimitName(i)]++
+			}
+		}
+	}
+}
+
+// grab returns per-syscall usage accumulated since the previous call
+// and adds the number of programs that hit each limit to stats.
+func (ut *usageTracker) grab(stats map[string]uint64) map[string]RpcCallUsage {
+	ut.mu.Lock()
+	defer ut.mu.Unlock()
+	for i, v := range ut.limitsHit {
+		if v != 0 {
+			stats[fmt.Sprintf("resource limit %v", ipc.LimitName(i))] += v
+		}
+	}
+	ut.limitsHit = [ipc.LimitCount]uint64{}
+	res := make(map[string]RpcCallUsage, len(ut.calls))
+	for call, stat := range ut.calls {
+		res[call] = *stat
+	}
+	ut.calls = make(map[string]*RpcCallUsage)
+	return res
+}
@@ -0,0 +1,51 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/google/syzkaller/pkg/ipc"
+	"github.com/google/syzkaller/prog"
+	_ "github.com/google/syzkaller/sys"
+)
+
+func TestUsageTracker(t *testing.T) {
+	target, err := prog.GetTarget("linux", "amd64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p1, err := target.Deserialize([]byte("getpid()\ngetuid()\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2, err := target.Deserialize([]byte("getpid()\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ut := newUsageTracker()
+	ut.record(p1, &ipc.ResourceUsage{MaxRSS: 10 << 20, Fds: 5})
+	ut.record(p2, &ipc.ResourceUsage{MaxRSS: 1 << 20, Memory: 20 << 20, Fds: 3, Pids: 2,
+		LimitsHit: ipc.LimitFds | ipc.LimitCPU})
+	stats := make(map[string]uint64)
+	usage := ut.grab(stats)
+	if len(usage) != 2 {
+		t.Fatalf("want usage of 2 calls, got %+v", usage)
+	}
+	getpid, getuid := usage["getpid"], usage["getuid"]
+	if getpid.MaxMemory != 20<<20 || getpid.MaxFds != 5 || getpid.MaxPids != 2 ||
+		len(getpid.Limits) != 2 || getpid.Limits["fds"] != 1 || getpid.Limits["cpu"] != 1 {
+		t.Errorf("bad getpid usage: %+v", getpid)
+	}
+	if getuid.MaxMemory != 10<<20 || getuid.MaxFds != 5 || getuid.MaxPids != 0 || len(getuid.Limits) != 0 {
+		t.Errorf("bad getuid usage: %+v", getuid)
+	}
+	if len(stats) != 2 || stats["resource limit fds"] != 1 || stats["resource limit cpu"] != 1 {
+		t.Errorf("bad stats: %+v", stats)
+	}
+	stats = make(map[string]uint64)
+	if usage := ut.grab(stats); len(usage) != 0 || len(stats) != 0 {
+		t.Errorf("usage is not reset after grab: %+v %+v", usage, stats)
+	}
+}
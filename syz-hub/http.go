@@ -12,10 +12,14 @@ import (
 	"strings"
 
 	. "github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/stats"
 )
 
 func (hub *Hub) initHttp(addr string) {
 	http.HandleFunc("/", hub.httpSummary)
+	metrics := new(stats.Registry)
+	metrics.Register(hub.collectMetrics)
+	http.Handle("/metrics", metrics)
 
 	ln, err := net.Listen("tcp4", addr)
 	if err != nil {
@@ -65,6 +69,21 @@ func (hub *Hub) httpSummary(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (hub *Hub) collectMetrics(set *stats.Set) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	set.Add(stats.HubCorpusSize, float64(len(hub.st.Corpus.Records)))
+	set.Add(stats.HubRepros, float64(len(hub.st.Repros.Records)))
+	for name, mgr := range hub.st.Managers {
+		set.Add(stats.HubManagerCorpus, float64(len(mgr.Corpus.Records)), name)
+		set.Add(stats.HubAdded, float64(mgr.Added), name)
+		set.Add(stats.HubDeleted, float64(mgr.Deleted), name)
+		set.Add(stats.HubNew, float64(mgr.New), name)
+		set.Add(stats.HubSentRepros, float64(mgr.SentRepros), name)
+		set.Add(stats.HubRecvRepros, float64(mgr.RecvRepros), name)
+	}
+}
+
 func compileTemplate(html string) *template.Template {
 	return template.Must(template.New("").Parse(strings.Replace(html, "{{STYLE}}", htmlStyle, -1)))
 }
@@ -57,7 +57,7 @@ func main() {
 
 	hub.initHttp(cfg.Http)
 
-	s, err := NewRpcServer(cfg.Rpc, hub)
+	s, err := NewRpcServer(cfg.Rpc, hub, false)
 	if err != nil {
 		Fatalf("failed to create rpc server: %v", err)
 	}
@@ -0,0 +1,317 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/syzkaller/pkg/cover"
+	. "github.com/google/syzkaller/pkg/rpctype"
+)
+
+// JSON API under /api/v1/. The HTML pages are rendered from the same data.
+// All times are in RFC 3339 format, all durations are in seconds.
+func (mgr *Manager) initApi() {
+	http.HandleFunc("/api/v1/stats", mgr.apiStats)
+	http.HandleFunc("/api/v1/crashes", mgr.apiCrashes)
+	http.HandleFunc("/api/v1/crash", mgr.apiCrash)
+	http.HandleFunc("/api/v1/corpus", mgr.apiCorpus)
+	http.HandleFunc("/api/v1/syscalls", mgr.apiSyscalls)
+	http.HandleFunc("/api/v1/vms", mgr.apiVMs)
+	http.HandleFunc("/api/v1/repro", mgr.apiRepro)
+	http.HandleFunc("/api/v1/hub", mgr.apiHub)
+}
+
+type APIStats struct {
+	Name         string
+	Uptime       int64 // seconds
+	FuzzingTime  int64 // seconds
+	FirstConnect time.Time
+	Corpus       int
+	TriageQueue  int
+	Cover        int
+	Signal       int
+	Syscalls     int
+	LeakSites    int
+	RacePairs    int
+	FlakySignal  int
+	NoisySignal  int
+	FaultSites   int
+	Focus        *APIFocus // nil if focus is not configured
+	Counters     map[string]uint64
+	Calls        []UICallType
+}
+
+type APIFocus struct {
+	Computing bool
+	Targets   int
+	Reached   int
+}
+
+type APICrash struct {
+	*UICrashType
+	Logs        []APICrashLog
+	Repro       string
+	CRepro      string
+	ReproReport string
+}
+
+type APICrashLog struct {
+	Index     int
+	Time      time.Time
+	Tag       string
+	Log       string
+	Report    string
+	Signature string
+	Frames    []string
+}
+
+type APIHub struct {
+	Enabled bool
+	HubStatus
+	Counters map[string]uint64
+}
+
+func (mgr *Manager) apiStats(w http.ResponseWriter, r *http.Request) {
+	mgr.mu.Lock()
+	data := mgr.collectStats()
+	mgr.mu.Unlock()
+	writeJSON(w, data)
+}
+
+func (mgr *Manager) apiCrashes(w http.ResponseWriter, r *http.Request) {
+	crashes, err := mgr.collectCrashes()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to collect crashes: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if crashes == nil {
+		crashes = []*UICrashType{}
+	}
+	writeJSON(w, crashes)
+}
+
+func (mgr *Manager) apiCrash(w http.ResponseWriter, r *http.Request) {
+	crashID := r.FormValue("id")
+	crash := readCrash(mgr.cfg.Workdir, crashID, true)
+	if crash == nil {
+		http.Error(w, fmt.Sprintf("unknown crash %q", crashID), http.StatusNotFound)
+		return
+	}
+	mgr.markReproducing([]*UICrashType{crash})
+	data := &APICrash{
+		UICrashType: crash,
+		Logs:        []APICrashLog{},
+	}
+	for _, c := range crash.Crashes {
+		log, _ := readCrashLog(filepath.Join(mgr.cfg.Workdir, c.Log))
+		var report []byte
+		if c.Report != "" {
+			report, _ = ioutil.ReadFile(filepath.Join(mgr.cfg.Workdir, c.Report))
+		}
+		data.Logs = append(data.Logs, APICrashLog{
+			Index:     c.Index,
+			Time:      c.Time,
+			Tag:       c.Tag,
+			Log:       string(log),
+			Report:    string(report),
+			Signature: c.Signature,
+			Frames:    c.Frames,
+		})
+	}
+	crash.Crashes = nil
+	dir := filepath.Join(mgr.cfg.Workdir, "crashes", crashID)
+	data.Repro = readFileString(filepath.Join(dir, "repro.prog"))
+	data.CRepro = readFileString(filepath.Join(dir, "repro.cprog"))
+	data.ReproReport = readFileString(filepath.Join(dir, "repro.report"))
+	writeJSON(w, data)
+}
+
+func (mgr *Manager) apiCorpus(w http.ResponseWriter, r *http.Request) {
+	data, err := mgr.collectCorpus(r.FormValue("call"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if data == nil {
+		data = []UIInput{}
+	}
+	writeJSON(w, data)
+}
+
+func (mgr *Manager) apiSyscalls(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, mgr.collectSyscalls())
+}
+
+func (mgr *Manager) apiVMs(w http.ResponseWriter, r *http.Request) {
+	mgr.mu.Lock()
+	data := []VMState{}
+	for _, vm := range mgr.vms {
+		data = append(data, *vm)
+	}
+	mgr.mu.Unlock()
+	writeJSON(w, data)
+}
+
+func (mgr *Manager) apiRepro(w http.ResponseWriter, r *http.Request) {
+	mgr.mu.Lock()
+	data := ReproStatus{
+		Pending:     append([]string{}, mgr.reproStatus.Pending...),
+		Queued:      append([]string{}, mgr.reproStatus.Queued...),
+		Reproducing: append([]string{}, mgr.reproStatus.Reproducing...),
+	}
+	mgr.mu.Unlock()
+	sort.Strings(data.Pending)
+	sort.Strings(data.Reproducing)
+	writeJSON(w, data)
+}
+
+func (mgr *Manager) apiHub(w http.ResponseWriter, r *http.Request) {
+	mgr.mu.Lock()
+	data := &APIHub{
+		Enabled:   mgr.cfg.Hub_Client != "",
+		HubStatus: mgr.hubStatus,
+		Counters:  make(map[string]uint64),
+	}
+	for k, v := range mgr.stats {
+		if strings.HasPrefix(k, "hub ") {
+			data.Counters[k] = v
+		}
+	}
+	mgr.mu.Unlock()
+	writeJSON(w, data)
+}
+
+// collectStats must be called with mgr.mu held.
+func (mgr *Manager) collectStats() *APIStats {
+	data := &APIStats{
+		Name:         mgr.cfg.Name,
+		Uptime:       int64(time.Since(mgr.startTime) / time.Second),
+		FuzzingTime:  int64(mgr.fuzzingTime / time.Second),
+		FirstConnect: mgr.firstConnect,
+		Corpus:       len(mgr.corpus),
+		TriageQueue:  len(mgr.candidates),
+		Cover:        len(mgr.corpusCover),
+		Signal:       len(mgr.corpusSignal),
+		Syscalls:     len(mgr.enabledCalls),
+		LeakSites:    len(mgr.leakSites),
+		RacePairs:    len(mgr.racePairs),
+		FlakySignal:  len(mgr.flakySignal),
+		NoisySignal:  len(mgr.noisySignal),
+		FaultSites:   len(mgr.faultSites),
+		Counters:     make(map[string]uint64),
+		Calls:        []UICallType{},
+	}
+	if len(mgr.cfg.ParsedFocus) != 0 {
+		data.Focus = &APIFocus{Computing: true}
+		if mgr.focus != nil {
+			data.Focus = &APIFocus{
+				Targets: len(mgr.focus.targets),
+				Reached: mgr.focus.reached(mgr.corpusCover),
+			}
+		}
+	}
+	for k, v := range mgr.stats {
+		data.Counters[k] = v
+	}
+
+	type CallCov struct {
+		count int
+		cov   cover.Cover
+	}
+	calls := make(map[string]*CallCov)
+	for _, inp := range mgr.corpus {
+		if calls[inp.Call] == nil {
+			calls[inp.Call] = new(CallCov)
+		}
+		cc := calls[inp.Call]
+		cc.count++
+		cc.cov = cover.Union(cc.cov, cover.Cover(inp.Cover))
+	}
+	for c, cc := range calls {
+		data.Calls = append(data.Calls, UICallType{
+			Name:   c,
+			Inputs: cc.count,
+			Cover:  len(cc.cov),
+		})
+	}
+	sort.Sort(UICallTypeArray(data.Calls))
+	return data
+}
+
+// collectCorpus returns corpus inputs for the call.
+func (mgr *Manager) collectCorpus(call string) ([]UIInput, error) {
+	// Deserialization of the whole corpus takes a while, so only copy inputs under the lock.
+	mgr.mu.Lock()
+	inputs := make(map[string]RpcInput)
+	for sig, inp := range mgr.corpus {
+		if call == "" || call == inp.Call {
+			inputs[sig] = inp
+		}
+	}
+	mgr.mu.Unlock()
+	var data []UIInput
+	for sig, inp := range inputs {
+		p, err := mgr.target.Deserialize(inp.Prog)
+		if err != nil {
+			return nil, fmt.Errorf("failed to deserialize program: %v", err)
+		}
+		var calls []string
+		for _, c := range p.Calls {
+			calls = append(calls, c.Meta.Name)
+		}
+		data = append(data, UIInput{
+			Short:  p.String(),
+			Full:   string(inp.Prog),
+			Call:   inp.Call,
+			Calls:  calls,
+			Signal: len(inp.Signal),
+			Cover:  len(inp.Cover),
+			Sig:    sig,
+		})
+	}
+	sort.Sort(UIInputArray(data))
+	return data, nil
+}
+
+func (mgr *Manager) collectCrashes() ([]*UICrashType, error) {
+	crashes, err := collectCrashes(mgr.cfg.Workdir)
+	if err != nil {
+		return nil, err
+	}
+	mgr.markReproducing(crashes)
+	return crashes, nil
+}
+
+// markReproducing sets Reproducing for crashes that are currently in the repro pipeline.
+func (mgr *Manager) markReproducing(crashes []*UICrashType) {
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	reproducing := make(map[string]bool)
+	for _, desc := range mgr.reproStatus.Reproducing {
+		reproducing[desc] = true
+	}
+	for _, crash := range crashes {
+		crash.Reproducing = reproducing[crash.Description]
+	}
+}
+
+func readFileString(file string) string {
+	data, _ := ioutil.ReadFile(file)
+	return string(data)
+}
+
+func writeJSON(w http.ResponseWriter, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		http.Error(w, fmt.Sprintf("failed to encode json: %v", err), http.StatusInternalServerError)
+	}
+}
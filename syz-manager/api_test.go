@@ -0,0 +1,135 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/pkg/hash"
+	"github.com/google/syzkaller/pkg/osutil"
+	. "github.com/google/syzkaller/pkg/rpctype"
+	"github.com/google/syzkaller/prog"
+	_ "github.com/google/syzkaller/sys"
+	"github.com/google/syzkaller/syz-manager/mgrconfig"
+)
+
+func testAPI(t *testing.T, handler http.HandlerFunc, url string, res interface{}) {
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest("GET", url, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("%v: got code %v: %s", url, w.Code, w.Body.Bytes())
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), res); err != nil {
+		t.Fatalf("%v: failed to parse reply: %v\n%s", url, err, w.Body.Bytes())
+	}
+}
+
+func TestAPI(t *testing.T) {
+	target, err := prog.GetTarget("linux", "amd64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	workdir, err := ioutil.TempDir("", "syz-manager-api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(workdir)
+	osutil.MkdirAll(filepath.Join(workdir, "crashes"))
+	inp1 := RpcInput{Call: "getpid", Prog: []byte("getpid()\n"), Signal: []uint32{1, 2}, Cover: []uint32{10}}
+	inp2 := RpcInput{Call: "getuid", Prog: []byte("getuid()\ngetpid()\n"), Signal: []uint32{3}, Cover: []uint32{20}}
+	getpidStats := &CallStats{Execs: 10, Errnos: map[int]uint64{0: 10}, Limits: map[string]uint64{}}
+	mgr := &Manager{
+		cfg:          &mgrconfig.Config{Name: "test", Workdir: workdir},
+		target:       target,
+		startTime:    time.Now(),
+		stats:        map[string]uint64{"exec total": 100, "hub add": 1},
+		corpus:       map[string]RpcInput{hash.String(inp1.Prog): inp1, hash.String(inp2.Prog): inp2},
+		corpusSignal: map[uint32]struct{}{1: {}, 2: {}, 3: {}},
+		corpusCover:  map[uint32]struct{}{10: {}, 20: {}},
+		enabledCalls: []string{"getpid", "getuid"},
+		callStats:    map[string]*CallStats{"getpid": getpidStats},
+		vms:          []*VMState{{Index: 0, State: vmFuzzing}},
+		reproStatus:  ReproStatus{Pending: []string{"b"}, Reproducing: []string{"WARNING in foo"}},
+	}
+
+	stats := new(APIStats)
+	testAPI(t, mgr.apiStats, "/api/v1/stats", stats)
+	if stats.Name != "test" || stats.Corpus != 2 || stats.Signal != 3 || stats.Cover != 2 ||
+		stats.Counters["exec total"] != 100 || len(stats.Calls) != 2 {
+		t.Errorf("bad stats: %+v", stats)
+	}
+
+	var corpus []UIInput
+	testAPI(t, mgr.apiCorpus, "/api/v1/corpus", &corpus)
+	if len(corpus) != 2 {
+		t.Errorf("bad corpus: %+v", corpus)
+	}
+	testAPI(t, mgr.apiCorpus, "/api/v1/corpus?call=getuid", &corpus)
+	if len(corpus) != 1 || corpus[0].Call != "getuid" || len(corpus[0].Calls) != 2 || corpus[0].Signal != 1 {
+		t.Errorf("bad corpus for getuid: %+v", corpus)
+	}
+
+	mgr.addCallUsage("getpid", RpcCallUsage{MaxMemory: 2 << 20, MaxFds: 5, Limits: map[string]uint64{"fds": 1}})
+	mgr.addCallUsage("getpid", RpcCallUsage{MaxMemory: 1 << 20, MaxFds: 7, Limits: map[string]uint64{"fds": 2}})
+	var syscalls []UISyscall
+	testAPI(t, mgr.apiSyscalls, "/api/v1/syscalls", &syscalls)
+	if len(syscalls) != 2 || syscalls[0].Name != "getpid" || syscalls[0].Execs != 10 ||
+		syscalls[0].MaxMemory != 2 || syscalls[0].MaxFds != 7 || syscalls[0].LimitHits != 3 {
+		t.Errorf("bad syscalls: %+v", syscalls)
+	}
+
+	var vms []VMState
+	testAPI(t, mgr.apiVMs, "/api/v1/vms", &vms)
+	if len(vms) != 1 || vms[0].State != vmFuzzing {
+		t.Errorf("bad vms: %+v", vms)
+	}
+
+	repro := new(ReproStatus)
+	testAPI(t, mgr.apiRepro, "/api/v1/repro", repro)
+	if len(repro.Pending) != 1 || len(repro.Queued) != 0 || len(repro.Reproducing) != 1 {
+		t.Errorf("bad repro status: %+v", repro)
+	}
+
+	hub := new(APIHub)
+	testAPI(t, mgr.apiHub, "/api/v1/hub", hub)
+	if hub.Enabled || len(hub.Counters) != 1 || hub.Counters["hub add"] != 1 {
+		t.Errorf("bad hub status: %+v", hub)
+	}
+
+	var crashes []*UICrashType
+	testAPI(t, mgr.apiCrashes, "/api/v1/crashes", &crashes)
+	if len(crashes) != 0 {
+		t.Errorf("bad crashes: %+v", crashes)
+	}
+	const title = "WARNING in foo"
+	id := hash.String([]byte(title))
+	crashdir := filepath.Join(workdir, "crashes", id)
+	osutil.MkdirAll(crashdir)
+	osutil.WriteFile(filepath.Join(crashdir, "description"), []byte(title+"\n"))
+	osutil.WriteFile(filepath.Join(crashdir, "log0"), []byte("crash log"))
+	osutil.WriteFile(filepath.Join(crashdir, "report0"), []byte("crash report"))
+	osutil.WriteFile(filepath.Join(crashdir, "repro.prog"), []byte("getpid()\n"))
+	testAPI(t, mgr.apiCrashes, "/api/v1/crashes", &crashes)
+	if len(crashes) != 1 || crashes[0].Description != title || !crashes[0].HasRepro || !crashes[0].Reproducing {
+		t.Errorf("bad crashes: %+v", crashes)
+	}
+	crash := new(APICrash)
+	testAPI(t, mgr.apiCrash, "/api/v1/crash?id="+id, crash)
+	if crash.Description != title || crash.Repro != "getpid()\n" || len(crash.Logs) != 1 ||
+		crash.Logs[0].Log != "crash log" || crash.Logs[0].Report != "crash report" {
+		t.Errorf("bad crash: %+v", crash)
+	}
+	w := httptest.NewRecorder()
+	mgr.apiCrash(w, httptest.NewRequest("GET", "/api/v1/crash?id=foo", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown crash: got code %v", w.Code)
+	}
+}
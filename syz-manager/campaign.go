@@ -0,0 +1,455 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/syzkaller/pkg/hash"
+	. "github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/osutil"
+)
+
+// Campaign mode runs the manager for a fixed budget (campaign_* params in config),
+// then stops fuzzing, waits for in-progress repros, writes a self-contained report
+// to workdir/campaign/report.{json,html} and exits with one of campaignExit* statuses.
+
+const (
+	campaignCheckPeriod  = 10 * time.Second
+	campaignSamplePeriod = time.Minute
+	campaignDir          = "campaign"
+)
+
+// Exit statuses of campaign mode (1 means manager failure, see Fatalf).
+const (
+	campaignExitOK          = 0 // budget exhausted or stop condition met, no crashes found
+	campaignExitCrashes     = 2 // crashes found
+	campaignExitNoExecs     = 3 // no programs executed and no crashes found, likely broken image or config
+	campaignExitInterrupted = 4 // interrupted with SIGINT before the budget was exhausted, no crashes found
+)
+
+type campaignState struct {
+	start         time.Time
+	lastCover     int
+	lastCoverTime time.Time
+	lastSample    time.Time
+	samples       []CampaignSample
+	crashes       map[string]int // number of crashes per title found during the campaign
+	stopReason    string
+	stop          chan bool // closed when the campaign must stop
+}
+
+type CampaignReport struct {
+	Name        string
+	Tag         string
+	Start       time.Time
+	End         time.Time
+	StopReason  string
+	ExitCode    int
+	Execs       uint64
+	FuzzingTime int64 // in seconds, summed over all VMs
+	Corpus      int
+	Cover       int
+	Signal      int
+	Crashes     []*CampaignCrash
+	Growth      []CampaignSample
+	Stats       map[string]uint64
+	Calls       map[string]uint64 // number of executions per syscall
+	Config      map[string]interface{}
+}
+
+type CampaignCrash struct {
+	Title  string
+	ID     string
+	Count  int    // number of crashes during the campaign
+	Repro  string // "C repro", "repro", "no repro" or "not attempted"
+	Report string // the latest crash report
+	Prog   string // syzkaller reproducer
+	CProg  string // C reproducer
+}
+
+// CampaignSample is a point of coverage growth curve.
+type CampaignSample struct {
+	Time   int64 // seconds since start
+	Execs  uint64
+	Corpus int
+	Cover  int
+	Signal int
+}
+
+func newCampaignState(start time.Time) *campaignState {
+	return &campaignState{
+		start:         start,
+		lastCoverTime: start,
+		lastSample:    start,
+		crashes:       make(map[string]int),
+		stop:          make(chan bool),
+	}
+}
+
+func (mgr *Manager) campaignLoop() {
+	for {
+		time.Sleep(campaignCheckPeriod)
+		mgr.mu.Lock()
+		mgr.campaignCheck(time.Now())
+		stopped := mgr.campaign.stopReason != ""
+		mgr.mu.Unlock()
+		if stopped {
+			return
+		}
+	}
+}
+
+// campaignCheck records coverage growth and stops the campaign if the budget is exhausted.
+// Must be called with mgr.mu held.
+func (mgr *Manager) campaignCheck(now time.Time) {
+	c := mgr.campaign
+	if now.Sub(c.lastSample) >= campaignSamplePeriod {
+		c.lastSample = now
+		c.samples = append(c.samples, mgr.campaignSample(now))
+	}
+	if cover := len(mgr.corpusCover); cover > c.lastCover {
+		c.lastCover = cover
+		c.lastCoverTime = now
+	}
+	// VMs can take a while to boot, don't count that as time without new coverage.
+	lastCoverTime := c.lastCoverTime
+	if lastCoverTime.Before(mgr.firstConnect) {
+		lastCoverTime = mgr.firstConnect
+	}
+	cfg := mgr.cfg
+	switch {
+	case cfg.Campaign_Duration != 0 && now.Sub(c.start) >= time.Duration(cfg.Campaign_Duration)*time.Minute:
+		mgr.stopCampaign(fmt.Sprintf("duration budget of %v minutes exhausted", cfg.Campaign_Duration))
+	case cfg.Campaign_Execs != 0 && mgr.stats["exec total"] >= cfg.Campaign_Execs:
+		mgr.stopCampaign(fmt.Sprintf("exec budget of %v programs exhausted", cfg.Campaign_Execs))
+	case cfg.Campaign_Stop_No_Cover != 0 &&
+		now.Sub(lastCoverTime) >= time.Duration(cfg.Campaign_Stop_No_Cover)*time.Minute:
+		mgr.stopCampaign(fmt.Sprintf("no new coverage for %v minutes", cfg.Campaign_Stop_No_Cover))
+	}
+}
+
+// campaignCrash accounts a saved crash and stops the campaign if the crash matches campaign_stop_crashes.
+// Must be called with mgr.mu held.
+func (mgr *Manager) campaignCrash(desc string) {
+	if mgr.campaign == nil {
+		return
+	}
+	mgr.campaign.crashes[desc]++
+	for _, re := range mgr.cfg.ParsedStopCrashes {
+		if re.MatchString(desc) {
+			mgr.stopCampaign(fmt.Sprintf("found crash '%v'", desc))
+			return
+		}
+	}
+}
+
+// stopCampaign must be called with mgr.mu held.
+func (mgr *Manager) stopCampaign(reason string) {
+	c := mgr.campaign
+	if c.stopReason != "" {
+		return
+	}
+	Logf(0, "campaign: stopping: %v", reason)
+	c.stopReason = reason
+	close(c.stop)
+}
+
+// Must be called with mgr.mu held.
+func (mgr *Manager) campaignSample(now time.Time) CampaignSample {
+	return CampaignSample{
+		Time:   int64(now.Sub(mgr.campaign.start) / time.Second),
+		Execs:  mgr.stats["exec total"],
+		Corpus: len(mgr.corpus),
+		Cover:  len(mgr.corpusCover),
+		Signal: len(mgr.corpusSignal),
+	}
+}
+
+// finishCampaign writes the campaign report and returns the exit status.
+func (mgr *Manager) finishCampaign() int {
+	rep := mgr.campaignReport(time.Now())
+	dir := filepath.Join(mgr.cfg.Workdir, campaignDir)
+	if err := writeCampaignReport(dir, rep); err != nil {
+		Fatalf("failed to write campaign report: %v", err)
+	}
+	Logf(0, "campaign: %v, executed %v programs, %v crashes, report is in %v, exit status %v",
+		rep.StopReason, rep.Execs, len(rep.Crashes), dir, rep.ExitCode)
+	return rep.ExitCode
+}
+
+func (mgr *Manager) campaignReport(now time.Time) *CampaignReport {
+	mgr.mu.Lock()
+	c := mgr.campaign
+	rep := &CampaignReport{
+		Name:        mgr.cfg.Name,
+		Tag:         mgr.cfg.Tag,
+		Start:       c.start,
+		End:         now,
+		StopReason:  c.stopReason,
+		Execs:       mgr.stats["exec total"],
+		FuzzingTime: int64(mgr.fuzzingTime / time.Second),
+		Corpus:      len(mgr.corpus),
+		Cover:       len(mgr.corpusCover),
+		Signal:      len(mgr.corpusSignal),
+		Growth:      append(append([]CampaignSample{}, c.samples...), mgr.campaignSample(now)),
+		Stats:       make(map[string]uint64),
+		Calls:       make(map[string]uint64),
+		Config:      redactConfig(mgr.cfg),
+	}
+	for k, v := range mgr.stats {
+		rep.Stats[k] = v
+	}
+	for call, stats := range mgr.callStats {
+		rep.Calls[call] = stats.Execs
+	}
+	crashes := make(map[string]int)
+	for desc, count := range c.crashes {
+		crashes[desc] = count
+	}
+	mgr.mu.Unlock()
+
+	if rep.StopReason == "" {
+		rep.StopReason = "interrupted"
+	}
+	for desc, count := range crashes {
+		rep.Crashes = append(rep.Crashes, readCampaignCrash(mgr.cfg.Workdir, desc, count))
+	}
+	sort.Slice(rep.Crashes, func(i, j int) bool {
+		return rep.Crashes[i].Title < rep.Crashes[j].Title
+	})
+	// Crashes take precedence: a kernel that crashes during boot or on the first programs
+	// executes nothing, but it is still a crash to report.
+	switch {
+	case len(rep.Crashes) != 0:
+		rep.ExitCode = campaignExitCrashes
+	case rep.Execs == 0:
+		rep.ExitCode = campaignExitNoExecs
+	case rep.StopReason == "interrupted":
+		rep.ExitCode = campaignExitInterrupted
+	default:
+		rep.ExitCode = campaignExitOK
+	}
+	return rep
+}
+
+func readCampaignCrash(workdir, desc string, count int) *CampaignCrash {
+	id := hash.String([]byte(desc))
+	crash := &CampaignCrash{
+		Title: desc,
+		ID:    id,
+		Count: count,
+		Repro: "not attempted",
+	}
+	ui := readCrash(workdir, id, true)
+	if ui == nil {
+		return crash
+	}
+	switch {
+	case ui.HasCRepro:
+		crash.Repro = "C repro"
+	case ui.HasRepro:
+		crash.Repro = "repro"
+	case ui.ReproAttempts != 0:
+		crash.Repro = "no repro"
+	}
+	dir := filepath.Join(workdir, "crashes", id)
+	crash.Prog = readFileString(filepath.Join(dir, "repro.prog"))
+	crash.CProg = readFileString(filepath.Join(dir, "repro.cprog"))
+	crash.Report = readFileString(filepath.Join(dir, "repro.report"))
+	for _, c := range ui.Crashes {
+		if crash.Report == "" && c.Report != "" {
+			crash.Report = readFileString(filepath.Join(workdir, c.Report))
+		}
+	}
+	return crash
+}
+
+// redactConfig returns manager config as a map without keys.
+// VM-type-specific config is redacted as a whole, it can contain credentials
+// (e.g. service accounts or device addresses) under arbitrary names.
+func redactConfig(cfg interface{}) map[string]interface{} {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil
+	}
+	res := make(map[string]interface{})
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil
+	}
+	for k, v := range res {
+		if strings.HasSuffix(k, "_Key") && v != "" || k == "VM" && v != nil {
+			res[k] = "<redacted>"
+		}
+	}
+	return res
+}
+
+func writeCampaignReport(dir string, rep *CampaignReport) error {
+	if err := osutil.MkdirAll(dir); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(rep, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := osutil.WriteFile(filepath.Join(dir, "report.json"), data); err != nil {
+		return err
+	}
+	f, err := os.Create(filepath.Join(dir, "report.html"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	config, _ := json.MarshalIndent(rep.Config, "", "\t")
+	ui := &UICampaign{
+		CampaignReport: rep,
+		Duration:       rep.End.Sub(rep.Start) / time.Second * time.Second,
+		Fuzzing:        time.Duration(rep.FuzzingTime) * time.Second,
+		ConfigJSON:     string(config),
+		Chart:          campaignChart(rep.Growth),
+	}
+	for k, v := range rep.Stats {
+		ui.StatList = append(ui.StatList, UIStat{Name: k, Value: fmt.Sprint(v)})
+	}
+	sort.Sort(UIStatArray(ui.StatList))
+	for k, v := range rep.Calls {
+		ui.CallList = append(ui.CallList, UIStat{Name: k, Value: fmt.Sprint(v)})
+	}
+	sort.Sort(UIStatArray(ui.CallList))
+	if err := campaignTemplate.Execute(f, ui); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+type UICampaign struct {
+	*CampaignReport
+	Duration   time.Duration
+	Fuzzing    time.Duration
+	ConfigJSON string
+	Chart      *UIChart
+	StatList   []UIStat
+	CallList   []UIStat
+}
+
+// UIChart is an SVG line chart of coverage growth, so that the report does not need external scripts.
+type UIChart struct {
+	Width    int
+	Height   int
+	Points   string
+	MaxCover int
+	MaxTime  time.Duration
+}
+
+func campaignChart(samples []CampaignSample) *UIChart {
+	chart := &UIChart{Width: 800, Height: 300}
+	if len(samples) == 0 {
+		return chart
+	}
+	maxTime := samples[len(samples)-1].Time
+	for _, s := range samples {
+		if s.Cover > chart.MaxCover {
+			chart.MaxCover = s.Cover
+		}
+	}
+	chart.MaxTime = time.Duration(maxTime) * time.Second
+	var points []string
+	for _, s := range samples {
+		x, y := 0, chart.Height
+		if maxTime != 0 {
+			x = int(s.Time * int64(chart.Width) / maxTime)
+		}
+		if chart.MaxCover != 0 {
+			y = chart.Height - s.Cover*chart.Height/chart.MaxCover
+		}
+		points = append(points, fmt.Sprintf("%v,%v", x, y))
+	}
+	chart.Points = strings.Join(points, " ")
+	return chart
+}
+
+var campaignTemplate = template.Must(template.New("").Parse(addStyle(`
+<!doctype html>
+<html>
+<head>
+	<title>{{.Name}} syzkaller campaign report</title>
+	{{STYLE}}
+</head>
+<body>
+<b>{{.Name}} campaign: {{.StopReason}}, exit status {{.ExitCode}}</b>
+<br><br>
+
+<table>
+	<caption>Summary:</caption>
+	<tr><td>tag</td><td>{{.Tag}}</td></tr>
+	<tr><td>start</td><td>{{.Start.Format "Jan 02 2006 15:04:05 MST"}}</td></tr>
+	<tr><td>duration</td><td>{{.Duration}}</td></tr>
+	<tr><td>fuzzing</td><td>{{.Fuzzing}}</td></tr>
+	<tr><td>executed programs</td><td>{{.Execs}}</td></tr>
+	<tr><td>corpus</td><td>{{.Corpus}}</td></tr>
+	<tr><td>cover</td><td>{{.Cover}}</td></tr>
+	<tr><td>signal</td><td>{{.Signal}}</td></tr>
+	<tr><td>crashes</td><td>{{len .Crashes}}</td></tr>
+</table>
+<br>
+
+<b>Coverage growth (max {{.Chart.MaxCover}} PCs in {{.Chart.MaxTime}}):</b><br>
+<svg width="{{.Chart.Width}}" height="{{.Chart.Height}}" style="border:1px solid">
+	<polyline points="{{.Chart.Points}}" fill="none" stroke="blue" stroke-width="2"/>
+</svg>
+<br><br>
+
+<table>
+	<caption>Crashes:</caption>
+	<tr>
+		<th>Description</th>
+		<th>Count</th>
+		<th>Repro</th>
+	</tr>
+	{{range $c := .Crashes}}
+	<tr>
+		<td><a href="#{{$c.ID}}">{{$c.Title}}</a></td>
+		<td>{{$c.Count}}</td>
+		<td>{{$c.Repro}}</td>
+	</tr>
+	{{end}}
+</table>
+<br>
+
+{{range $c := .Crashes}}
+<b id="{{$c.ID}}">{{$c.Title}}</b>
+{{if $c.Report}}<pre>{{$c.Report}}</pre>{{end}}
+{{if $c.Prog}}<b>Reproducer:</b><pre>{{$c.Prog}}</pre>{{end}}
+{{if $c.CProg}}<b>C reproducer:</b><pre>{{$c.CProg}}</pre>{{end}}
+<br>
+{{end}}
+
+<table>
+	<caption>Stats:</caption>
+	{{range $s := .StatList}}
+	<tr><td>{{$s.Name}}</td><td>{{$s.Value}}</td></tr>
+	{{end}}
+</table>
+<br>
+
+<table>
+	<caption>Syscall executions:</caption>
+	{{range $s := .CallList}}
+	<tr><td>{{$s.Name}}</td><td>{{$s.Value}}</td></tr>
+	{{end}}
+</table>
+<br>
+
+<b>Config:</b>
+<pre>{{.ConfigJSON}}</pre>
+</body></html>
+`)))
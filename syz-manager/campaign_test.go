@@ -0,0 +1,216 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/pkg/hash"
+	"github.com/google/syzkaller/pkg/osutil"
+	. "github.com/google/syzkaller/pkg/rpctype"
+	"github.com/google/syzkaller/syz-manager/mgrconfig"
+)
+
+func testCampaignManager(cfg *mgrconfig.Config, start time.Time) *Manager {
+	return &Manager{
+		cfg:          cfg,
+		firstConnect: start,
+		stats:        make(map[string]uint64),
+		corpus:       make(map[string]RpcInput),
+		corpusSignal: make(map[uint32]struct{}),
+		corpusCover:  make(map[uint32]struct{}),
+		callStats:    make(map[string]*CallStats),
+		campaign:     newCampaignState(start),
+	}
+}
+
+func TestCampaignCheck(t *testing.T) {
+	start := time.Date(2018, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		cfg    *mgrconfig.Config
+		execs  uint64
+		cover  int // new coverage every minute for this number of minutes
+		now    time.Duration
+		reason string
+	}{
+		{
+			cfg: &mgrconfig.Config{Campaign_Duration: 60},
+			now: 59 * time.Minute,
+		},
+		{
+			cfg:    &mgrconfig.Config{Campaign_Duration: 60},
+			now:    60 * time.Minute,
+			reason: "duration budget of 60 minutes exhausted",
+		},
+		{
+			cfg:   &mgrconfig.Config{Campaign_Execs: 1000},
+			execs: 999,
+			now:   time.Minute,
+		},
+		{
+			cfg:    &mgrconfig.Config{Campaign_Execs: 1000},
+			execs:  1000,
+			now:    time.Minute,
+			reason: "exec budget of 1000 programs exhausted",
+		},
+		{
+			cfg:   &mgrconfig.Config{Campaign_Stop_No_Cover: 10},
+			cover: 20,
+			now:   29 * time.Minute,
+		},
+		{
+			cfg:    &mgrconfig.Config{Campaign_Stop_No_Cover: 10},
+			cover:  20,
+			now:    30 * time.Minute,
+			reason: "no new coverage for 10 minutes",
+		},
+	}
+	for i, test := range tests {
+		mgr := testCampaignManager(test.cfg, start)
+		mgr.stats["exec total"] = test.execs
+		for m := 1; m <= test.cover; m++ {
+			mgr.corpusCover[uint32(m)] = struct{}{}
+			mgr.campaignCheck(start.Add(time.Duration(m) * time.Minute))
+		}
+		mgr.campaignCheck(start.Add(test.now))
+		if got := mgr.campaign.stopReason; got != test.reason {
+			t.Errorf("#%v: got stop reason %q, want %q", i, got, test.reason)
+		}
+		select {
+		case <-mgr.campaign.stop:
+			if test.reason == "" {
+				t.Errorf("#%v: stop channel is closed", i)
+			}
+		default:
+			if test.reason != "" {
+				t.Errorf("#%v: stop channel is not closed", i)
+			}
+		}
+		if want := test.cover; len(mgr.campaign.samples) < want {
+			t.Errorf("#%v: got %v samples, want at least %v", i, len(mgr.campaign.samples), want)
+		}
+	}
+}
+
+func TestCampaignCrash(t *testing.T) {
+	start := time.Now()
+	mgr := testCampaignManager(&mgrconfig.Config{
+		ParsedStopCrashes: []*regexp.Regexp{regexp.MustCompile("^KASAN: ")},
+	}, start)
+	mgr.campaignCrash("WARNING in foo")
+	mgr.campaignCrash("WARNING in foo")
+	if mgr.campaign.stopReason != "" {
+		t.Fatalf("campaign stopped on a non-matching crash: %v", mgr.campaign.stopReason)
+	}
+	mgr.campaignCrash("KASAN: use-after-free Read in bar")
+	if want := "found crash 'KASAN: use-after-free Read in bar'"; mgr.campaign.stopReason != want {
+		t.Fatalf("got stop reason %q, want %q", mgr.campaign.stopReason, want)
+	}
+	if mgr.campaign.crashes["WARNING in foo"] != 2 || mgr.campaign.crashes["KASAN: use-after-free Read in bar"] != 1 {
+		t.Fatalf("bad crash counts: %+v", mgr.campaign.crashes)
+	}
+}
+
+func TestCampaignReport(t *testing.T) {
+	workdir, err := ioutil.TempDir("", "syz-manager-campaign")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(workdir)
+	start := time.Now().Add(-time.Hour)
+	cfg := &mgrconfig.Config{
+		Name:              "ci",
+		Workdir:           workdir,
+		Dashboard_Key:     "secret",
+		VM:                json.RawMessage(`{"token": "vmsecret"}`),
+		Campaign_Duration: 60,
+	}
+	mgr := testCampaignManager(cfg, start)
+
+	rep := mgr.campaignReport(time.Now())
+	if rep.ExitCode != campaignExitNoExecs || rep.StopReason != "interrupted" {
+		t.Fatalf("got exit code %v, stop reason %q", rep.ExitCode, rep.StopReason)
+	}
+
+	// Crashes are reported even if no programs were executed.
+	mgr.campaignCrash("no output from test machine")
+	rep = mgr.campaignReport(time.Now())
+	if rep.ExitCode != campaignExitCrashes {
+		t.Fatalf("got exit code %v, want %v", rep.ExitCode, campaignExitCrashes)
+	}
+	delete(mgr.campaign.crashes, "no output from test machine")
+
+	mgr.stats["exec total"] = 100
+	rep = mgr.campaignReport(time.Now())
+	if rep.ExitCode != campaignExitInterrupted {
+		t.Fatalf("got exit code %v, want %v", rep.ExitCode, campaignExitInterrupted)
+	}
+
+	mgr.campaignCheck(time.Now())
+	rep = mgr.campaignReport(time.Now())
+	if rep.ExitCode != campaignExitOK {
+		t.Fatalf("got exit code %v, want %v", rep.ExitCode, campaignExitOK)
+	}
+
+	const title = "KASAN: use-after-free Read in bar"
+	crashdir := filepath.Join(workdir, "crashes", hash.String([]byte(title)))
+	osutil.MkdirAll(crashdir)
+	osutil.WriteFile(filepath.Join(crashdir, "description"), []byte(title+"\n"))
+	osutil.WriteFile(filepath.Join(crashdir, "log0"), []byte("log"))
+	osutil.WriteFile(filepath.Join(crashdir, "report0"), []byte("crash report"))
+	osutil.WriteFile(filepath.Join(crashdir, "repro.prog"), []byte("bar()"))
+	mgr.campaignCrash(title)
+	mgr.campaignCrash("WARNING in foo")
+	rep = mgr.campaignReport(time.Now())
+	if rep.ExitCode != campaignExitCrashes || len(rep.Crashes) != 2 {
+		t.Fatalf("got exit code %v, %v crashes", rep.ExitCode, len(rep.Crashes))
+	}
+	if c := rep.Crashes[0]; c.Title != title || c.Repro != "repro" || c.Prog != "bar()" || c.Report != "crash report" {
+		t.Fatalf("bad crash: %+v", c)
+	}
+	if c := rep.Crashes[1]; c.Title != "WARNING in foo" || c.Repro != "not attempted" {
+		t.Fatalf("bad crash: %+v", c)
+	}
+	if key := rep.Config["Dashboard_Key"]; key != "<redacted>" {
+		t.Fatalf("dashboard key is not redacted: %v", key)
+	}
+	if vm := rep.Config["VM"]; vm != "<redacted>" {
+		t.Fatalf("vm config is not redacted: %v", vm)
+	}
+
+	dir := filepath.Join(workdir, campaignDir)
+	if err := writeCampaignReport(dir, rep); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, "report.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep1 := new(CampaignReport)
+	if err := json.Unmarshal(data, rep1); err != nil {
+		t.Fatal(err)
+	}
+	if rep1.ExitCode != rep.ExitCode || len(rep1.Crashes) != len(rep.Crashes) || len(rep1.Growth) != len(rep.Growth) {
+		t.Fatalf("bad report.json:\n%s", data)
+	}
+	html, err := ioutil.ReadFile(filepath.Join(dir, "report.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"use-after-free Read in bar", "<polyline", "bar()"} {
+		if !strings.Contains(string(html), want) {
+			t.Errorf("report.html does not contain %q", want)
+		}
+	}
+	if strings.Contains(string(html), "secret") {
+		t.Errorf("report.html contains dashboard key or vm config")
+	}
+}
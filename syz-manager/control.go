@@ -0,0 +1,266 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	. "github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/syz-manager/mgrconfig"
+)
+
+// Overrides are runtime changes of the manager configuration made with control requests
+// (see syz-manager ctl). They are persisted in workdir/overrides.json and applied on start.
+type Overrides struct {
+	Paused        bool
+	DisabledCalls []string // syscall names, see mgrconfig.MatchSyscall
+	Suppressions  []string // additional regexps for crashes that are not reported
+	Ignores       []string // additional regexps for kernel output that is not considered a crash
+}
+
+// Control commands, the argument is a syscall name for disable/enable
+// and a regexp for suppress/unsuppress/ignore/unignore.
+var controlCommands = map[string]func(ov *Overrides, arg string){
+	"pause":      func(ov *Overrides, arg string) { ov.Paused = true },
+	"resume":     func(ov *Overrides, arg string) { ov.Paused = false },
+	"disable":    func(ov *Overrides, arg string) { ov.DisabledCalls = addString(ov.DisabledCalls, arg) },
+	"enable":     func(ov *Overrides, arg string) { ov.DisabledCalls = removeString(ov.DisabledCalls, arg) },
+	"suppress":   func(ov *Overrides, arg string) { ov.Suppressions = addString(ov.Suppressions, arg) },
+	"unsuppress": func(ov *Overrides, arg string) { ov.Suppressions = removeString(ov.Suppressions, arg) },
+	"ignore":     func(ov *Overrides, arg string) { ov.Ignores = addString(ov.Ignores, arg) },
+	"unignore":   func(ov *Overrides, arg string) { ov.Ignores = removeString(ov.Ignores, arg) },
+}
+
+func controlCommandNeedsArg(cmd string) bool {
+	return cmd != "pause" && cmd != "resume"
+}
+
+func (mgr *Manager) overridesFile() string {
+	return filepath.Join(mgr.cfg.Workdir, "overrides.json")
+}
+
+func (mgr *Manager) loadOverrides() {
+	data, err := ioutil.ReadFile(mgr.overridesFile())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			Logf(0, "failed to read overrides: %v", err)
+		}
+		return
+	}
+	ov := Overrides{}
+	if err := json.Unmarshal(data, &ov); err != nil {
+		Logf(0, "failed to parse overrides: %v", err)
+		return
+	}
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	if err := mgr.applyOverrides(ov); err != nil {
+		Logf(0, "failed to apply overrides: %v", err)
+		return
+	}
+	Logf(0, "applied overrides: %+v", ov)
+}
+
+func (mgr *Manager) saveOverrides(ov Overrides) error {
+	data, err := json.MarshalIndent(ov, "", "\t")
+	if err != nil {
+		return err
+	}
+	return osutil.WriteFile(mgr.overridesFile(), data)
+}
+
+// applyOverrides validates and applies ov, must be called with mgr.mu held.
+func (mgr *Manager) applyOverrides(ov Overrides) error {
+	syscalls := make(map[int]bool)
+	for id := range mgr.baseSyscalls {
+		syscalls[id] = true
+	}
+	for _, name := range ov.DisabledCalls {
+		n := 0
+		for _, call := range mgr.target.Syscalls {
+			if mgrconfig.MatchSyscall(call, name) {
+				delete(syscalls, call.ID)
+				n++
+			}
+		}
+		if n == 0 {
+			return fmt.Errorf("unknown syscall: %v", name)
+		}
+	}
+	// mmap is used to allocate memory.
+	syscalls[mgr.target.MmapSyscall.ID] = true
+	if len(syscalls) == 1 {
+		return fmt.Errorf("all syscalls are disabled")
+	}
+	suppressions, err := compileRegexps(ov.Suppressions)
+	if err != nil {
+		return err
+	}
+	ignores, err := compileRegexps(ov.Ignores)
+	if err != nil {
+		return err
+	}
+	if enabled := serializeSyscalls(syscalls); enabled != mgr.enabledSyscalls {
+		mgr.enabledSyscalls = enabled
+		mgr.callsVersion++
+	}
+	mgr.suppressions = suppressions
+	mgr.ignores = ignores
+	mgr.overrides = ov
+	return nil
+}
+
+func compileRegexps(list []string) ([]*regexp.Regexp, error) {
+	var res []*regexp.Regexp
+	for _, str := range list {
+		re, err := regexp.Compile(str)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile %q: %v", str, err)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+func serializeSyscalls(syscalls map[int]bool) string {
+	var ids []int
+	for id := range syscalls {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	buf := new(bytes.Buffer)
+	for i, id := range ids {
+		if i != 0 {
+			buf.WriteByte(',')
+		}
+		fmt.Fprintf(buf, "%v", id)
+	}
+	return buf.String()
+}
+
+// getIgnores returns regexps for kernel output that is not considered a crash.
+func (mgr *Manager) getIgnores() []*regexp.Regexp {
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	return append(append([]*regexp.Regexp{}, mgr.cfg.ParsedIgnores...), mgr.ignores...)
+}
+
+// GET /api/v1/control returns current overrides.
+// POST /api/v1/control?cmd=CMD[&arg=ARG] executes a control command (see controlCommands).
+func (mgr *Manager) apiControl(w http.ResponseWriter, r *http.Request) {
+	if r.Method == "GET" {
+		mgr.mu.Lock()
+		ov := mgr.overrides
+		mgr.mu.Unlock()
+		writeJSON(w, ov)
+		return
+	}
+	if !mgr.checkRequest(w, r, "POST") {
+		return
+	}
+	cmd, arg := r.URL.Query().Get("cmd"), r.URL.Query().Get("arg")
+	f := controlCommands[cmd]
+	if f == nil {
+		http.Error(w, fmt.Sprintf("unknown command %q", cmd), http.StatusBadRequest)
+		return
+	}
+	if controlCommandNeedsArg(cmd) && arg == "" {
+		http.Error(w, fmt.Sprintf("command %v requires an argument", cmd), http.StatusBadRequest)
+		return
+	}
+	mgr.mu.Lock()
+	ov := mgr.overrides
+	ov.DisabledCalls = append([]string{}, ov.DisabledCalls...)
+	ov.Suppressions = append([]string{}, ov.Suppressions...)
+	ov.Ignores = append([]string{}, ov.Ignores...)
+	f(&ov, arg)
+	if err := mgr.applyOverrides(ov); err != nil {
+		mgr.mu.Unlock()
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	mgr.mu.Unlock()
+	Logf(0, "control: %v %v (%v)", cmd, arg, r.RemoteAddr)
+	if err := mgr.saveOverrides(ov); err != nil {
+		Logf(0, "failed to save overrides: %v", err)
+	}
+	writeJSON(w, ov)
+}
+
+func addString(list []string, str string) []string {
+	for _, s := range list {
+		if s == str {
+			return list
+		}
+	}
+	return append(list, str)
+}
+
+func removeString(list []string, str string) []string {
+	var res []string
+	for _, s := range list {
+		if s != str {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+// runCtl implements "syz-manager -config=manager.cfg ctl CMD [ARG]":
+// sends the control command to the running manager.
+func runCtl(cfg *mgrconfig.Config, args []string) {
+	if len(args) == 0 {
+		Fatalf("usage: syz-manager -config=manager.cfg ctl status|pause|resume|" +
+			"disable|enable SYSCALL|suppress|unsuppress|ignore|unignore REGEXP")
+	}
+	addr := cfg.Http
+	if strings.HasPrefix(addr, ":") {
+		addr = "localhost" + addr
+	}
+	cmd := args[0]
+	var resp *http.Response
+	var err error
+	if cmd == "status" {
+		resp, err = http.Get(fmt.Sprintf("http://%v/api/v1/control", addr))
+	} else {
+		if controlCommands[cmd] == nil {
+			Fatalf("unknown command %q", cmd)
+		}
+		params := url.Values{}
+		params.Set("cmd", cmd)
+		if controlCommandNeedsArg(cmd) {
+			if len(args) != 2 {
+				Fatalf("command %v requires one argument", cmd)
+			}
+			params.Set("arg", args[1])
+		}
+		var req *http.Request
+		req, err = http.NewRequest("POST", fmt.Sprintf("http://%v/api/v1/control?%v", addr, params.Encode()), nil)
+		if err != nil {
+			Fatalf("%v", err)
+		}
+		req.Header.Set(httpKeyHeader, cfg.Http_Key)
+		resp, err = http.DefaultClient.Do(req)
+	}
+	if err != nil {
+		Fatalf("%v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		Fatalf("%v: %s", resp.Status, body)
+	}
+	fmt.Printf("%s", body)
+}
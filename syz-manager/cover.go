@@ -4,61 +4,25 @@
 package main
 
 import (
-	"bufio"
 	"bytes"
 	"fmt"
 	"html/template"
 	"io"
 	"io/ioutil"
-	"os/exec"
 	"sort"
-	"strconv"
 	"strings"
 
 	"github.com/google/syzkaller/pkg/cover"
+	"github.com/google/syzkaller/pkg/coverreport"
 	. "github.com/google/syzkaller/pkg/log"
 	"github.com/google/syzkaller/pkg/symbolizer"
 )
 
-type symbol struct {
-	start uint64
-	end   uint64
-	name  string
-}
-
-type symbolArray []symbol
-
-func (a symbolArray) Len() int           { return len(a) }
-func (a symbolArray) Less(i, j int) bool { return a[i].start < a[j].start }
-func (a symbolArray) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
-
-type coverage struct {
-	line    int
-	covered bool
-}
-
-type coverageArray []coverage
-
-func (a coverageArray) Len() int           { return len(a) }
-func (a coverageArray) Less(i, j int) bool { return a[i].line < a[j].line }
-func (a coverageArray) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
-
-type uint64Array []uint64
-
-func (a uint64Array) Len() int           { return len(a) }
-func (a uint64Array) Less(i, j int) bool { return a[i] < a[j] }
-func (a uint64Array) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
-
 var (
 	allCoverPCs     []uint64
 	allCoverReady   = make(chan bool)
 	allSymbols      map[string][]symbolizer.Symbol
 	allSymbolsReady = make(chan bool)
-	vmOffsets       = make(map[string]uint32)
-)
-
-const (
-	callLen = 5 // length of a call instruction, x86-ism
 )
 
 func initAllCover(vmlinux string) {
@@ -73,9 +37,8 @@ func initAllCover(vmlinux string) {
 		if vmlinux == "" {
 			return
 		}
-		pcs, err := coveredPCs(vmlinux)
+		pcs, err := coverreport.CoveredPCs(vmlinux)
 		if err == nil {
-			sort.Sort(uint64Array(pcs))
 			allCoverPCs = pcs
 		} else {
 			Logf(0, "failed to run objdump on %v: %v", vmlinux, err)
@@ -88,48 +51,44 @@ func initAllCover(vmlinux string) {
 	}()
 }
 
-func generateCoverHtml(w io.Writer, vmlinux string, cov []uint32) error {
+// coverProfile builds coverage profile for coverage reported by fuzzers.
+func coverProfile(vmlinux string, cov []uint32) (*coverreport.Profile, error) {
 	if len(cov) == 0 {
-		return fmt.Errorf("No coverage data available")
+		return nil, fmt.Errorf("No coverage data available")
 	}
-
-	base, err := getVmOffset(vmlinux)
+	base, err := coverreport.VMOffset(vmlinux)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	pcs := make([]uint64, len(cov))
 	for i, pc := range cov {
-		pcs[i] = cover.RestorePC(pc, base) - callLen
+		pcs[i] = cover.RestorePC(pc, base) - coverreport.CallLen
 	}
-	uncovered, err := uncoveredPcsInFuncs(vmlinux, pcs)
+	symbols, err := sortedSymbols()
 	if err != nil {
-		return err
-	}
-
-	coveredFrames, prefix, err := symbolize(vmlinux, pcs)
-	if err != nil {
-		return err
-	}
-	if len(coveredFrames) == 0 {
-		return fmt.Errorf("'%s' does not have debug info (set CONFIG_DEBUG_INFO=y)", vmlinux)
+		return nil, err
 	}
+	<-allCoverReady
+	return coverreport.MakeProfile(vmlinux, symbols, allCoverPCs, pcs)
+}
 
-	uncoveredFrames, prefix, err := symbolize(vmlinux, uncovered)
+func generateCoverHtml(w io.Writer, vmlinux string, cov []uint32) error {
+	profile, err := coverProfile(vmlinux, cov)
 	if err != nil {
 		return err
 	}
-
 	var d templateData
-	for f, covered := range fileSet(coveredFrames, uncoveredFrames) {
-		lines, err := parseFile(f)
+	for _, f := range profile.Files {
+		lines, err := parseFile(f.Path)
 		if err != nil {
 			return err
 		}
+		covered := f.Lines
 		coverage := 0
 		var buf bytes.Buffer
 		for i, ln := range lines {
-			if len(covered) > 0 && covered[0].line == i+1 {
-				if covered[0].covered {
+			if len(covered) > 0 && covered[0].Line == i+1 {
+				if covered[0].Covered {
 					buf.Write([]byte("<span id='covered'>"))
 					buf.Write(ln)
 					buf.Write([]byte("</span> /*covered*/\n"))
@@ -145,11 +104,8 @@ func generateCoverHtml(w io.Writer, vmlinux string, cov []uint32) error {
 				buf.Write([]byte{'\n'})
 			}
 		}
-		if len(f) > len(prefix) {
-			f = f[len(prefix):]
-		}
 		d.Files = append(d.Files, &templateFile{
-			Name:     f,
+			Name:     f.Name,
 			Body:     template.HTML(buf.String()),
 			Coverage: coverage,
 		})
@@ -162,37 +118,73 @@ func generateCoverHtml(w io.Writer, vmlinux string, cov []uint32) error {
 	return nil
 }
 
-func fileSet(covered, uncovered []symbolizer.Frame) map[string][]coverage {
-	files := make(map[string]map[int]bool)
-	funcs := make(map[string]bool)
-	for _, frame := range covered {
-		if files[frame.File] == nil {
-			files[frame.File] = make(map[int]bool)
+// generateCoverDiffHtml renders coverage diff: newly covered lines are green, no longer covered are red.
+// The first page lists functions with changed coverage.
+func generateCoverDiffHtml(w io.Writer, diff *coverreport.Diff) error {
+	var d templateData
+	funcs := new(bytes.Buffer)
+	for _, fn := range diff.Funcs {
+		id := "changed"
+		if fn.Old == 0 {
+			id = "new"
+		} else if fn.New == 0 {
+			id = "lost"
 		}
-		files[frame.File][frame.Line] = true
-		funcs[frame.Func] = true
+		fmt.Fprintf(funcs, "<span id='%v'>%v</span> %v:%v %v/%v -> %v/%v\n", id,
+			template.HTMLEscapeString(fn.Name), template.HTMLEscapeString(fn.File), fn.Line,
+			fn.Old, fn.Total, fn.New, fn.Total)
 	}
-	for _, frame := range uncovered {
-		if !funcs[frame.Func] {
+	d.Files = append(d.Files, &templateFile{
+		Name: "changed functions",
+		Body: template.HTML(funcs.String()),
+		Diff: fmt.Sprint(len(diff.Funcs)),
+	})
+	var files []*templateFile
+	for _, f := range diff.Files {
+		if f.Added == 0 && f.Removed == 0 {
 			continue
 		}
-		if files[frame.File] == nil {
-			files[frame.File] = make(map[int]bool)
-		}
-		if !files[frame.File][frame.Line] {
-			files[frame.File][frame.Line] = false
-		}
-	}
-	res := make(map[string][]coverage)
-	for f, lines := range files {
-		sorted := make([]coverage, 0, len(lines))
-		for ln, covered := range lines {
-			sorted = append(sorted, coverage{ln, covered})
+		// Old snapshots can refer to sources that no longer exist,
+		// then only line numbers are shown.
+		lines, _ := parseFile(f.Path)
+		var buf bytes.Buffer
+		changed := f.Lines
+		for i := 0; i < len(lines) || len(changed) > 0; i++ {
+			var ln []byte
+			if i < len(lines) {
+				ln = lines[i]
+			}
+			if len(changed) == 0 || changed[0].Line != i+1 {
+				if ln != nil {
+					buf.Write(ln)
+					buf.WriteByte('\n')
+				}
+				continue
+			}
+			if ln == nil {
+				ln = []byte(fmt.Sprintf("line %v", i+1))
+			}
+			switch c := changed[0]; {
+			case !c.Old && c.New:
+				fmt.Fprintf(&buf, "<span id='new'>%s</span> /*new*/\n", ln)
+			case c.Old && !c.New:
+				fmt.Fprintf(&buf, "<span id='lost'>%s</span> /*lost*/\n", ln)
+			case c.New:
+				fmt.Fprintf(&buf, "<span id='covered'>%s</span> /*covered*/\n", ln)
+			default:
+				fmt.Fprintf(&buf, "%s\n", ln)
+			}
+			changed = changed[1:]
 		}
-		sort.Sort(coverageArray(sorted))
-		res[f] = sorted
+		files = append(files, &templateFile{
+			Name: f.Name,
+			Body: template.HTML(buf.String()),
+			Diff: fmt.Sprintf("+%v -%v", f.Added, f.Removed),
+		})
 	}
-	return res
+	sort.Sort(templateFileArray(files))
+	d.Files = append(d.Files, files...)
+	return coverTemplate.Execute(w, d)
 }
 
 func parseFile(fn string) ([][]byte, error) {
@@ -216,163 +208,13 @@ func parseFile(fn string) ([][]byte, error) {
 	return lines, nil
 }
 
-func getVmOffset(vmlinux string) (uint32, error) {
-	if v, ok := vmOffsets[vmlinux]; ok {
-		return v, nil
-	}
-	out, err := exec.Command("readelf", "-SW", vmlinux).CombinedOutput()
-	if err != nil {
-		return 0, fmt.Errorf("readelf failed: %v\n%s", err, out)
-	}
-	s := bufio.NewScanner(bytes.NewReader(out))
-	var addr uint32
-	for s.Scan() {
-		ln := s.Text()
-		pieces := strings.Fields(ln)
-		for i := 0; i < len(pieces); i++ {
-			if pieces[i] != "PROGBITS" {
-				continue
-			}
-			v, err := strconv.ParseUint("0x"+pieces[i+1], 0, 64)
-			if err != nil {
-				return 0, fmt.Errorf("failed to parse addr in readelf output: %v", err)
-			}
-			if v == 0 {
-				continue
-			}
-			v32 := (uint32)(v >> 32)
-			if addr == 0 {
-				addr = v32
-			}
-			if addr != v32 {
-				return 0, fmt.Errorf("different section offsets in a single binary")
-			}
-		}
-	}
-	vmOffsets[vmlinux] = addr
-	return addr, nil
-}
-
-// uncoveredPcsInFuncs returns uncovered PCs with __sanitizer_cov_trace_pc calls in functions containing pcs.
-func uncoveredPcsInFuncs(vmlinux string, pcs []uint64) ([]uint64, error) {
+// sortedSymbols returns text symbols of vmlinux sorted by address.
+func sortedSymbols() ([]coverreport.Symbol, error) {
 	<-allSymbolsReady
 	if allSymbols == nil {
 		return nil, fmt.Errorf("failed to run nm on vmlinux")
 	}
-	var symbols symbolArray
-	for name, ss := range allSymbols {
-		for _, s := range ss {
-			symbols = append(symbols, symbol{s.Addr, s.Addr + uint64(s.Size), name})
-		}
-	}
-	sort.Sort(symbols)
-
-	<-allCoverReady
-	if len(allCoverPCs) == 0 {
-		return nil, nil
-	}
-
-	handledFuncs := make(map[uint64]bool)
-	uncovered := make(map[uint64]bool)
-	for _, pc := range pcs {
-		idx := sort.Search(len(symbols), func(i int) bool {
-			return pc < symbols[i].end
-		})
-		if idx == len(symbols) {
-			continue
-		}
-		s := symbols[idx]
-		if pc < s.start || pc > s.end {
-			continue
-		}
-		if !handledFuncs[s.start] {
-			handledFuncs[s.start] = true
-			startPC := sort.Search(len(allCoverPCs), func(i int) bool {
-				return s.start <= allCoverPCs[i]
-			})
-			endPC := sort.Search(len(allCoverPCs), func(i int) bool {
-				return s.end < allCoverPCs[i]
-			})
-			for _, pc1 := range allCoverPCs[startPC:endPC] {
-				uncovered[pc1] = true
-			}
-		}
-		delete(uncovered, pc)
-	}
-	uncoveredPCs := make([]uint64, 0, len(uncovered))
-	for pc := range uncovered {
-		uncoveredPCs = append(uncoveredPCs, pc)
-	}
-	return uncoveredPCs, nil
-}
-
-// coveredPCs returns list of PCs of __sanitizer_cov_trace_pc calls in binary bin.
-func coveredPCs(bin string) ([]uint64, error) {
-	cmd := exec.Command("objdump", "-d", "--no-show-raw-insn", bin)
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		return nil, err
-	}
-	defer stdout.Close()
-	if err := cmd.Start(); err != nil {
-		return nil, err
-	}
-	defer cmd.Wait()
-	var pcs []uint64
-	s := bufio.NewScanner(stdout)
-	// A line looks as: "ffffffff8100206a:       callq  ffffffff815cc1d0 <__sanitizer_cov_trace_pc>"
-	callInsn := []byte("callq ")
-	traceFunc := []byte(" <__sanitizer_cov_trace_pc>")
-	for s.Scan() {
-		ln := s.Bytes()
-		if pos := bytes.Index(ln, callInsn); pos == -1 {
-			continue
-		} else if bytes.Index(ln[pos:], traceFunc) == -1 {
-			continue
-		}
-		colon := bytes.IndexByte(ln, ':')
-		if colon == -1 {
-			continue
-		}
-		pc, err := strconv.ParseUint(string(ln[:colon]), 16, 64)
-		if err != nil {
-			continue
-		}
-		pcs = append(pcs, pc)
-	}
-	if err := s.Err(); err != nil {
-		return nil, err
-	}
-	return pcs, nil
-}
-
-func symbolize(vmlinux string, pcs []uint64) ([]symbolizer.Frame, string, error) {
-	symb := symbolizer.NewSymbolizer()
-	defer symb.Close()
-
-	frames, err := symb.SymbolizeArray(vmlinux, pcs)
-	if err != nil {
-		return nil, "", err
-	}
-
-	prefix := ""
-	for i := range frames {
-		frame := &frames[i]
-		frame.PC--
-		if prefix == "" {
-			prefix = frame.File
-		} else {
-			i := 0
-			for ; i < len(prefix) && i < len(frame.File); i++ {
-				if prefix[i] != frame.File[i] {
-					break
-				}
-			}
-			prefix = prefix[:i]
-		}
-
-	}
-	return frames, prefix, nil
+	return coverreport.SortedSymbols(allSymbols), nil
 }
 
 type templateData struct {
@@ -383,6 +225,7 @@ type templateFile struct {
 	Name     string
 	Body     template.HTML
 	Coverage int
+	Diff     string // added/removed lines for coverage diffs
 }
 
 type templateFileArray []*templateFile
@@ -439,6 +282,19 @@ var coverTemplate = template.Must(template.New("").Parse(
 				color: rgb(255, 0, 0);
 				font-weight: bold;
 			}
+			#new {
+				color: rgb(0, 160, 0);
+				font-weight: bold;
+			}
+			#lost {
+				color: rgb(255, 0, 0);
+				font-weight: bold;
+				text-decoration: line-through;
+			}
+			#changed {
+				color: rgb(200, 120, 0);
+				font-weight: bold;
+			}
 		</style>
 	</head>
 	<body>
@@ -446,7 +302,7 @@ var coverTemplate = template.Must(template.New("").Parse(
 			<div id="nav">
 				<select id="files">
 				{{range $i, $f := .Files}}
-				<option value="file{{$i}}">{{$f.Name}} ({{$f.Coverage}})</option>
+				<option value="file{{$i}}">{{$f.Name}} ({{if $f.Diff}}{{$f.Diff}}{{else}}{{$f.Coverage}}{{end}})</option>
 				{{end}}
 				</select>
 			</div>
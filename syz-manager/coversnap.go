@@ -0,0 +1,223 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/syzkaller/pkg/coverreport"
+	"github.com/google/syzkaller/pkg/hash"
+	. "github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/osutil"
+)
+
+// Coverage snapshots are saved to workdir/coversnap every cover_snapshot_period minutes
+// (if corpus coverage has changed since the last snapshot) and can be compared
+// with each other and with the current coverage on /cover?diff=A..B page.
+
+const (
+	coverSnapshotDir    = "coversnap"
+	coverSnapshotFormat = "20060102-150405"
+	coverSnapshotExt    = ".json.gz"
+	coverSnapshotNow    = "current"
+)
+
+type UICoverSnapshot struct {
+	Name string
+	Prev string
+	Time string
+	Tag  string
+	PCs  int
+}
+
+func (mgr *Manager) coverSnapshotLoop() {
+	period := time.Duration(mgr.cfg.Cover_Snapshot_Period) * time.Minute
+	// Coverage can change without changing its size (e.g. after corpus deletion), so we compare hashes.
+	lastHash := ""
+	if names, _ := coverSnapshotNames(mgr.cfg.Workdir); len(names) != 0 {
+		file := filepath.Join(mgr.cfg.Workdir, coverSnapshotDir, names[len(names)-1]+coverSnapshotExt)
+		if s, err := coverreport.ReadSnapshotInfo(file); err == nil {
+			lastHash = s.PCsHash
+		}
+	}
+	for {
+		time.Sleep(period)
+		cov := mgr.corpusCoverPCs()
+		if len(cov) == 0 {
+			continue
+		}
+		covHash := coverHash(cov)
+		if covHash == lastHash {
+			continue
+		}
+		if err := mgr.saveCoverSnapshot(cov, covHash); err != nil {
+			Logf(0, "failed to save coverage snapshot: %v", err)
+			continue
+		}
+		lastHash = covHash
+	}
+}
+
+// coverHash returns hash of the set of PCs, it sorts cov.
+func coverHash(cov []uint32) string {
+	sort.Slice(cov, func(i, j int) bool { return cov[i] < cov[j] })
+	data := make([]byte, 4*len(cov))
+	for i, pc := range cov {
+		binary.LittleEndian.PutUint32(data[4*i:], pc)
+	}
+	return hash.String(data)
+}
+
+func (mgr *Manager) corpusCoverPCs() []uint32 {
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	cov := make([]uint32, 0, len(mgr.corpusCover))
+	for pc := range mgr.corpusCover {
+		cov = append(cov, pc)
+	}
+	return cov
+}
+
+func (mgr *Manager) saveCoverSnapshot(cov []uint32, covHash string) error {
+	profile, err := coverProfile(mgr.cfg.Vmlinux, cov)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	dir := filepath.Join(mgr.cfg.Workdir, coverSnapshotDir)
+	osutil.MkdirAll(dir)
+	snapshot := &coverreport.Snapshot{
+		Time:    now,
+		Tag:     mgr.cfg.Tag,
+		PCs:     len(cov),
+		PCsHash: covHash,
+		Profile: profile,
+	}
+	name := now.Format(coverSnapshotFormat)
+	if err := coverreport.WriteSnapshot(filepath.Join(dir, name+coverSnapshotExt), snapshot); err != nil {
+		return err
+	}
+	Logf(0, "saved coverage snapshot %v (%v PCs)", name, len(cov))
+	if max := mgr.cfg.Cover_Snapshot_Max; max != 0 {
+		names, err := coverSnapshotNames(mgr.cfg.Workdir)
+		if err != nil {
+			return err
+		}
+		for len(names) > max {
+			os.Remove(filepath.Join(dir, names[0]+coverSnapshotExt))
+			names = names[1:]
+		}
+	}
+	mgr.mu.Lock()
+	mgr.stats["cover snapshots"]++
+	mgr.mu.Unlock()
+	return nil
+}
+
+// coverSnapshotNames returns names of saved snapshots from the oldest to the newest.
+func coverSnapshotNames(workdir string) ([]string, error) {
+	dir := filepath.Join(workdir, coverSnapshotDir)
+	if !osutil.IsExist(dir) {
+		return nil, nil
+	}
+	files, err := osutil.ListDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, f := range files {
+		if strings.HasSuffix(f, coverSnapshotExt) {
+			names = append(names, strings.TrimSuffix(f, coverSnapshotExt))
+		}
+	}
+	// Names are timestamps, so they sort chronologically.
+	sort.Strings(names)
+	return names, nil
+}
+
+func listCoverSnapshots(workdir string) ([]*UICoverSnapshot, error) {
+	names, err := coverSnapshotNames(workdir)
+	if err != nil {
+		return nil, err
+	}
+	var snapshots []*UICoverSnapshot
+	for i, name := range names {
+		s, err := coverreport.ReadSnapshotInfo(filepath.Join(workdir, coverSnapshotDir, name+coverSnapshotExt))
+		if err != nil {
+			Logf(0, "%v", err)
+			continue
+		}
+		ui := &UICoverSnapshot{
+			Name: name,
+			Time: s.Time.Format(dateFormat),
+			Tag:  s.Tag,
+			PCs:  s.PCs,
+		}
+		if i != 0 {
+			ui.Prev = names[i-1]
+		}
+		snapshots = append(snapshots, ui)
+	}
+	return snapshots, nil
+}
+
+func readCoverSnapshot(workdir, name string) (*coverreport.Snapshot, error) {
+	if name == "" || strings.ContainsAny(name, "/.") {
+		return nil, fmt.Errorf("bad snapshot name %q", name)
+	}
+	return coverreport.ReadSnapshot(filepath.Join(workdir, coverSnapshotDir, name+coverSnapshotExt))
+}
+
+// coverDiff compares coverage snapshots specified as "A..B".
+// Either snapshot can be "current" which means current corpus coverage (default for B).
+// mgr.mu is held only to copy the current coverage.
+func (mgr *Manager) coverDiff(spec string) (*coverreport.Diff, error) {
+	parts := strings.Split(spec, "..")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("bad diff %q, want snapshotA..snapshotB", spec)
+	}
+	if parts[1] == "" {
+		parts[1] = coverSnapshotNow
+	}
+	var profiles [2]*coverreport.Profile
+	for i, name := range parts {
+		if name != coverSnapshotNow {
+			s, err := readCoverSnapshot(mgr.cfg.Workdir, name)
+			if err != nil {
+				return nil, err
+			}
+			profiles[i] = s.Profile
+			continue
+		}
+		profile, err := coverProfile(mgr.cfg.Vmlinux, mgr.corpusCoverPCs())
+		if err != nil {
+			return nil, err
+		}
+		profiles[i] = profile
+	}
+	return coverreport.MakeDiff(profiles[0], profiles[1]), nil
+}
+
+func (mgr *Manager) httpCoverSnapshots(w http.ResponseWriter, r *http.Request) {
+	snapshots, err := listCoverSnapshots(mgr.cfg.Workdir)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to list snapshots: %v", err), http.StatusInternalServerError)
+		return
+	}
+	// Show the newest first.
+	for i, j := 0, len(snapshots)-1; i < j; i, j = i+1, j-1 {
+		snapshots[i], snapshots[j] = snapshots[j], snapshots[i]
+	}
+	if err := coverSnapshotsTemplate.Execute(w, snapshots); err != nil {
+		http.Error(w, fmt.Sprintf("failed to execute template: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+)
+
+func TestCoverHash(t *testing.T) {
+	h1 := coverHash([]uint32{1, 2, 3})
+	if h2 := coverHash([]uint32{3, 1, 2}); h2 != h1 {
+		t.Fatalf("hash depends on the order of PCs")
+	}
+	// The same number of PCs, but a different set.
+	if h3 := coverHash([]uint32{1, 2, 4}); h3 == h1 {
+		t.Fatalf("different sets of PCs have the same hash")
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	. "github.com/google/syzkaller/pkg/rpctype"
+	"github.com/google/syzkaller/syz-manager/mgrconfig"
+)
+
+func testFaultManager(workdir string) *Manager {
+	return &Manager{
+		cfg:         &mgrconfig.Config{Workdir: workdir},
+		faultSites:  make(map[uint32]*FaultSite),
+		faultPoints: make(map[faultPoint]uint32),
+		fuzzers: map[string]*Fuzzer{
+			"vm-0": new(Fuzzer),
+			"vm-1": new(Fuzzer),
+		},
+	}
+}
+
+func TestFaultSites(t *testing.T) {
+	workdir, err := ioutil.TempDir("", "syz-manager-fault")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(workdir)
+	mgr := testFaultManager(workdir)
+	mgr.addFaultSites("vm-0", []RpcFaultSite{
+		{PC: 1, Call: "open", Nth: 0, Hits: 1},
+		{PC: 1, Call: "open", Nth: 1, Hits: 2},
+		{PC: 2, Call: "read", Nth: 0, Hits: 1},
+	})
+	mgr.addFaultSites("vm-1", []RpcFaultSite{{PC: 1, Call: "open", Nth: 0, Hits: 5}})
+	if len(mgr.faultSites) != 2 || mgr.faultSites[1].Hits != 8 || mgr.faultSites[2].Hits != 1 {
+		t.Fatalf("bad fault sites: %+v", mgr.faultSites)
+	}
+	// Fault points that hit new sites are sent to other fuzzers, hit counts are not.
+	want := []RpcFaultSite{
+		{PC: 1, Call: "open", Nth: 0},
+		{PC: 1, Call: "open", Nth: 1},
+		{PC: 2, Call: "read", Nth: 0},
+	}
+	if got := mgr.fuzzers["vm-1"].faultSites; !reflect.DeepEqual(got, want) {
+		t.Fatalf("bad fault sites for other fuzzers:\n%+v\nwant:\n%+v", got, want)
+	}
+	if got := mgr.fuzzers["vm-0"].faultSites; len(got) != 0 {
+		t.Fatalf("known fault sites are sent back: %+v", got)
+	}
+
+	if err := mgr.saveFaultSites(); err != nil {
+		t.Fatal(err)
+	}
+	mgr1 := testFaultManager(workdir)
+	mgr1.loadFaultSites()
+	if len(mgr1.faultSites) != 2 || mgr1.faultSites[1].Hits != 8 || mgr1.faultSites[1].Call != "open" {
+		t.Fatalf("bad loaded fault sites: %+v", mgr1.faultSites)
+	}
+	if !reflect.DeepEqual(mgr1.faultPoints, mgr.faultPoints) {
+		t.Fatalf("bad loaded fault points:\n%+v\nwant:\n%+v", mgr1.faultPoints, mgr.faultPoints)
+	}
+}
@@ -0,0 +1,309 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/coverreport"
+	. "github.com/google/syzkaller/pkg/log"
+	. "github.com/google/syzkaller/pkg/rpctype"
+	"github.com/google/syzkaller/pkg/symbolizer"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/syz-manager/mgrconfig"
+)
+
+const (
+	// Functions further than this number of calls from the focus targets get zero weight.
+	maxFocusDistance = 10
+	// Priority of syscalls with coverage in the focus targets is multiplied by 1+focusPrioBoost.
+	focusPrioBoost = 10
+)
+
+// focusInfo is the directed fuzzing state computed from the focus targets in config.
+// PCs are in the same format as coverage reported by fuzzers.
+type focusInfo struct {
+	weights map[uint32]float32 // weight of PCs: 1/(1+call distance from function of the PC to targets)
+	targets map[uint32]bool    // PCs in the focus targets
+}
+
+// initFocus computes the focus weights asynchronously on start, it takes some time
+// as it requires disassembling vmlinux and, for file/line targets, symbolizing all coverage PCs.
+func (mgr *Manager) initFocus() {
+	if len(mgr.cfg.ParsedFocus) == 0 {
+		return
+	}
+	go func() {
+		focus, err := computeFocus(mgr.cfg.Vmlinux, mgr.cfg.ParsedFocus)
+		if err != nil {
+			Logf(0, "failed to compute focus: %v", err)
+			return
+		}
+		Logf(0, "focus: %v target PCs, %v PCs with non-zero weight", len(focus.targets), len(focus.weights))
+		mgr.mu.Lock()
+		mgr.focus = focus
+		mgr.mu.Unlock()
+	}()
+}
+
+func computeFocus(vmlinux string, targets []mgrconfig.FocusTarget) (*focusInfo, error) {
+	symbols, err := sortedSymbols()
+	if err != nil {
+		return nil, err
+	}
+	<-allCoverReady
+	if len(allCoverPCs) == 0 {
+		return nil, fmt.Errorf("no coverage PCs in %v", vmlinux)
+	}
+	funcOf := func(pc uint64) string {
+		if s := coverreport.FindSymbol(symbols, pc); s != nil {
+			return s.Name
+		}
+		return ""
+	}
+	targetPCs, err := resolveFocusTargets(vmlinux, targets, funcOf)
+	if err != nil {
+		return nil, err
+	}
+	if len(targetPCs) == 0 {
+		return nil, fmt.Errorf("focus targets do not match any coverage PCs")
+	}
+	distance := make(map[string]int)
+	for _, pc := range targetPCs {
+		if fn := funcOf(pc); fn != "" {
+			distance[fn] = 0
+		}
+	}
+	graph, err := callGraph(vmlinux)
+	if err != nil {
+		return nil, err
+	}
+	focusDistances(graph, distance)
+	focus := &focusInfo{
+		weights: make(map[uint32]float32),
+		targets: make(map[uint32]bool),
+	}
+	for _, pc := range targetPCs {
+		focus.targets[coverPC(pc)] = true
+		focus.weights[coverPC(pc)] = 1
+	}
+	for _, pc := range allCoverPCs {
+		if d, ok := distance[funcOf(pc)]; ok {
+			focus.weights[coverPC(pc)] = 1 / float32(1+d)
+		}
+	}
+	return focus, nil
+}
+
+// coverPC converts a PC of __sanitizer_cov_trace_pc call to the format reported by fuzzers
+// (return address truncated to 32 bits, see cover.RestorePC).
+func coverPC(pc uint64) uint32 {
+	return uint32(pc + coverreport.CallLen)
+}
+
+// resolveFocusTargets returns coverage PCs that belong to the targets.
+func resolveFocusTargets(vmlinux string, targets []mgrconfig.FocusTarget,
+	funcOf func(pc uint64) string) ([]uint64, error) {
+	funcs := make(map[string]bool)
+	var files []mgrconfig.FocusTarget
+	for _, target := range targets {
+		if target.Func != "" {
+			if allSymbols[target.Func] == nil {
+				Logf(0, "focus: function %v is not present in vmlinux", target.Func)
+			}
+			funcs[target.Func] = true
+		} else {
+			files = append(files, target)
+		}
+	}
+	var pcs []uint64
+	for _, pc := range allCoverPCs {
+		if funcs[funcOf(pc)] {
+			pcs = append(pcs, pc)
+		}
+	}
+	if len(files) == 0 {
+		return pcs, nil
+	}
+	symb := symbolizer.NewSymbolizer()
+	defer symb.Close()
+	frames, err := symb.SymbolizeArray(vmlinux, allCoverPCs)
+	if err != nil {
+		return nil, err
+	}
+	matched := make(map[uint64]bool)
+	for _, frame := range frames {
+		if matched[frame.PC] {
+			continue
+		}
+		for _, target := range files {
+			if matchFocusFile(frame, target) {
+				matched[frame.PC] = true
+				break
+			}
+		}
+	}
+	for _, pc := range allCoverPCs {
+		if matched[pc] && !funcs[funcOf(pc)] {
+			pcs = append(pcs, pc)
+		}
+	}
+	return pcs, nil
+}
+
+// matchFocusFile checks if frame (possibly an inlined one) is in the target file/lines.
+// Target file matches absolute paths in debug info by suffix.
+func matchFocusFile(frame symbolizer.Frame, target mgrconfig.FocusTarget) bool {
+	if frame.File != target.File && !strings.HasSuffix(frame.File, "/"+target.File) {
+		return false
+	}
+	return target.Line == 0 || frame.Line >= target.Line && frame.Line <= target.EndLine
+}
+
+// focusDistances computes call distance to the target functions for functions that
+// (transitively) call them. distance must contain the target functions with distance 0.
+// graph maps caller to callees.
+func focusDistances(graph map[string][]string, distance map[string]int) {
+	callers := make(map[string][]string)
+	for caller, callees := range graph {
+		for _, callee := range callees {
+			callers[callee] = append(callers[callee], caller)
+		}
+	}
+	var queue []string
+	for fn := range distance {
+		queue = append(queue, fn)
+	}
+	for len(queue) != 0 {
+		fn := queue[0]
+		queue = queue[1:]
+		d := distance[fn] + 1
+		if d > maxFocusDistance {
+			continue
+		}
+		for _, caller := range callers[fn] {
+			if _, ok := distance[caller]; !ok {
+				distance[caller] = d
+				queue = append(queue, caller)
+			}
+		}
+	}
+}
+
+// callGraph returns static call graph of binary bin: map of functions to functions they call directly.
+func callGraph(bin string) (map[string][]string, error) {
+	cmd := exec.Command("objdump", "-d", "--no-show-raw-insn", bin)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	defer stdout.Close()
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	defer cmd.Wait()
+	return parseCallGraph(stdout)
+}
+
+func parseCallGraph(r io.Reader) (map[string][]string, error) {
+	graph := make(map[string][]string)
+	seen := make(map[string]bool)
+	caller := ""
+	s := bufio.NewScanner(r)
+	// A function starts as: "ffffffff81000000 <startup_64>:"
+	// and a call looks as: "ffffffff8100206a:       callq  ffffffff815cc1d0 <memset+0x10>"
+	callInsn := []byte("callq ")
+	for s.Scan() {
+		ln := s.Bytes()
+		if len(ln) != 0 && ln[len(ln)-1] == ':' {
+			if lt := bytes.IndexByte(ln, '<'); lt != -1 && bytes.HasSuffix(ln, []byte(">:")) {
+				caller = string(ln[lt+1 : len(ln)-2])
+				seen = make(map[string]bool)
+			}
+			continue
+		}
+		pos := bytes.Index(ln, callInsn)
+		if pos == -1 || caller == "" {
+			continue
+		}
+		ln = ln[pos:]
+		lt := bytes.IndexByte(ln, '<')
+		gt := bytes.IndexByte(ln, '>')
+		if lt == -1 || gt < lt {
+			continue
+		}
+		callee := ln[lt+1 : gt]
+		if plus := bytes.IndexByte(callee, '+'); plus != -1 {
+			callee = callee[:plus]
+		}
+		if len(callee) == 0 || bytes.HasPrefix(callee, []byte("__sanitizer_")) || seen[string(callee)] {
+			continue
+		}
+		seen[string(callee)] = true
+		graph[caller] = append(graph[caller], string(callee))
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return graph, nil
+}
+
+// weight returns max focus weight of PCs in cov.
+func (focus *focusInfo) weight(cov []uint32) float32 {
+	w := float32(0)
+	for _, pc := range cov {
+		if w1 := focus.weights[pc]; w1 > w {
+			w = w1
+		}
+	}
+	return w
+}
+
+// filter returns PCs in cov with non-zero weight, that's enough for fuzzers to weight the input.
+func (focus *focusInfo) filter(cov []uint32) []uint32 {
+	var res []uint32
+	for _, pc := range cov {
+		if focus.weights[pc] != 0 {
+			res = append(res, pc)
+		}
+	}
+	return res
+}
+
+// reached returns number of focus target PCs covered by corpus.
+func (focus *focusInfo) reached(corpusCover map[uint32]struct{}) int {
+	n := 0
+	for pc := range focus.targets {
+		if _, ok := corpusCover[pc]; ok {
+			n++
+		}
+	}
+	return n
+}
+
+// boostPrios returns a copy of prios where priorities of calls to syscalls that have
+// coverage close to the focus targets in corpus are increased.
+func (focus *focusInfo) boostPrios(target *prog.Target, prios [][]float32, corpus map[string]RpcInput) [][]float32 {
+	weights := make(map[string]float32)
+	for _, inp := range corpus {
+		if w := focus.weight(inp.Cover); w > weights[inp.Call] {
+			weights[inp.Call] = w
+		}
+	}
+	res := make([][]float32, len(prios))
+	for i := range prios {
+		res[i] = append([]float32{}, prios[i]...)
+		for _, c := range target.Syscalls {
+			if w := weights[c.CallName]; w != 0 && c.ID < len(res[i]) {
+				res[i][c.ID] *= 1 + focusPrioBoost*w
+			}
+		}
+	}
+	return res
+}
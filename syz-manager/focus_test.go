@@ -0,0 +1,48 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFocusDistances(t *testing.T) {
+	objdump := `
+ffffffff81000000 <sys_foo>:
+ffffffff81000004:       callq  ffffffff815cc1d0 <__sanitizer_cov_trace_pc>
+ffffffff81000009:       callq  ffffffff81000100 <foo_helper>
+ffffffff8100000e:       callq  ffffffff81000100 <foo_helper>
+ffffffff81000013:       callq  ffffffff81000300 <unrelated>
+
+ffffffff81000100 <foo_helper>:
+ffffffff81000104:       callq  ffffffff81000210 <target+0x10>
+ffffffff81000109:       retq
+
+ffffffff81000200 <target>:
+ffffffff81000204:       callq  ffffffff81000300 <unrelated>
+
+ffffffff81000300 <unrelated>:
+ffffffff81000304:       retq
+`
+	graph, err := parseCallGraph(strings.NewReader(objdump))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantGraph := map[string][]string{
+		"sys_foo":    {"foo_helper", "unrelated"},
+		"foo_helper": {"target"},
+		"target":     {"unrelated"},
+	}
+	if !reflect.DeepEqual(graph, wantGraph) {
+		t.Fatalf("got call graph %v, want %v", graph, wantGraph)
+	}
+	distance := map[string]int{"target": 0}
+	focusDistances(graph, distance)
+	wantDistance := map[string]int{"target": 0, "foo_helper": 1, "sys_foo": 2}
+	if !reflect.DeepEqual(distance, wantDistance) {
+		t.Fatalf("got distances %v, want %v", distance, wantDistance)
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"bufio"
 	"fmt"
 	"html/template"
-	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
@@ -18,9 +17,11 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/google/syzkaller/pkg/cover"
+	"github.com/google/syzkaller/pkg/coverreport"
 	. "github.com/google/syzkaller/pkg/log"
 	"github.com/google/syzkaller/pkg/osutil"
 	"github.com/google/syzkaller/pkg/report"
@@ -33,10 +34,19 @@ func (mgr *Manager) initHttp() {
 	http.HandleFunc("/corpus", mgr.httpCorpus)
 	http.HandleFunc("/crash", mgr.httpCrash)
 	http.HandleFunc("/cover", mgr.httpCover)
+	http.HandleFunc("/coversnaps", mgr.httpCoverSnapshots)
 	http.HandleFunc("/prio", mgr.httpPrio)
+	http.HandleFunc("/faults", mgr.httpFaults)
+	http.HandleFunc("/flaky", mgr.httpFlaky)
+	http.HandleFunc("/syscalls", mgr.httpSyscalls)
+	http.HandleFunc("/races", mgr.httpRaces)
 	http.HandleFunc("/file", mgr.httpFile)
 	http.HandleFunc("/report", mgr.httpReport)
 	http.HandleFunc("/rawcover", mgr.httpRawCover)
+	mgr.initApi()
+	mgr.initImport()
+	http.HandleFunc("/api/v1/control", mgr.apiControl)
+	http.Handle("/metrics", mgr.initMetrics())
 
 	ln, err := net.Listen("tcp4", mgr.cfg.Http)
 	if err != nil {
@@ -50,58 +60,60 @@ func (mgr *Manager) initHttp() {
 }
 
 func (mgr *Manager) httpSummary(w http.ResponseWriter, r *http.Request) {
-	data := &UISummaryData{
-		Name: mgr.cfg.Name,
-	}
-
-	var err error
-	if data.Crashes, err = collectCrashes(mgr.cfg.Workdir); err != nil {
+	crashes, err := mgr.collectCrashes()
+	if err != nil {
 		http.Error(w, fmt.Sprintf("failed to collect crashes: %v", err), http.StatusInternalServerError)
 		return
 	}
-
 	mgr.mu.Lock()
-	defer mgr.mu.Unlock()
+	stats := mgr.collectStats()
+	mgr.mu.Unlock()
 
-	data.Stats = append(data.Stats, UIStat{Name: "uptime", Value: fmt.Sprint(time.Since(mgr.startTime) / 1e9 * 1e9)})
-	data.Stats = append(data.Stats, UIStat{Name: "fuzzing", Value: fmt.Sprint(mgr.fuzzingTime / 60e9 * 60e9)})
-	data.Stats = append(data.Stats, UIStat{Name: "corpus", Value: fmt.Sprint(len(mgr.corpus))})
-	data.Stats = append(data.Stats, UIStat{Name: "triage queue", Value: fmt.Sprint(len(mgr.candidates))})
-	data.Stats = append(data.Stats, UIStat{Name: "cover", Value: fmt.Sprint(len(mgr.corpusCover)), Link: "/cover"})
-	data.Stats = append(data.Stats, UIStat{Name: "signal", Value: fmt.Sprint(len(mgr.corpusSignal))})
-
-	type CallCov struct {
-		count int
-		cov   cover.Cover
+	data := &UISummaryData{
+		Name:    stats.Name,
+		Calls:   stats.Calls,
+		Crashes: crashes,
 	}
-	calls := make(map[string]*CallCov)
-	for _, inp := range mgr.corpus {
-		if calls[inp.Call] == nil {
-			calls[inp.Call] = new(CallCov)
+	data.Stats = append(data.Stats, UIStat{Name: "uptime", Value: fmt.Sprint(time.Duration(stats.Uptime) * time.Second)})
+	data.Stats = append(data.Stats, UIStat{Name: "fuzzing", Value: fmt.Sprint(time.Duration(stats.FuzzingTime/60*60) * time.Second)})
+	data.Stats = append(data.Stats, UIStat{Name: "corpus", Value: fmt.Sprint(stats.Corpus)})
+	data.Stats = append(data.Stats, UIStat{Name: "triage queue", Value: fmt.Sprint(stats.TriageQueue)})
+	data.Stats = append(data.Stats, UIStat{Name: "cover", Value: fmt.Sprint(stats.Cover), Link: "/cover"})
+	if mgr.cfg.Vmlinux != "" && mgr.cfg.Cover_Snapshot_Period != 0 {
+		data.Stats = append(data.Stats, UIStat{Name: "cover snapshots", Value: "list", Link: "/coversnaps"})
+	}
+	data.Stats = append(data.Stats, UIStat{Name: "signal", Value: fmt.Sprint(stats.Signal)})
+	data.Stats = append(data.Stats, UIStat{Name: "syscalls", Value: fmt.Sprint(stats.Syscalls), Link: "/syscalls"})
+	if stats.LeakSites != 0 {
+		data.Stats = append(data.Stats, UIStat{Name: "leak sites", Value: fmt.Sprint(stats.LeakSites)})
+	}
+	if stats.RacePairs != 0 {
+		data.Stats = append(data.Stats, UIStat{Name: "race pairs", Value: fmt.Sprint(stats.RacePairs), Link: "/races"})
+	}
+	if stats.FlakySignal != 0 {
+		data.Stats = append(data.Stats, UIStat{Name: "flaky signal", Value: fmt.Sprint(stats.FlakySignal), Link: "/flaky"})
+		data.Stats = append(data.Stats, UIStat{Name: "noisy signal", Value: fmt.Sprint(stats.NoisySignal)})
+	}
+	if stats.FaultSites != 0 {
+		data.Stats = append(data.Stats, UIStat{Name: "fault sites", Value: fmt.Sprint(stats.FaultSites), Link: "/faults"})
+	}
+	if stats.Focus != nil {
+		focus := "computing"
+		if !stats.Focus.Computing {
+			focus = fmt.Sprintf("not reached (0/%v PCs)", stats.Focus.Targets)
+			if stats.Focus.Reached != 0 {
+				focus = fmt.Sprintf("reached (%v/%v PCs)", stats.Focus.Reached, stats.Focus.Targets)
+			}
 		}
-		cc := calls[inp.Call]
-		cc.count++
-		cc.cov = cover.Union(cc.cov, cover.Cover(inp.Cover))
+		data.Stats = append(data.Stats, UIStat{Name: "focus", Value: focus})
 	}
 
 	secs := uint64(1)
-	if !mgr.firstConnect.IsZero() {
-		secs = uint64(time.Since(mgr.firstConnect))/1e9 + 1
-	}
-
-	var cov cover.Cover
-	for c, cc := range calls {
-		cov = cover.Union(cov, cc.cov)
-		data.Calls = append(data.Calls, UICallType{
-			Name:   c,
-			Inputs: cc.count,
-			Cover:  len(cc.cov),
-		})
+	if !stats.FirstConnect.IsZero() {
+		secs = uint64(time.Since(stats.FirstConnect))/1e9 + 1
 	}
-	sort.Sort(UICallTypeArray(data.Calls))
-
 	var intStats []UIStat
-	for k, v := range mgr.stats {
+	for k, v := range stats.Counters {
 		val := fmt.Sprintf("%v", v)
 		if x := v / secs; x >= 10 {
 			val += fmt.Sprintf(" (%v/sec)", x)
@@ -130,6 +142,7 @@ func (mgr *Manager) httpCrash(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprintf("failed to read crash info"), http.StatusInternalServerError)
 		return
 	}
+	mgr.markReproducing([]*UICrashType{crash})
 	if err := crashTemplate.Execute(w, crash); err != nil {
 		http.Error(w, fmt.Sprintf("failed to execute template: %v", err), http.StatusInternalServerError)
 		return
@@ -137,28 +150,11 @@ func (mgr *Manager) httpCrash(w http.ResponseWriter, r *http.Request) {
 }
 
 func (mgr *Manager) httpCorpus(w http.ResponseWriter, r *http.Request) {
-	mgr.mu.Lock()
-	defer mgr.mu.Unlock()
-
-	var data []UIInput
-	call := r.FormValue("call")
-	for sig, inp := range mgr.corpus {
-		if call != inp.Call {
-			continue
-		}
-		p, err := mgr.target.Deserialize(inp.Prog)
-		if err != nil {
-			http.Error(w, fmt.Sprintf("failed to deserialize program: %v", err), http.StatusInternalServerError)
-			return
-		}
-		data = append(data, UIInput{
-			Short: p.String(),
-			Full:  string(inp.Prog),
-			Cover: len(inp.Cover),
-			Sig:   sig,
-		})
+	data, err := mgr.collectCorpus(r.FormValue("call"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
-	sort.Sort(UIInputArray(data))
 
 	if err := corpusTemplate.Execute(w, data); err != nil {
 		http.Error(w, fmt.Sprintf("failed to execute template: %v", err), http.StatusInternalServerError)
@@ -167,13 +163,27 @@ func (mgr *Manager) httpCorpus(w http.ResponseWriter, r *http.Request) {
 }
 
 func (mgr *Manager) httpCover(w http.ResponseWriter, r *http.Request) {
-	mgr.mu.Lock()
-	defer mgr.mu.Unlock()
-
 	if mgr.cfg.Vmlinux == "" {
 		http.Error(w, fmt.Sprintf("no vmlinux in config file"), http.StatusInternalServerError)
 		return
 	}
+	if spec := r.FormValue("diff"); spec != "" {
+		// Diffs are symbolized without holding mgr.mu, see coverDiff.
+		diff, err := mgr.coverDiff(spec)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to diff coverage: %v", err), http.StatusInternalServerError)
+			return
+		}
+		if err := generateCoverDiffHtml(w, diff); err != nil {
+			http.Error(w, fmt.Sprintf("failed to generate coverage diff: %v", err), http.StatusInternalServerError)
+			return
+		}
+		runtime.GC()
+		return
+	}
+
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
 	var cov cover.Cover
 	if sig := r.FormValue("input"); sig != "" {
 		cov = mgr.corpus[sig].Cover
@@ -186,6 +196,24 @@ func (mgr *Manager) httpCover(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if format := r.FormValue("format"); format != "" {
+		if !coverreport.IsFormat(format) {
+			http.Error(w, fmt.Sprintf("unknown coverage format %q, supported: %v",
+				format, strings.Join(coverreport.Formats, ", ")), http.StatusBadRequest)
+			return
+		}
+		profile, err := coverProfile(mgr.cfg.Vmlinux, cov)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to generate coverage profile: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", coverreport.ContentType(format))
+		if err := coverreport.Export(w, profile, format); err != nil {
+			Logf(0, "failed to export coverage: %v", err)
+		}
+		runtime.GC()
+		return
+	}
 	if err := generateCoverHtml(w, mgr.cfg.Vmlinux, cov); err != nil {
 		http.Error(w, fmt.Sprintf("failed to generate coverage profile: %v", err), http.StatusInternalServerError)
 		return
@@ -223,6 +251,220 @@ func (mgr *Manager) httpPrio(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (mgr *Manager) httpFaults(w http.ResponseWriter, r *http.Request) {
+	mgr.mu.Lock()
+	var data []UIFaultSite
+	for pc, site := range mgr.faultSites {
+		data = append(data, UIFaultSite{
+			PC:    uint64(pc),
+			Call:  site.Call,
+			Hits:  site.Hits,
+			Found: site.Found.Format(dateFormat),
+		})
+	}
+	mgr.mu.Unlock()
+	sort.Sort(UIFaultSiteArray(data))
+
+	if mgr.cfg.Vmlinux != "" {
+		pcs := make([]uint32, len(data))
+		for i, site := range data {
+			pcs[i] = uint32(site.PC)
+		}
+		restored, funcs, err := mgr.symbolizePCs(pcs)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for i := range data {
+			data[i].PC = restored[i]
+			data[i].Func = funcs[i]
+		}
+	}
+
+	if err := faultsTemplate.Execute(w, data); err != nil {
+		http.Error(w, fmt.Sprintf("failed to execute template: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+// httpSyscalls shows per-syscall execution stats, sorted by the sort parameter
+// (name, execs, success, einval, signal, crashes, memory or limits), or exports them as JSON if json=1.
+func (mgr *Manager) httpSyscalls(w http.ResponseWriter, r *http.Request) {
+	data := mgr.collectSyscalls()
+	var less func(a, b *UISyscall) bool
+	switch r.FormValue("sort") {
+	case "", "name":
+		less = func(a, b *UISyscall) bool { return a.Name < b.Name }
+	case "execs":
+		less = func(a, b *UISyscall) bool { return a.Execs > b.Execs }
+	case "success":
+		less = func(a, b *UISyscall) bool { return a.Success < b.Success }
+	case "einval":
+		less = func(a, b *UISyscall) bool { return a.Einval > b.Einval }
+	case "signal":
+		less = func(a, b *UISyscall) bool { return a.NewSignal > b.NewSignal }
+	case "crashes":
+		less = func(a, b *UISyscall) bool { return a.Crashes > b.Crashes }
+	case "memory":
+		less = func(a, b *UISyscall) bool { return a.MaxMemory > b.MaxMemory }
+	case "limits":
+		less = func(a, b *UISyscall) bool { return a.LimitHits > b.LimitHits }
+	default:
+		http.Error(w, fmt.Sprintf("unknown sort: %v", r.FormValue("sort")), http.StatusBadRequest)
+		return
+	}
+	sort.SliceStable(data, func(i, j int) bool { return less(&data[i], &data[j]) })
+
+	if r.FormValue("json") == "1" {
+		writeJSON(w, data)
+		return
+	}
+	if err := syscallsTemplate.Execute(w, data); err != nil {
+		http.Error(w, fmt.Sprintf("failed to execute template: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (mgr *Manager) collectSyscalls() []UISyscall {
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	calls := make(map[string]bool)
+	for _, call := range mgr.enabledCalls {
+		calls[call] = true
+	}
+	for call := range mgr.callStats {
+		calls[call] = true
+	}
+	data := []UISyscall{}
+	for call := range calls {
+		stats := mgr.callStats[call]
+		if stats == nil {
+			stats = &CallStats{}
+		}
+		data = append(data, newUISyscall(call, stats))
+	}
+	sort.Slice(data, func(i, j int) bool { return data[i].Name < data[j].Name })
+	return data
+}
+
+func newUISyscall(call string, stats *CallStats) UISyscall {
+	s := UISyscall{
+		Name:      call,
+		Execs:     stats.Execs,
+		Errnos:    stats.Errnos,
+		NewSignal: stats.NewSignal,
+		Crashes:   stats.Crashes,
+		Broken:    stats.Execs >= brokenCallExecs && stats.Errnos[0] == 0,
+		MaxMemory: stats.MaxMemory >> 20,
+		MaxFds:    stats.MaxFds,
+		MaxPids:   stats.MaxPids,
+		Limits:    make(map[string]uint64),
+	}
+	for limit, n := range stats.Limits {
+		s.Limits[limit] = n
+		s.LimitHits += n
+	}
+	var total, topErrno uint64
+	for errno, n := range stats.Errnos {
+		total += n
+		if errno != 0 && n > topErrno {
+			topErrno = n
+			s.TopErrno = fmt.Sprintf("%v (%v)", syscall.Errno(errno).Error(), errno)
+		}
+	}
+	if total != 0 {
+		s.Success = float64(stats.Errnos[0]) * 100 / float64(total)
+		s.Einval = float64(stats.Errnos[int(syscall.EINVAL)]) * 100 / float64(total)
+	}
+	return s
+}
+
+// httpRaces shows pairs of syscalls raced by fuzzers, the ones that led to crashes first.
+func (mgr *Manager) httpRaces(w http.ResponseWriter, r *http.Request) {
+	mgr.mu.Lock()
+	var data []RacePair
+	for _, pair := range mgr.racePairs {
+		data = append(data, *pair)
+	}
+	mgr.mu.Unlock()
+	sort.Slice(data, func(i, j int) bool {
+		if data[i].Crashes != data[j].Crashes {
+			return data[i].Crashes > data[j].Crashes
+		}
+		if data[i].NewSignal != data[j].NewSignal {
+			return data[i].NewSignal > data[j].NewSignal
+		}
+		return data[i].Execs > data[j].Execs
+	})
+
+	if err := racesTemplate.Execute(w, data); err != nil {
+		http.Error(w, fmt.Sprintf("failed to execute template: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (mgr *Manager) httpFlaky(w http.ResponseWriter, r *http.Request) {
+	mgr.mu.Lock()
+	var data []UIFlakySignal
+	for s, n := range mgr.flakySignal {
+		_, noisy := mgr.noisySignal[s]
+		data = append(data, UIFlakySignal{
+			Signal: uint64(s),
+			Count:  n,
+			Noisy:  noisy,
+		})
+	}
+	mgr.mu.Unlock()
+	sort.Sort(UIFlakySignalArray(data))
+	if len(data) > 100 {
+		data = data[:100]
+	}
+
+	// With pc signal, signal values are coverage PCs and can be symbolized.
+	if mgr.cfg.Signal == "pc" && mgr.cfg.Vmlinux != "" {
+		pcs := make([]uint32, len(data))
+		for i, s := range data {
+			pcs[i] = uint32(s.Signal)
+		}
+		restored, funcs, err := mgr.symbolizePCs(pcs)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for i := range data {
+			data[i].Signal = restored[i]
+			data[i].Func = funcs[i]
+		}
+	}
+
+	if err := flakyTemplate.Execute(w, data); err != nil {
+		http.Error(w, fmt.Sprintf("failed to execute template: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+// symbolizePCs restores full coverage PCs reported by fuzzers and returns function+offset for them.
+func (mgr *Manager) symbolizePCs(pcs []uint32) ([]uint64, []string, error) {
+	base, err := coverreport.VMOffset(mgr.cfg.Vmlinux)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get vm offset: %v", err)
+	}
+	symbols, err := sortedSymbols()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read symbols: %v", err)
+	}
+	restored := make([]uint64, len(pcs))
+	funcs := make([]string, len(pcs))
+	for i, pc32 := range pcs {
+		pc := cover.RestorePC(pc32, base) - coverreport.CallLen
+		restored[i] = pc
+		if s := coverreport.FindSymbol(symbols, pc); s != nil {
+			funcs[i] = fmt.Sprintf("%v+0x%x", s.Name, pc-s.Start)
+		}
+	}
+	return restored, funcs, nil
+}
+
 func (mgr *Manager) httpFile(w http.ResponseWriter, r *http.Request) {
 	file := filepath.Clean(r.FormValue("name"))
 	if !strings.HasPrefix(file, "crashes/") && !strings.HasPrefix(file, "corpus/") {
@@ -230,14 +472,13 @@ func (mgr *Manager) httpFile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	file = filepath.Join(mgr.cfg.Workdir, file)
-	f, err := os.Open(file)
+	data, err := readCrashLog(file)
 	if err != nil {
 		http.Error(w, "failed to open the file", http.StatusInternalServerError)
 		return
 	}
-	defer f.Close()
 	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
-	io.Copy(w, f)
+	w.Write(data)
 }
 
 func (mgr *Manager) httpReport(w http.ResponseWriter, r *http.Request) {
@@ -295,7 +536,7 @@ func (mgr *Manager) httpRawCover(w http.ResponseWriter, r *http.Request) {
 	mgr.mu.Lock()
 	defer mgr.mu.Unlock()
 
-	base, err := getVmOffset(mgr.cfg.Vmlinux)
+	base, err := coverreport.VMOffset(mgr.cfg.Vmlinux)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("failed to get vmlinux base: %v", err), http.StatusInternalServerError)
 		return
@@ -309,7 +550,7 @@ func (mgr *Manager) httpRawCover(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 	buf := bufio.NewWriter(w)
 	for _, pc := range cov {
-		restored := cover.RestorePC(pc, base) - callLen
+		restored := cover.RestorePC(pc, base) - coverreport.CallLen
 		fmt.Fprintf(buf, "0x%x\n", restored)
 	}
 	buf.Flush()
@@ -361,15 +602,14 @@ func readCrash(workdir, dir string, full bool) *UICrashType {
 	var crashes []*UICrash
 	reproAttempts := 0
 	hasRepro, hasCRepro := false, false
+	var retest *RetestStatus
 	reports := make(map[string]bool)
 	for _, f := range files {
-		if strings.HasPrefix(f, "log") {
-			index, err := strconv.ParseUint(f[3:], 10, 64)
-			if err == nil {
-				crashes = append(crashes, &UICrash{
-					Index: int(index),
-				})
-			}
+		if index, compressed, ok := parseCrashLogName(f); ok {
+			crashes = append(crashes, &UICrash{
+				Index:      index,
+				compressed: compressed,
+			})
 		} else if strings.HasPrefix(f, "report") {
 			reports[f] = true
 		} else if f == "repro.prog" {
@@ -377,6 +617,8 @@ func readCrash(workdir, dir string, full bool) *UICrashType {
 		} else if f == "repro.cprog" {
 			hasCRepro = true
 		} else if f == "repro.report" {
+		} else if f == "repro.retest" {
+			retest = readRetest(filepath.Join(crashdir, dir))
 		} else if f == "repro0" || f == "repro1" || f == "repro2" {
 			reproAttempts++
 		}
@@ -386,6 +628,9 @@ func readCrash(workdir, dir string, full bool) *UICrashType {
 		for _, crash := range crashes {
 			index := strconv.Itoa(crash.Index)
 			crash.Log = filepath.Join("crashes", dir, "log"+index)
+			if crash.compressed {
+				crash.Log += ".gz"
+			}
 			if stat, err := os.Stat(filepath.Join(workdir, crash.Log)); err == nil {
 				crash.Time = stat.ModTime()
 				crash.TimeStr = crash.Time.Format(dateFormat)
@@ -396,6 +641,10 @@ func readCrash(workdir, dir string, full bool) *UICrashType {
 			if osutil.IsExist(filepath.Join(workdir, reportFile)) {
 				crash.Report = reportFile
 			}
+			if data, err := ioutil.ReadFile(filepath.Join(crashdir, dir, "signature"+index)); err == nil {
+				lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+				crash.Signature, crash.Frames = lines[0], lines[1:]
+			}
 		}
 		sort.Sort(UICrashArray(crashes))
 	}
@@ -411,13 +660,44 @@ func readCrash(workdir, dir string, full bool) *UICrashType {
 		triaged = "non-reproducible"
 	}
 	return &UICrashType{
-		Description: string(desc),
-		LastTime:    modTime.Format(dateFormat),
-		ID:          dir,
-		Count:       len(crashes),
-		Triaged:     triaged,
-		Crashes:     crashes,
+		Description:   string(desc),
+		LastTime:      modTime.Format(dateFormat),
+		ID:            dir,
+		Count:         len(crashes),
+		Triaged:       triaged,
+		HasRepro:      hasRepro,
+		HasCRepro:     hasCRepro,
+		ReproAttempts: reproAttempts,
+		Retest:        retest,
+		Crashes:       crashes,
+		Stacks:        groupStacks(crashes),
+	}
+}
+
+// groupStacks groups crashes with the same title by stack signature,
+// different signatures are likely different bugs.
+func groupStacks(crashes []*UICrash) []*UIStack {
+	var stacks []*UIStack
+	bySignature := make(map[string]*UIStack)
+	for _, crash := range crashes {
+		if crash.Signature == "" {
+			continue
+		}
+		stack := bySignature[crash.Signature]
+		if stack == nil {
+			stack = &UIStack{
+				Signature: crash.Signature,
+				Frames:    crash.Frames,
+			}
+			bySignature[crash.Signature] = stack
+			stacks = append(stacks, stack)
+		}
+		stack.Crashes = append(stack.Crashes, crash.Index)
 	}
+	sort.SliceStable(stacks, func(i, j int) bool {
+		return len(stacks[i].Crashes) > len(stacks[j].Crashes)
+	})
+	return stacks
 }
 
 func trimNewLines(data []byte) []byte {
@@ -436,21 +716,37 @@ type UISummaryData struct {
 }
 
 type UICrashType struct {
-	Description string
-	LastTime    string
-	ID          string
-	Count       int
-	Triaged     string
-	Crashes     []*UICrash
+	Description   string
+	LastTime      string
+	ID            string
+	Count         int
+	Triaged       string
+	HasRepro      bool
+	HasCRepro     bool
+	ReproAttempts int           // number of failed repro attempts
+	Reproducing   bool          // currently being reproduced
+	Retest        *RetestStatus // result of the last retest of the reproducer (nil if not retested)
+	Crashes       []*UICrash
+	Stacks        []*UIStack // crashes grouped by stack signature
+}
+
+type UIStack struct {
+	Signature string
+	Frames    []string
+	Crashes   []int // indexes of crashes with this signature
 }
 
 type UICrash struct {
-	Index   int
-	Time    time.Time
-	TimeStr string
-	Log     string
-	Report  string
-	Tag     string
+	Index     int
+	Time      time.Time
+	TimeStr   string
+	Log       string
+	Report    string
+	Tag       string
+	Signature string
+	Frames    []string
+
+	compressed bool
 }
 
 type UIStat struct {
@@ -466,11 +762,13 @@ type UICallType struct {
 }
 
 type UIInput struct {
-	Short string
-	Full  string
-	Calls int
-	Cover int
-	Sig   string
+	Short  string
+	Full   string
+	Call   string
+	Calls  []string
+	Signal int
+	Cover  int
+	Sig    string
 }
 
 type UICallTypeArray []UICallType
@@ -537,6 +835,7 @@ var summaryTemplate = template.Must(template.New("").Parse(addStyle(`
 		<th>Count</th>
 		<th>Last Time</th>
 		<th>Report</th>
+		<th>Retest</th>
 	</tr>
 	{{range $c := $.Crashes}}
 	<tr>
@@ -546,8 +845,11 @@ var summaryTemplate = template.Must(template.New("").Parse(addStyle(`
 		<td>
 			{{if $c.Triaged}}
 				<a href="/report?id={{$c.ID}}">{{$c.Triaged}}</a>
+			{{else if $c.Reproducing}}
+				reproducing
 			{{end}}
 		</td>
+		<td>{{if $c.Retest}}{{$c.Retest.Status}}{{end}}</td>
 	</tr>
 	{{end}}
 </table>
@@ -590,13 +892,39 @@ var crashTemplate = template.Must(template.New("").Parse(addStyle(`
 {{if .Triaged}}
 Report: <a href="/report?id={{.ID}}">{{.Triaged}}</a>
 {{end}}
+{{with .Retest}}
+<br>
+Retest: {{.Status}} (crashed {{.Crashed}}/{{.Attempts}} times{{if .Other}}, {{.Other}} times with other titles{{end}} on {{if .Tag}}{{.Tag}}{{else}}current kernel{{end}}, {{.Time.Format "2006/01/02 15:04"}}){{if .Desc}}, last crash: {{.Desc}}{{end}}
+{{end}}
+<br><br>
+
+{{if .Stacks}}
+<table>
+	<caption>Stacks:</caption>
+	<tr>
+		<th>Signature</th>
+		<th>Count</th>
+		<th>Frames</th>
+		<th>Crashes</th>
+	</tr>
+	{{range $s := $.Stacks}}
+	<tr>
+		<td>{{printf "%.8s" $s.Signature}}</td>
+		<td>{{len $s.Crashes}}</td>
+		<td>{{range $f := $s.Frames}}{{$f}}<br>{{end}}</td>
+		<td>{{range $i := $s.Crashes}}{{$i}} {{end}}</td>
+	</tr>
+	{{end}}
+</table>
 <br><br>
+{{end}}
 
 <table>
 	<tr>
 		<th>#</th>
 		<th>Log</th>
 		<th>Report</th>
+		<th>Stack</th>
 		<th>Time</th>
 		<th>Tag</th>
 	</tr>
@@ -609,6 +937,7 @@ Report: <a href="/report?id={{.ID}}">{{.Triaged}}</a>
 		{{else}}
 			<td></td>
 		{{end}}
+		<td>{{printf "%.8s" $c.Signature}}</td>
 		<td>{{$c.TimeStr}}</td>
 		<td>{{$c.Tag}}</td>
 	</tr>
@@ -664,6 +993,215 @@ Priorities for {{$.Call}} <br> <br>
 </body></html>
 `)))
 
+type UIFaultSite struct {
+	PC    uint64
+	Func  string
+	Call  string
+	Hits  uint64
+	Found string
+}
+
+type UIFaultSiteArray []UIFaultSite
+
+func (a UIFaultSiteArray) Len() int           { return len(a) }
+func (a UIFaultSiteArray) Less(i, j int) bool { return a[i].Hits > a[j].Hits }
+func (a UIFaultSiteArray) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+
+var faultsTemplate = template.Must(template.New("").Parse(addStyle(`
+<!doctype html>
+<html>
+<head>
+	<title>syzkaller fault sites</title>
+	{{STYLE}}
+</head>
+<body>
+<table>
+	<caption>Fault injection sites ({{len $}}):</caption>
+	<tr>
+		<th>PC</th>
+		<th>Function</th>
+		<th>Syscall</th>
+		<th>Hits</th>
+		<th>Found</th>
+	</tr>
+	{{range $s := $}}
+	<tr>
+		<td>{{printf "0x%x" $s.PC}}</td>
+		<td>{{$s.Func}}</td>
+		<td>{{$s.Call}}</td>
+		<td>{{$s.Hits}}</td>
+		<td>{{$s.Found}}</td>
+	</tr>
+	{{end}}
+</table>
+</body></html>
+`)))
+
+type UISyscall struct {
+	Name      string
+	Execs     uint64
+	Errnos    map[int]uint64
+	Success   float64 // percent of successful executions
+	Einval    float64 // percent of executions that failed with EINVAL
+	TopErrno  string
+	NewSignal uint64
+	Crashes   uint64
+	Broken    bool   // never succeeded after brokenCallExecs executions
+	MaxMemory uint64 // peak memory usage of programs with the syscall in MB
+	MaxFds    int
+	MaxPids   int
+	Limits    map[string]uint64 // number of programs with the syscall that hit resource limits
+	LimitHits uint64            // total over all limits
+}
+
+var syscallsTemplate = template.Must(template.New("").Parse(addStyle(`
+<!doctype html>
+<html>
+<head>
+	<title>syzkaller syscalls</title>
+	{{STYLE}}
+</head>
+<body>
+<table>
+	<caption>Syscalls ({{len $}}, <a href="/syscalls?json=1">json</a>):</caption>
+	<tr>
+		<th><a href="/syscalls?sort=name">Syscall</a></th>
+		<th><a href="/syscalls?sort=execs">Executions</a></th>
+		<th><a href="/syscalls?sort=success">Success</a></th>
+		<th><a href="/syscalls?sort=einval">EINVAL</a></th>
+		<th>Top errno</th>
+		<th><a href="/syscalls?sort=signal">New signal</a></th>
+		<th><a href="/syscalls?sort=crashes">Crashes</a></th>
+		<th><a href="/syscalls?sort=memory">Memory</a></th>
+		<th>Fds</th>
+		<th>Pids</th>
+		<th><a href="/syscalls?sort=limits">Limit hits</a></th>
+		<th>Broken</th>
+	</tr>
+	{{range $s := $}}
+	<tr>
+		<td>{{$s.Name}}</td>
+		<td>{{$s.Execs}}</td>
+		<td>{{printf "%.1f%%" $s.Success}}</td>
+		<td>{{printf "%.1f%%" $s.Einval}}</td>
+		<td>{{$s.TopErrno}}</td>
+		<td>{{$s.NewSignal}}</td>
+		<td>{{$s.Crashes}}</td>
+		<td>{{$s.MaxMemory}}MB</td>
+		<td>{{$s.MaxFds}}</td>
+		<td>{{$s.MaxPids}}</td>
+		<td>{{range $limit, $n := $s.Limits}}{{$limit}}:{{$n}} {{end}}</td>
+		<td>{{if $s.Broken}}likely broken{{end}}</td>
+	</tr>
+	{{end}}
+</table>
+</body></html>
+`)))
+
+var coverSnapshotsTemplate = template.Must(template.New("").Parse(addStyle(`
+<!doctype html>
+<html>
+<head>
+	<title>syzkaller coverage snapshots</title>
+	{{STYLE}}
+</head>
+<body>
+<table>
+	<caption>Coverage snapshots ({{len $}}):</caption>
+	<tr>
+		<th>Snapshot</th>
+		<th>Time</th>
+		<th>Tag</th>
+		<th>PCs</th>
+		<th>Diff</th>
+	</tr>
+	{{range $s := $}}
+	<tr>
+		<td>{{$s.Name}}</td>
+		<td>{{$s.Time}}</td>
+		<td>{{$s.Tag}}</td>
+		<td>{{$s.PCs}}</td>
+		<td>
+			{{if $s.Prev}}<a href="/cover?diff={{$s.Prev}}..{{$s.Name}}">previous</a>{{end}}
+			<a href="/cover?diff={{$s.Name}}..current">current</a>
+		</td>
+	</tr>
+	{{end}}
+</table>
+</body></html>
+`)))
+
+var racesTemplate = template.Must(template.New("").Parse(addStyle(`
+<!doctype html>
+<html>
+<head>
+	<title>syzkaller races</title>
+	{{STYLE}}
+</head>
+<body>
+<table>
+	<caption>Race pairs ({{len $}}):</caption>
+	<tr>
+		<th>Call 1</th>
+		<th>Call 2</th>
+		<th>Executions</th>
+		<th>New signal</th>
+		<th>Crashes</th>
+	</tr>
+	{{range $p := $}}
+	<tr>
+		<td>{{$p.Call1}}</td>
+		<td>{{$p.Call2}}</td>
+		<td>{{$p.Execs}}</td>
+		<td>{{$p.NewSignal}}</td>
+		<td>{{$p.Crashes}}</td>
+	</tr>
+	{{end}}
+</table>
+</body></html>
+`)))
+
+type UIFlakySignal struct {
+	Signal uint64
+	Func   string
+	Count  uint64
+	Noisy  bool
+}
+
+type UIFlakySignalArray []UIFlakySignal
+
+func (a UIFlakySignalArray) Len() int           { return len(a) }
+func (a UIFlakySignalArray) Less(i, j int) bool { return a[i].Count > a[j].Count }
+func (a UIFlakySignalArray) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+
+var flakyTemplate = template.Must(template.New("").Parse(addStyle(`
+<!doctype html>
+<html>
+<head>
+	<title>syzkaller flaky signal</title>
+	{{STYLE}}
+</head>
+<body>
+<table>
+	<caption>Most flaky signal:</caption>
+	<tr>
+		<th>Signal</th>
+		<th>Function</th>
+		<th>Flaky</th>
+		<th>Noisy</th>
+	</tr>
+	{{range $s := $}}
+	<tr>
+		<td>{{printf "0x%x" $s.Signal}}</td>
+		<td>{{$s.Func}}</td>
+		<td>{{$s.Count}}</td>
+		<td>{{if $s.Noisy}}noisy{{end}}</td>
+	</tr>
+	{{end}}
+</table>
+</body></html>
+`)))
+
 func addStyle(html string) string {
 	return strings.Replace(html, "{{STYLE}}", htmlStyle, -1)
 }
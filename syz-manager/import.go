@@ -0,0 +1,273 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/syzkaller/pkg/cover"
+	"github.com/google/syzkaller/pkg/db"
+	"github.com/google/syzkaller/pkg/hash"
+	. "github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/osutil"
+	. "github.com/google/syzkaller/pkg/rpctype"
+)
+
+// Live corpus import/export. All requests require the http_key from the config
+// passed in the X-Syz-Key header (see checkRequest).
+//
+// POST /api/v1/corpus/upload?origin=NAME[&minimized=1] uploads programs as candidates for triage.
+// The body is either a corpus.db file (as produced by syz-db pack), an execution log
+// or a single program. Origin of the upload is recorded in workdir/imports.
+// GET /api/v1/corpus/download returns the current corpus in corpus.db format.
+// POST /api/v1/corpus/delete?sig=HASH[&sig=HASH...] deletes inputs from the corpus.
+// Corpus signal/coverage are updated right away, but fuzzers that already have the inputs
+// keep mutating them until they reconnect (fuzzers with a corpus cache drop them on reconnect).
+// GET /api/v1/corpus/imports returns the list of uploads.
+func (mgr *Manager) initImport() {
+	http.HandleFunc("/api/v1/corpus/upload", mgr.apiCorpusUpload)
+	http.HandleFunc("/api/v1/corpus/download", mgr.apiCorpusDownload)
+	http.HandleFunc("/api/v1/corpus/delete", mgr.apiCorpusDelete)
+	http.HandleFunc("/api/v1/corpus/imports", mgr.apiCorpusImports)
+}
+
+// Import describes a single corpus upload.
+type Import struct {
+	Time     time.Time
+	Origin   string
+	Remote   string
+	Programs int // number of programs added as candidates
+	Dropped  int // number of programs that failed to parse
+}
+
+const maxUploadSize = 256 << 20
+
+func (mgr *Manager) apiCorpusUpload(w http.ResponseWriter, r *http.Request) {
+	if !mgr.checkRequest(w, r, "POST") {
+		return
+	}
+	origin := r.URL.Query().Get("origin")
+	if origin == "" {
+		http.Error(w, "missing origin", http.StatusBadRequest)
+		return
+	}
+	data, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxUploadSize))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read request: %v", err), http.StatusBadRequest)
+		return
+	}
+	progs, err := mgr.parseUpload(data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	imp := &Import{
+		Time:   time.Now(),
+		Origin: origin,
+		Remote: r.RemoteAddr,
+	}
+	minimized := r.URL.Query().Get("minimized") == "1"
+	// Parsing of a large upload takes a while, so it is done without holding mgr.mu.
+	var candidates []RpcCandidate
+	for _, data := range progs {
+		if _, err := mgr.target.Deserialize(data); err != nil {
+			imp.Dropped++
+			continue
+		}
+		candidates = append(candidates, RpcCandidate{
+			Prog:      data,
+			Minimized: minimized,
+		})
+	}
+	imp.Programs = len(candidates)
+	mgr.mu.Lock()
+	mgr.candidates = append(mgr.candidates, candidates...)
+	mgr.imports = append(mgr.imports, imp)
+	mgr.stats["imported"] += uint64(imp.Programs)
+	mgr.mu.Unlock()
+
+	Logf(0, "imported %v programs from %v (%v), dropped %v", imp.Programs, imp.Origin, imp.Remote, imp.Dropped)
+	mgr.saveImport(imp)
+	writeJSON(w, imp)
+}
+
+// parseUpload splits uploaded data into individual programs.
+func (mgr *Manager) parseUpload(data []byte) ([][]byte, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty upload")
+	}
+	if bytes.IndexByte(data, 0) != -1 {
+		// Binary data, must be a corpus.db file.
+		f, err := osutil.WriteTempFile(data)
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(f)
+		corpus, err := db.Open(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open corpus database: %v", err)
+		}
+		if len(corpus.Records) == 0 {
+			return nil, fmt.Errorf("no programs in corpus database")
+		}
+		var progs [][]byte
+		for _, rec := range corpus.Records {
+			progs = append(progs, rec.Val)
+		}
+		return progs, nil
+	}
+	if entries := mgr.target.ParseLog(data); len(entries) != 0 {
+		var progs [][]byte
+		for _, ent := range entries {
+			progs = append(progs, ent.P.Serialize())
+		}
+		return progs, nil
+	}
+	return [][]byte{data}, nil
+}
+
+func (mgr *Manager) saveImport(imp *Import) {
+	f, err := os.OpenFile(filepath.Join(mgr.cfg.Workdir, "imports"),
+		os.O_WRONLY|os.O_APPEND|os.O_CREATE, osutil.DefaultFilePerm)
+	if err != nil {
+		Logf(0, "failed to save import: %v", err)
+		return
+	}
+	defer f.Close()
+	fmt.Fprintf(f, "%v: origin=%q remote=%v programs=%v dropped=%v\n",
+		imp.Time.Format(time.RFC3339), imp.Origin, imp.Remote, imp.Programs, imp.Dropped)
+}
+
+func (mgr *Manager) apiCorpusDownload(w http.ResponseWriter, r *http.Request) {
+	if !mgr.checkRequest(w, r, "GET") {
+		return
+	}
+	// Write a compacted copy of the persistent corpus.
+	f, err := osutil.WriteTempFile(nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer os.Remove(f)
+	corpus, err := db.Open(f)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to create corpus database: %v", err), http.StatusInternalServerError)
+		return
+	}
+	mgr.mu.Lock()
+	for key, rec := range mgr.corpusDB.Records {
+		corpus.Save(key, rec.Val, rec.Seq)
+	}
+	mgr.mu.Unlock()
+	if err := corpus.Flush(); err != nil {
+		http.Error(w, fmt.Sprintf("failed to save corpus database: %v", err), http.StatusInternalServerError)
+		return
+	}
+	data, err := ioutil.ReadFile(f)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", "attachment; filename=corpus.db")
+	w.Write(data)
+}
+
+func (mgr *Manager) apiCorpusDelete(w http.ResponseWriter, r *http.Request) {
+	if !mgr.checkRequest(w, r, "POST") {
+		return
+	}
+	sigs := r.URL.Query()["sig"]
+	if len(sigs) == 0 {
+		http.Error(w, "missing sig", http.StatusBadRequest)
+		return
+	}
+	mgr.mu.Lock()
+	var deleted []string
+	for _, sig := range sigs {
+		if _, ok := mgr.corpus[sig]; !ok {
+			continue
+		}
+		delete(mgr.corpus, sig)
+		delete(mgr.inputsDirty, sig)
+		mgr.corpusDB.Delete(sig)
+		mgr.inputStatsMu.Lock()
+		mgr.inputStatsDB.Delete(sig)
+		mgr.inputStatsMu.Unlock()
+		deleted = append(deleted, sig)
+	}
+	if len(deleted) != 0 {
+		mgr.deleteCorpusInputsLocked()
+	}
+	if err := mgr.corpusDB.Flush(); err != nil {
+		Logf(0, "failed to save corpus database: %v", err)
+	}
+	mgr.mu.Unlock()
+
+	Logf(0, "deleted %v inputs from corpus (%v)", len(deleted), r.RemoteAddr)
+	if deleted == nil {
+		deleted = []string{}
+	}
+	writeJSON(w, deleted)
+}
+
+// deleteCorpusInputsLocked updates state derived from corpus after inputs are deleted from it.
+func (mgr *Manager) deleteCorpusInputsLocked() {
+	mgr.corpusSignal = make(map[uint32]struct{})
+	mgr.corpusCover = make(map[uint32]struct{})
+	for _, inp := range mgr.corpus {
+		cover.SignalAdd(mgr.corpusSignal, inp.Signal)
+		cover.SignalAdd(mgr.corpusCover, inp.Cover)
+	}
+	// Don't send deleted inputs to fuzzers that don't have them yet.
+	for _, f := range mgr.fuzzers {
+		var inputs []RpcInput
+		for _, inp := range f.inputs {
+			if _, ok := mgr.corpus[hash.String(inp.Prog)]; ok {
+				inputs = append(inputs, inp)
+			}
+		}
+		f.inputs = inputs
+	}
+}
+
+func (mgr *Manager) apiCorpusImports(w http.ResponseWriter, r *http.Request) {
+	if !mgr.checkRequest(w, r, "GET") {
+		return
+	}
+	mgr.mu.Lock()
+	data := append([]*Import{}, mgr.imports...)
+	mgr.mu.Unlock()
+	writeJSON(w, data)
+}
+
+// httpKeyHeader is the request header that carries http_key.
+// The key is not accepted in URL parameters, because URLs end up in logs of proxies and servers.
+const httpKeyHeader = "X-Syz-Key"
+
+// checkRequest checks method and key of a request that accesses corpus or modifies manager state.
+func (mgr *Manager) checkRequest(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method != method {
+		http.Error(w, fmt.Sprintf("method %v is not allowed", r.Method), http.StatusMethodNotAllowed)
+		return false
+	}
+	if mgr.cfg.Http_Key == "" {
+		http.Error(w, "http_key is not configured", http.StatusForbidden)
+		return false
+	}
+	key := r.Header.Get(httpKeyHeader)
+	if subtle.ConstantTimeCompare([]byte(key), []byte(mgr.cfg.Http_Key)) != 1 {
+		Logf(0, "unauthorized http request from %v: %v", r.RemoteAddr, r.URL.Path)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
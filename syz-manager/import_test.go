@@ -0,0 +1,157 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/db"
+	"github.com/google/syzkaller/pkg/hash"
+	"github.com/google/syzkaller/pkg/osutil"
+	. "github.com/google/syzkaller/pkg/rpctype"
+	"github.com/google/syzkaller/prog"
+	_ "github.com/google/syzkaller/sys"
+	"github.com/google/syzkaller/syz-manager/mgrconfig"
+)
+
+func TestParseUpload(t *testing.T) {
+	target, err := prog.GetTarget("linux", "amd64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mgr := &Manager{target: target}
+	prog1 := "getpid()\n"
+	prog2 := "getuid()\nsched_yield()\n"
+
+	progs, err := mgr.parseUpload([]byte(prog1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(progs) != 1 || string(progs[0]) != prog1 {
+		t.Fatalf("bad single program upload: %q", progs)
+	}
+
+	log := "executing program 0:\n" + prog1 + "\nexecuting program 1:\n" + prog2
+	progs, err = mgr.parseUpload([]byte(log))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(progs) != 2 || string(progs[0]) != prog1 || string(progs[1]) != prog2 {
+		t.Fatalf("bad log upload: %q", progs)
+	}
+
+	f, err := osutil.WriteTempFile(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f)
+	corpus, err := db.Open(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	corpus.Save("1", []byte(prog1), 0)
+	corpus.Save("2", []byte(prog2), 0)
+	if err := corpus.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	progs, err = mgr.parseUpload(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(progs) != 2 {
+		t.Fatalf("bad corpus.db upload: %q", progs)
+	}
+
+	if _, err := mgr.parseUpload(nil); err == nil {
+		t.Fatalf("no error for empty upload")
+	}
+}
+
+func TestCheckRequest(t *testing.T) {
+	mgr := &Manager{cfg: &mgrconfig.Config{Http_Key: "secret"}}
+	tests := []struct {
+		method string
+		key    string
+		query  string
+		code   int
+	}{
+		{"POST", "secret", "", http.StatusOK},
+		{"GET", "secret", "", http.StatusMethodNotAllowed},
+		{"POST", "", "", http.StatusUnauthorized},
+		{"POST", "secre", "", http.StatusUnauthorized},
+		{"POST", "", "key=secret", http.StatusUnauthorized},
+	}
+	for i, test := range tests {
+		r := httptest.NewRequest(test.method, "/api/v1/corpus/delete?"+test.query, nil)
+		if test.key != "" {
+			r.Header.Set(httpKeyHeader, test.key)
+		}
+		w := httptest.NewRecorder()
+		if ok := mgr.checkRequest(w, r, "POST"); ok != (test.code == http.StatusOK) || w.Code != test.code {
+			t.Errorf("#%v: got ok=%v code=%v, want code %v", i, ok, w.Code, test.code)
+		}
+	}
+	mgr.cfg.Http_Key = ""
+	r := httptest.NewRequest("POST", "/api/v1/corpus/delete", nil)
+	r.Header.Set(httpKeyHeader, "")
+	if w := httptest.NewRecorder(); mgr.checkRequest(w, r, "POST") || w.Code != http.StatusForbidden {
+		t.Errorf("request is allowed without http_key, code %v", w.Code)
+	}
+}
+
+func TestCorpusDelete(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syz-manager-import")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	corpusDB, err := db.Open(filepath.Join(dir, "corpus.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputStatsDB, err := db.Open(filepath.Join(dir, "corpus-stats.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	inp1 := RpcInput{Prog: []byte("getpid()\n"), Signal: []uint32{1, 2}, Cover: []uint32{10, 20}}
+	inp2 := RpcInput{Prog: []byte("getuid()\n"), Signal: []uint32{2, 3}, Cover: []uint32{20, 30}}
+	sig1, sig2 := hash.String(inp1.Prog), hash.String(inp2.Prog)
+	mgr := &Manager{
+		cfg:          &mgrconfig.Config{Http_Key: "secret"},
+		corpus:       map[string]RpcInput{sig1: inp1, sig2: inp2},
+		corpusDB:     corpusDB,
+		inputStatsDB: inputStatsDB,
+		corpusSignal: map[uint32]struct{}{1: {}, 2: {}, 3: {}},
+		corpusCover:  map[uint32]struct{}{10: {}, 20: {}, 30: {}},
+		fuzzers: map[string]*Fuzzer{
+			"vm-0": {inputs: []RpcInput{inp1, inp2}},
+		},
+	}
+	r := httptest.NewRequest("POST", "/api/v1/corpus/delete?sig="+sig2+"&sig=unknown", nil)
+	r.Header.Set(httpKeyHeader, "secret")
+	w := httptest.NewRecorder()
+	mgr.apiCorpusDelete(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got code %v: %s", w.Code, w.Body.Bytes())
+	}
+	if len(mgr.corpus) != 1 || len(mgr.corpusSignal) != 2 || len(mgr.corpusCover) != 2 {
+		t.Fatalf("bad corpus after delete: %v inputs, signal %v, cover %v",
+			len(mgr.corpus), mgr.corpusSignal, mgr.corpusCover)
+	}
+	if _, ok := mgr.corpusSignal[3]; ok {
+		t.Fatalf("signal of deleted input is in corpus signal")
+	}
+	if inputs := mgr.fuzzers["vm-0"].inputs; len(inputs) != 1 || string(inputs[0].Prog) != string(inp1.Prog) {
+		t.Fatalf("deleted input is still sent to fuzzers: %+v", inputs)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/db"
+	"github.com/google/syzkaller/pkg/hash"
+	. "github.com/google/syzkaller/pkg/rpctype"
+	"github.com/google/syzkaller/syz-manager/mgrconfig"
+)
+
+func TestInputStatsSave(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syz-manager-stats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "corpus_stats.db")
+	inputStatsDB, err := db.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inp1 := RpcInput{Prog: []byte("getpid()\n")}
+	inp2 := RpcInput{Prog: []byte("getuid()\n")}
+	sig1, sig2 := hash.String(inp1.Prog), hash.String(inp2.Prog)
+	mgr := &Manager{
+		cfg:          &mgrconfig.Config{},
+		inputStatsDB: inputStatsDB,
+		stats:        make(map[string]uint64),
+		fuzzerExecs:  make(map[string]uint64),
+		corpus:       map[string]RpcInput{sig1: inp1, sig2: inp2},
+		inputsDirty:  make(map[string]bool),
+		callStats:    make(map[string]*CallStats),
+		racePairs:    make(map[racePairKey]*RacePair),
+		fuzzers:      map[string]*Fuzzer{"vm-0": {}},
+	}
+	a := &PollArgs{
+		Name: "vm-0",
+		InputStats: []RpcInputStats{
+			{Sig: sig1, Chosen: 2, Found: 1},
+			{Sig: "unknown", Chosen: 1},
+		},
+	}
+	if err := mgr.Poll(a, new(PollRes)); err != nil {
+		t.Fatal(err)
+	}
+	if inp := mgr.corpus[sig1]; inp.Chosen != 2 || inp.Found != 1 {
+		t.Fatalf("counters are not updated: %+v", inp)
+	}
+	if len(mgr.inputStatsDB.Records) != 0 {
+		t.Fatalf("stats database is written on poll: %v", mgr.inputStatsDB.Records)
+	}
+
+	if err := mgr.saveInputStats(); err != nil {
+		t.Fatal(err)
+	}
+	if len(mgr.inputsDirty) != 0 {
+		t.Fatalf("inputs are still dirty after save: %v", mgr.inputsDirty)
+	}
+	mgr.inputStatsDB, err = db.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mgr.inputStatsDB.Records) != 1 {
+		t.Fatalf("want 1 saved record, got %v", mgr.inputStatsDB.Records)
+	}
+	if chosen, found := mgr.loadInputStats(sig1); chosen != 2 || found != 1 {
+		t.Fatalf("loaded chosen=%v found=%v, want 2/1", chosen, found)
+	}
+}
@@ -0,0 +1,307 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/osutil"
+)
+
+// Workdir janitor periodically enforces retention policy for crashes
+// (crashes_max_size, crash_max_logs, crash_max_age and crash_compress_age in config)
+// and removes VM instance dirs left by dead processes.
+
+const janitorPeriod = time.Hour
+
+type retentionPolicy struct {
+	maxSize     int64 // total size of crashes in bytes
+	maxLogs     int
+	maxAge      time.Duration
+	compressAge time.Duration
+}
+
+type janitorStats struct {
+	reclaimed  int64 // bytes
+	removed    int   // removed crash logs
+	compressed int   // compressed crash logs
+}
+
+// crashLog is a single crash log with the accompanying report, tag and signature files,
+// or logs of a reproduction attempt (index is reproLogIndex).
+type crashLog struct {
+	dir        string
+	index      int
+	time       time.Time
+	size       int64
+	compressed bool
+}
+
+const reproLogIndex = -1
+
+func (mgr *Manager) janitorLoop() {
+	policy := retentionPolicy{
+		maxSize:     int64(mgr.cfg.Crashes_Max_Size) << 20,
+		maxLogs:     mgr.cfg.Crash_Max_Logs,
+		maxAge:      time.Duration(mgr.cfg.Crash_Max_Age) * 24 * time.Hour,
+		compressAge: time.Duration(mgr.cfg.Crash_Compress_Age) * 24 * time.Hour,
+	}
+	for {
+		var stats janitorStats
+		if err := cleanCrashes(mgr.crashdir, policy, time.Now(), &mgr.crashMu, &stats); err != nil {
+			Logf(0, "janitor: failed to clean crashes: %v", err)
+		}
+		instances, err := osutil.RemoveStaleProcessTempDirs(mgr.cfg.Workdir)
+		if err != nil {
+			Logf(0, "janitor: failed to remove stale instance dirs: %v", err)
+		}
+		stats.reclaimed += instances
+		if stats.reclaimed != 0 || stats.compressed != 0 {
+			Logf(0, "janitor: reclaimed %v KB, removed %v logs, compressed %v logs",
+				stats.reclaimed>>10, stats.removed, stats.compressed)
+		}
+		mgr.mu.Lock()
+		mgr.stats["janitor reclaimed KB"] += uint64(stats.reclaimed >> 10)
+		mgr.stats["janitor removed logs"] += uint64(stats.removed)
+		mgr.stats["janitor compressed logs"] += uint64(stats.compressed)
+		mgr.mu.Unlock()
+		time.Sleep(janitorPeriod)
+	}
+}
+
+// cleanCrashes applies policy to all crashes in crashdir.
+// The latest log of every crash is always kept.
+// Crash logs are listed and removed under mu, but they are compressed without holding it,
+// so that saving of new crashes is not blocked for long.
+func cleanCrashes(crashdir string, policy retentionPolicy, now time.Time, mu sync.Locker,
+	stats *janitorStats) error {
+	mu.Lock()
+	candidates, compress, err := applyRetention(crashdir, policy, now, stats)
+	mu.Unlock()
+	if err != nil {
+		return err
+	}
+	for _, log := range compress {
+		if err := compressCrashLog(log, mu, stats); err != nil {
+			return err
+		}
+	}
+	if policy.maxSize == 0 {
+		return nil
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	size := osutil.DiskUsage(crashdir)
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].time.Before(candidates[j].time)
+	})
+	for _, log := range candidates {
+		if size <= policy.maxSize {
+			break
+		}
+		if !log.unchanged() {
+			// The log slot was reused for a new crash.
+			continue
+		}
+		size -= log.size
+		removeCrashLog(log, stats)
+	}
+	return nil
+}
+
+// applyRetention removes logs that exceed maxLogs/maxAge and returns logs
+// that can be removed to fit into maxSize (across all crashes) and logs that need compression.
+func applyRetention(crashdir string, policy retentionPolicy, now time.Time, stats *janitorStats) (
+	candidates, compress []*crashLog, err error) {
+	dirs, err := osutil.ListDir(crashdir)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, dir := range dirs {
+		logs, err := listCrashLogs(filepath.Join(crashdir, dir))
+		if err != nil {
+			return nil, nil, err
+		}
+		for i, log := range logs {
+			if i != 0 && (i >= policy.maxLogs || policy.maxAge != 0 && now.Sub(log.time) > policy.maxAge) {
+				removeCrashLog(log, stats)
+				continue
+			}
+			if policy.compressAge != 0 && !log.compressed && now.Sub(log.time) > policy.compressAge {
+				compress = append(compress, log)
+			}
+			if i != 0 {
+				candidates = append(candidates, log)
+			}
+		}
+		// Logs of reproduction are not needed to use the reproducer, so they can be removed as well.
+		if log := reproLog(filepath.Join(crashdir, dir)); log != nil {
+			candidates = append(candidates, log)
+		}
+	}
+	return candidates, compress, nil
+}
+
+// listCrashLogs returns logs of the crash in dir sorted from newest to oldest.
+func listCrashLogs(dir string) ([]*crashLog, error) {
+	files, err := osutil.ListDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var logs []*crashLog
+	for _, f := range files {
+		index, compressed, ok := parseCrashLogName(f)
+		if !ok {
+			continue
+		}
+		stat, err := os.Stat(filepath.Join(dir, f))
+		if err != nil {
+			continue
+		}
+		log := &crashLog{
+			dir:        dir,
+			index:      index,
+			time:       stat.ModTime(),
+			compressed: compressed,
+		}
+		for _, name := range crashLogFiles(log) {
+			if stat, err := os.Stat(name); err == nil {
+				log.size += stat.Size()
+			}
+		}
+		logs = append(logs, log)
+	}
+	sort.Slice(logs, func(i, j int) bool {
+		return logs[i].time.After(logs[j].time)
+	})
+	return logs, nil
+}
+
+// reproLog returns logs of reproduction of the crash in dir, or nil if there are none.
+func reproLog(dir string) *crashLog {
+	log := &crashLog{
+		dir:   dir,
+		index: reproLogIndex,
+	}
+	stat, err := os.Stat(crashLogName(log))
+	if err != nil {
+		return nil
+	}
+	log.time = stat.ModTime()
+	for _, name := range crashLogFiles(log) {
+		if stat, err := os.Stat(name); err == nil {
+			log.size += stat.Size()
+		}
+	}
+	return log
+}
+
+// parseCrashLogName parses "logN" and "logN.gz" file names.
+func parseCrashLogName(name string) (index int, compressed, ok bool) {
+	if !strings.HasPrefix(name, "log") {
+		return 0, false, false
+	}
+	compressed = strings.HasSuffix(name, ".gz")
+	index, err := strconv.Atoi(strings.TrimSuffix(name[3:], ".gz"))
+	if err != nil || index < 0 {
+		return 0, false, false
+	}
+	return index, compressed, true
+}
+
+func crashLogName(log *crashLog) string {
+	if log.index == reproLogIndex {
+		return filepath.Join(log.dir, "repro.stats.log")
+	}
+	name := filepath.Join(log.dir, fmt.Sprintf("log%v", log.index))
+	if log.compressed {
+		name += ".gz"
+	}
+	return name
+}
+
+func crashLogFiles(log *crashLog) []string {
+	if log.index == reproLogIndex {
+		return []string{crashLogName(log), filepath.Join(log.dir, "repro.log")}
+	}
+	files := []string{crashLogName(log)}
+	for _, prefix := range []string{"report", "tag", "signature"} {
+		files = append(files, filepath.Join(log.dir, fmt.Sprintf("%v%v", prefix, log.index)))
+	}
+	return files
+}
+
+func removeCrashLog(log *crashLog, stats *janitorStats) {
+	for _, name := range crashLogFiles(log) {
+		os.Remove(name)
+	}
+	stats.reclaimed += log.size
+	stats.removed++
+}
+
+// unchanged checks that the log was not overwritten with a new crash since it was listed.
+func (log *crashLog) unchanged() bool {
+	stat, err := os.Stat(crashLogName(log))
+	return err == nil && !stat.ModTime().After(log.time)
+}
+
+func compressCrashLog(log *crashLog, mu sync.Locker, stats *janitorStats) error {
+	name := crashLogName(log)
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	buf := new(bytes.Buffer)
+	gz := gzip.NewWriter(buf)
+	gz.Write(data)
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !log.unchanged() {
+		// The log slot was reused for a new crash while we were compressing.
+		return nil
+	}
+	if err := osutil.WriteFile(name+".gz", buf.Bytes()); err != nil {
+		return err
+	}
+	// Preserve modification time, it is used as crash time and for retention.
+	if err := os.Chtimes(name+".gz", log.time, log.time); err != nil {
+		return err
+	}
+	if err := os.Remove(name); err != nil {
+		return err
+	}
+	log.compressed = true
+	log.size -= int64(len(data) - buf.Len())
+	stats.reclaimed += int64(len(data) - buf.Len())
+	stats.compressed++
+	return nil
+}
+
+// readCrashLog reads crash log file that can be compressed by janitor.
+func readCrashLog(name string) ([]byte, error) {
+	data, err := ioutil.ReadFile(name)
+	if err != nil || !strings.HasSuffix(name, ".gz") {
+		return data, err
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	return ioutil.ReadAll(gz)
+}
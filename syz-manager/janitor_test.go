@@ -0,0 +1,220 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/pkg/osutil"
+)
+
+// createCrash creates crash dir with logs of the given ages (in days).
+func createCrash(t *testing.T, crashdir, name string, now time.Time, ages ...int) {
+	dir := filepath.Join(crashdir, name)
+	osutil.MkdirAll(dir)
+	osutil.WriteFile(filepath.Join(dir, "description"), []byte(name+"\n"))
+	for i, age := range ages {
+		mtime := now.Add(-time.Duration(age)*24*time.Hour - time.Minute)
+		for _, prefix := range []string{"log", "report"} {
+			file := filepath.Join(dir, fmt.Sprintf("%v%v", prefix, i))
+			if err := osutil.WriteFile(file, bytes.Repeat([]byte("a"), 1000)); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chtimes(file, mtime, mtime); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+func crashFiles(t *testing.T, crashdir, name string) []string {
+	files, err := osutil.ListDir(filepath.Join(crashdir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(files)
+	return files
+}
+
+func TestCleanCrashes(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		policy     retentionPolicy
+		crash1     []string
+		crash2     []string
+		removed    int
+		compressed int
+	}{
+		{
+			policy:  retentionPolicy{maxLogs: 100},
+			crash1:  []string{"description", "log0", "log1", "log2", "report0", "report1", "report2"},
+			crash2:  []string{"description", "log0", "log1", "report0", "report1"},
+			removed: 0,
+		},
+		{
+			policy:  retentionPolicy{maxLogs: 2},
+			crash1:  []string{"description", "log0", "log1", "report0", "report1"},
+			crash2:  []string{"description", "log0", "log1", "report0", "report1"},
+			removed: 1,
+		},
+		{
+			// The latest log is kept even if it is too old.
+			policy:  retentionPolicy{maxLogs: 100, maxAge: 5 * 24 * time.Hour},
+			crash1:  []string{"description", "log0", "log1", "log2", "report0", "report1", "report2"},
+			crash2:  []string{"description", "log0", "report0"},
+			removed: 1,
+		},
+		{
+			policy:     retentionPolicy{maxLogs: 100, compressAge: 2 * 24 * time.Hour},
+			crash1:     []string{"description", "log0", "log1", "log2.gz", "report0", "report1", "report2"},
+			crash2:     []string{"description", "log0.gz", "log1.gz", "report0", "report1"},
+			compressed: 3,
+		},
+		{
+			// The oldest logs across all crashes are removed first.
+			policy:  retentionPolicy{maxLogs: 100, maxSize: 7000},
+			crash1:  []string{"description", "log0", "log1", "report0", "report1"},
+			crash2:  []string{"description", "log0", "report0"},
+			removed: 2,
+		},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			crashdir, err := ioutil.TempDir("", "syz-janitor")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(crashdir)
+			createCrash(t, crashdir, "crash1", now, 0, 1, 3)
+			createCrash(t, crashdir, "crash2", now, 7, 10)
+			var stats janitorStats
+			if err := cleanCrashes(crashdir, test.policy, now, new(sync.Mutex), &stats); err != nil {
+				t.Fatal(err)
+			}
+			if got := crashFiles(t, crashdir, "crash1"); !reflect.DeepEqual(got, test.crash1) {
+				t.Errorf("crash1 files: got %v, want %v", got, test.crash1)
+			}
+			if got := crashFiles(t, crashdir, "crash2"); !reflect.DeepEqual(got, test.crash2) {
+				t.Errorf("crash2 files: got %v, want %v", got, test.crash2)
+			}
+			if stats.removed != test.removed || stats.compressed != test.compressed {
+				t.Errorf("removed/compressed %v/%v logs, want %v/%v",
+					stats.removed, stats.compressed, test.removed, test.compressed)
+			}
+			if (stats.removed != 0 || stats.compressed != 0) && stats.reclaimed == 0 {
+				t.Errorf("reclaimed no space")
+			}
+		})
+	}
+}
+
+func TestReadCompressedCrashLog(t *testing.T) {
+	crashdir, err := ioutil.TempDir("", "syz-janitor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(crashdir)
+	now := time.Now()
+	createCrash(t, crashdir, "crash", now, 3)
+	var stats janitorStats
+	policy := retentionPolicy{maxLogs: 100, compressAge: 24 * time.Hour}
+	if err := cleanCrashes(crashdir, policy, now, new(sync.Mutex), &stats); err != nil {
+		t.Fatal(err)
+	}
+	data, err := readCrashLog(filepath.Join(crashdir, "crash", "log0.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, bytes.Repeat([]byte("a"), 1000)) {
+		t.Fatalf("wrong log contents after compression")
+	}
+}
+
+// hookLocker runs hook when it is locked for the n-th time.
+type hookLocker struct {
+	sync.Mutex
+	n    int
+	hook func()
+}
+
+func (l *hookLocker) Lock() {
+	l.Mutex.Lock()
+	if l.n--; l.n == 0 {
+		l.hook()
+	}
+}
+
+func TestCleanCrashesConcurrentSave(t *testing.T) {
+	crashdir, err := ioutil.TempDir("", "syz-janitor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(crashdir)
+	now := time.Now()
+	createCrash(t, crashdir, "crash", now, 0, 3)
+	// A new crash overwrites log1 while it is being compressed.
+	newLog := []byte("new crash")
+	mu := &hookLocker{n: 2, hook: func() {
+		osutil.WriteFile(filepath.Join(crashdir, "crash", "log1"), newLog)
+	}}
+	var stats janitorStats
+	policy := retentionPolicy{maxLogs: 100, compressAge: 24 * time.Hour}
+	if err := cleanCrashes(crashdir, policy, now, mu, &stats); err != nil {
+		t.Fatal(err)
+	}
+	if stats.compressed != 0 {
+		t.Fatalf("compressed %v logs", stats.compressed)
+	}
+	if data, err := ioutil.ReadFile(filepath.Join(crashdir, "crash", "log1")); err != nil || !bytes.Equal(data, newLog) {
+		t.Fatalf("new crash log is lost: %q, %v", data, err)
+	}
+}
+
+func TestCleanCrashesReproLogs(t *testing.T) {
+	crashdir, err := ioutil.TempDir("", "syz-janitor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(crashdir)
+	now := time.Now()
+	createCrash(t, crashdir, "crash1", now, 0, 1)
+	createCrash(t, crashdir, "crash2", now, 0)
+	dir := filepath.Join(crashdir, "crash2")
+	mtime := now.Add(-5 * 24 * time.Hour)
+	for _, name := range []string{"repro.prog", "repro.log", "repro.stats.log"} {
+		file := filepath.Join(dir, name)
+		if err := osutil.WriteFile(file, bytes.Repeat([]byte("a"), 1000)); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(file, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Repro logs are older than log1 of crash1, so they are removed first, the reproducer is kept.
+	var stats janitorStats
+	policy := retentionPolicy{maxLogs: 100, maxSize: 8000}
+	if err := cleanCrashes(crashdir, policy, now, new(sync.Mutex), &stats); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"description", "log0", "report0", "repro.prog"}
+	if got := crashFiles(t, crashdir, "crash2"); !reflect.DeepEqual(got, want) {
+		t.Errorf("crash2 files: got %v, want %v", got, want)
+	}
+	want = []string{"description", "log0", "log1", "report0", "report1"}
+	if got := crashFiles(t, crashdir, "crash1"); !reflect.DeepEqual(got, want) {
+		t.Errorf("crash1 files: got %v, want %v", got, want)
+	}
+	if stats.removed != 1 || stats.reclaimed != 2000 {
+		t.Errorf("removed %v logs, reclaimed %v", stats.removed, stats.reclaimed)
+	}
+}
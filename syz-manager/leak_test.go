@@ -0,0 +1,39 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+
+	. "github.com/google/syzkaller/pkg/rpctype"
+)
+
+func TestAddLeaks(t *testing.T) {
+	mgr := &Manager{
+		leakSites: make(map[string]bool),
+		leakQueue: make(chan *Crash, 1),
+		fuzzers: map[string]*Fuzzer{
+			"vm-0": new(Fuzzer),
+			"vm-1": new(Fuzzer),
+		},
+	}
+	mgr.addLeaks("vm-0", []RpcLeak{{Site: "foo"}, {Site: "bar"}})
+	if !mgr.leakSites["foo"] || mgr.leakSites["bar"] {
+		t.Fatalf("bad leak sites: %v", mgr.leakSites)
+	}
+	if sites := mgr.fuzzers["vm-1"].leakSites; len(sites) != 1 || sites[0] != "foo" {
+		t.Fatalf("bad leak sites of other fuzzers: %v", sites)
+	}
+	if crash := <-mgr.leakQueue; crash.desc != "memory leak in foo" || crash.vmIndex != 0 {
+		t.Fatalf("bad leak crash: %+v", crash)
+	}
+	// The leak that was dropped because the queue was full is reported again.
+	mgr.addLeaks("vm-1", []RpcLeak{{Site: "foo"}, {Site: "bar"}})
+	if !mgr.leakSites["bar"] {
+		t.Fatalf("dropped leak site is not reported again")
+	}
+	if crash := <-mgr.leakQueue; crash.desc != "memory leak in bar" || crash.vmIndex != 1 {
+		t.Fatalf("bad leak crash: %+v", crash)
+	}
+}
@@ -4,15 +4,17 @@
 package main
 
 import (
-	"bytes"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"math/rand"
 	"net"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"syscall"
@@ -47,12 +49,17 @@ type Manager struct {
 	crashdir     string
 	port         int
 	corpusDB     *db.DB
+	inputStatsDB *db.DB     // power schedule counters of corpus inputs, see RpcInputStats
+	inputStatsMu sync.Mutex // protects inputStatsDB, it is flushed outside of mu (see saveInputStats)
 	startTime    time.Time
 	firstConnect time.Time
 	lastPrioCalc time.Time
 	fuzzingTime  time.Duration
 	stats        map[string]uint64
 	crashTypes   map[string]bool
+	crashMu      sync.Mutex        // protects crash logs against concurrent changes by janitor
+	crashClasses map[string]uint64 // number of crashes per title class, see crashClass
+	fuzzerExecs  map[string]uint64 // number of executed programs per fuzzer name
 	vmStop       chan bool
 	vmChecked    bool
 	fresh        bool
@@ -63,15 +70,32 @@ type Manager struct {
 	mu              sync.Mutex
 	phase           int
 	enabledSyscalls string
-	enabledCalls    []string // as determined by fuzzer
+	enabledCalls    []string     // as determined by fuzzer
+	baseSyscalls    map[int]bool // enabled syscalls from config, before overrides
+	callsVersion    int          // incremented when enabledSyscalls changes
+	overrides       Overrides
+	suppressions    []*regexp.Regexp // from overrides, in addition to config suppressions
+	ignores         []*regexp.Regexp // from overrides, in addition to config ignores
 
 	candidates     []RpcCandidate // untriaged inputs from corpus and hub
 	disabledHashes map[string]struct{}
 	corpus         map[string]RpcInput
+	inputsDirty    map[string]bool // inputs with power schedule counters changed since they were saved
 	corpusSignal   map[uint32]struct{}
 	maxSignal      map[uint32]struct{}
+	maxSignalLog   []uint32 // last max signal in the order it was added, for delta connect of fuzzers
+	maxSignalStart int      // index of maxSignalLog[0] in the whole log
 	corpusCover    map[uint32]struct{}
 	prios          [][]float32
+	focus          *focusInfo // nil if focus is not configured or not yet computed
+	faultSites     map[uint32]*FaultSite
+	faultPoints    map[faultPoint]uint32 // fault site hit by a fault point, persisted with faultSites
+	faultDirty     bool                  // fault sites changed since they were saved
+	flakySignal    map[uint32]uint64     // number of times signal was flaky in triage
+	noisySignal    map[uint32]struct{}
+	callStats      map[string]*CallStats
+	racePairs      map[racePairKey]*RacePair
+	leakSites      map[string]bool // allocation sites of memory leaks reported by fuzzers
 	newRepros      [][]byte
 
 	fuzzers        map[string]*Fuzzer
@@ -79,6 +103,13 @@ type Manager struct {
 	hubCorpus      map[hash.Sig]bool
 	needMoreRepros chan chan bool
 	hubReproQueue  chan *Crash
+	leakQueue      chan *Crash // memory leaks reported by fuzzers without VM crash
+
+	vms         []*VMState  // indexed by VM index
+	reproStatus ReproStatus // snapshot of vmLoop repro state
+	hubStatus   HubStatus
+	imports     []*Import      // corpus uploads over HTTP
+	campaign    *campaignState // nil if not in campaign mode
 
 	// For checking that files that we are using are not changing under us.
 	// Maps file name to modification time.
@@ -102,6 +133,88 @@ type Fuzzer struct {
 	name         string
 	inputs       []RpcInput
 	newMaxSignal []uint32
+	focusSent    bool
+	faultSites   []RpcFaultSite // new fault sites exercised by other fuzzers
+	noisySignal  []uint32
+	leakSites    []string // new leak sites reported by other fuzzers
+	callsVersion int      // version of enabled syscalls the fuzzer has
+}
+
+// CallStats are execution counters of a syscall aggregated across all fuzzers.
+type CallStats struct {
+	Execs     uint64
+	Errnos    map[int]uint64 // errno histogram, 0 means success
+	NewSignal uint64         // number of executions that gave new signal
+	Crashes   uint64         // number of crashes with the syscall in programs in the crash log
+	// Peak resource usage of programs with the syscall across all fuzzers.
+	MaxMemory uint64            // in bytes
+	MaxFds    int               // open fds at the end of the program
+	MaxPids   int               // tasks, only with cgroups
+	Limits    map[string]uint64 // number of programs that hit the limit, by limit name
+}
+
+// VMState is the current state of a test machine.
+type VMState struct {
+	Index     int
+	State     string // one of vmIdle, vmBooting, vmFuzzing, vmReproducing
+	Since     time.Time
+	Crashes   uint64
+	LastCrash string
+}
+
+const (
+	vmIdle        = "idle"
+	vmBooting     = "booting"
+	vmFuzzing     = "fuzzing"
+	vmReproducing = "reproducing"
+)
+
+// ReproStatus contains descriptions of crashes in the repro pipeline.
+type ReproStatus struct {
+	Pending     []string // waiting for the decision whether to reproduce
+	Queued      []string // waiting for free VMs
+	Reproducing []string
+}
+
+// HubStatus describes syncing with syz-hub.
+type HubStatus struct {
+	Connected     bool
+	LastSync      time.Time
+	LastError     string
+	LastErrorTime time.Time
+}
+
+// RacePair are counters of racing executions of a pair of syscalls aggregated across all fuzzers.
+type RacePair struct {
+	Call1     string
+	Call2     string
+	Execs     uint64
+	NewSignal uint64 // amount of new signal racing executions gave
+	Crashes   uint64 // number of crashes with the pair racing in the crash log
+}
+
+type racePairKey struct {
+	call1 string
+	call2 string
+}
+
+// Max number of entries kept in maxSignalLog.
+const maxSignalLogSize = 1 << 20
+
+// Syscalls that did not succeed after this number of executions are flagged as likely broken.
+const brokenCallExecs = 10000
+
+// FaultSite is a kernel fault injection site, see pkg/fuzzer.FaultSite.
+type FaultSite struct {
+	Call  string // syscall that hit the site first
+	Hits  uint64
+	Found time.Time
+}
+
+// faultPoint is nth fault injection point of a syscall.
+type faultPoint struct {
+	call string
+	nth  int
 }
 
 type Crash struct {
@@ -119,6 +232,10 @@ func main() {
 	if err != nil {
 		Fatalf("%v", err)
 	}
+	if flag.NArg() != 0 && flag.Arg(0) == "ctl" {
+		runCtl(cfg, flag.Args()[1:])
+		return
+	}
 	target, err := prog.GetTarget(cfg.TargetOS, cfg.TargetArch)
 	if err != nil {
 		Fatalf("%v", err)
@@ -145,11 +262,7 @@ func RunManager(cfg *mgrconfig.Config, target *prog.Target, syscalls map[int]boo
 
 	enabledSyscalls := ""
 	if len(syscalls) != 0 {
-		buf := new(bytes.Buffer)
-		for c := range syscalls {
-			fmt.Fprintf(buf, ",%v", c)
-		}
-		enabledSyscalls = buf.String()[1:]
+		enabledSyscalls = serializeSyscalls(syscalls)
 		Logf(1, "enabled syscalls: %v", enabledSyscalls)
 	}
 
@@ -161,8 +274,19 @@ func RunManager(cfg *mgrconfig.Config, target *prog.Target, syscalls map[int]boo
 		startTime:       time.Now(),
 		stats:           make(map[string]uint64),
 		crashTypes:      make(map[string]bool),
+		crashClasses:    make(map[string]uint64),
+		fuzzerExecs:     make(map[string]uint64),
 		enabledSyscalls: enabledSyscalls,
+		baseSyscalls:    syscalls,
 		corpus:          make(map[string]RpcInput),
+		inputsDirty:     make(map[string]bool),
+		faultSites:      make(map[uint32]*FaultSite),
+		faultPoints:     make(map[faultPoint]uint32),
+		flakySignal:     make(map[uint32]uint64),
+		noisySignal:     make(map[uint32]struct{}),
+		callStats:       make(map[string]*CallStats),
+		racePairs:       make(map[racePairKey]*RacePair),
+		leakSites:       make(map[string]bool),
 		disabledHashes:  make(map[string]struct{}),
 		corpusSignal:    make(map[uint32]struct{}),
 		maxSignal:       make(map[uint32]struct{}),
@@ -171,9 +295,16 @@ func RunManager(cfg *mgrconfig.Config, target *prog.Target, syscalls map[int]boo
 		fresh:           true,
 		vmStop:          make(chan bool),
 		hubReproQueue:   make(chan *Crash, 10),
+		leakQueue:       make(chan *Crash, 10),
 		needMoreRepros:  make(chan chan bool),
 		usedFiles:       make(map[string]time.Time),
 	}
+	if cfg.Campaign() {
+		mgr.campaign = newCampaignState(mgr.startTime)
+	}
+
+	mgr.loadOverrides()
+	mgr.loadFaultSites()
 
 	Logf(0, "loading corpus...")
 	mgr.corpusDB, err = db.Open(filepath.Join(cfg.Workdir, "corpus.db"))
@@ -212,6 +343,10 @@ func RunManager(cfg *mgrconfig.Config, target *prog.Target, syscalls map[int]boo
 			Minimized: true, // don't reminimize programs from corpus, it takes lots of time on start
 		})
 	}
+	mgr.inputStatsDB, err = db.Open(filepath.Join(cfg.Workdir, "corpus_stats.db"))
+	if err != nil {
+		Fatalf("failed to open corpus stats database: %v", err)
+	}
 	mgr.fresh = len(mgr.corpusDB.Records) == 0
 	Logf(0, "loaded %v programs (%v total, %v deleted)", len(mgr.candidates), len(mgr.corpusDB.Records), deleted)
 
@@ -230,10 +365,11 @@ func RunManager(cfg *mgrconfig.Config, target *prog.Target, syscalls map[int]boo
 
 	// Create HTTP server.
 	mgr.initHttp()
+	mgr.initFocus()
 	mgr.collectUsedFiles()
 
 	// Create RPC server for fuzzers.
-	s, err := NewRpcServer(cfg.Rpc, mgr)
+	s, err := NewRpcServer(cfg.Rpc, mgr, true)
 	if err != nil {
 		Fatalf("failed to create rpc server: %v", err)
 	}
@@ -256,7 +392,9 @@ func RunManager(cfg *mgrconfig.Config, target *prog.Target, syscalls map[int]boo
 				mgr.mu.Unlock()
 				continue
 			}
-			mgr.fuzzingTime += diff * time.Duration(atomic.LoadUint32(&mgr.numFuzzing))
+			if !mgr.overrides.Paused {
+				mgr.fuzzingTime += diff * time.Duration(atomic.LoadUint32(&mgr.numFuzzing))
+			}
 			executed := mgr.stats["exec total"]
 			crashes := mgr.stats["crashes"]
 			mgr.mu.Unlock()
@@ -309,6 +447,25 @@ func RunManager(cfg *mgrconfig.Config, target *prog.Target, syscalls map[int]boo
 		}()
 	}
 
+	go func() {
+		for {
+			time.Sleep(time.Minute)
+			if err := mgr.saveFaultSites(); err != nil {
+				Logf(0, "failed to save fault sites: %v", err)
+			}
+			if err := mgr.saveInputStats(); err != nil {
+				Logf(0, "failed to save corpus stats database: %v", err)
+			}
+		}
+	}()
+	if mgr.campaign != nil {
+		go mgr.campaignLoop()
+	}
+	go mgr.janitorLoop()
+	if mgr.cfg.Vmlinux != "" && mgr.cfg.Cover && mgr.cfg.Cover_Snapshot_Period != 0 {
+		go mgr.coverSnapshotLoop()
+	}
+
 	go func() {
 		c := make(chan os.Signal, 2)
 		signal.Notify(c, syscall.SIGINT)
@@ -320,6 +477,9 @@ func RunManager(cfg *mgrconfig.Config, target *prog.Target, syscalls map[int]boo
 	}()
 
 	mgr.vmLoop()
+	if mgr.campaign != nil {
+		os.Exit(mgr.finishCampaign())
+	}
 }
 
 type RunResult struct {
@@ -344,6 +504,12 @@ func (mgr *Manager) vmLoop() {
 	if instancesPerRepro > vmCount {
 		instancesPerRepro = vmCount
 	}
+	// Retests use a quarter of VMs so that the rest keep fuzzing,
+	// with a single VM there is nothing left for fuzzing and retests are skipped.
+	instancesPerRetest := vmCount / 4
+	if instancesPerRetest == 0 {
+		instancesPerRetest = 1
+	}
 	instances := make([]int, vmCount)
 	for i := range instances {
 		instances[i] = vmCount - i - 1
@@ -354,7 +520,28 @@ func (mgr *Manager) vmLoop() {
 	reproInstances := 0
 	var reproQueue []*Crash
 	reproDone := make(chan *ReproResult, 1)
+	var retestQueue []*Retest
+	if instancesPerRetest < vmCount {
+		retestQueue = mgr.collectRetests()
+	} else if mgr.cfg.Retest_Repros > 0 {
+		Logf(0, "not retesting reproducers: need more than %v VMs", vmCount)
+	}
+	retesting := false
+	retestDone := make(chan *RetestResult, 1)
 	stopPending := false
+	// In campaign mode fuzzing is stopped when the budget is exhausted,
+	// but in-progress repros and retests are allowed to finish.
+	var campaignStop chan bool
+	if mgr.campaign != nil {
+		campaignStop = mgr.campaign.stop
+	}
+	stopping := false
+	mgr.mu.Lock()
+	mgr.vms = make([]*VMState, vmCount)
+	for i := range mgr.vms {
+		mgr.vms[i] = &VMState{Index: i, State: vmIdle, Since: time.Now()}
+	}
+	mgr.mu.Unlock()
 	shutdown := vm.Shutdown
 	for {
 		mgr.mu.Lock()
@@ -390,20 +577,42 @@ func (mgr *Manager) vmLoop() {
 			reproQueue = append(reproQueue, crash)
 		}
 
-		Logf(1, "loop: phase=%v shutdown=%v instances=%v/%v %+v repro: pending=%v reproducing=%v queued=%v",
+		Logf(1, "loop: phase=%v shutdown=%v instances=%v/%v %+v repro: pending=%v reproducing=%v queued=%v retest: %v",
 			phase, shutdown == nil, len(instances), vmCount, instances,
-			len(pendingRepro), len(reproducing), len(reproQueue))
+			len(pendingRepro), len(reproducing), len(reproQueue), len(retestQueue))
 
+		// When a campaign is stopping, crashes found so far (including the one that triggered the stop)
+		// are reproduced regardless of the phase, but no new fuzzing is started.
 		canRepro := func() bool {
-			return phase >= phaseTriagedHub &&
+			return (phase >= phaseTriagedHub || stopping) &&
 				len(reproQueue) != 0 && reproInstances+instancesPerRepro <= vmCount
 		}
+		// Retests run one at a time after hub triage and yield to queued repros,
+		// so that they don't take over all VMs.
+		canRetest := func() bool {
+			return phase >= phaseTriagedHub && len(retestQueue) != 0 && len(reproQueue) == 0 &&
+				!retesting && reproInstances+instancesPerRetest <= vmCount
+		}
 
-		if shutdown == nil {
+		if shutdown == nil || stopping && len(reproQueue) == 0 {
 			if len(instances) == vmCount {
 				return
 			}
 		} else {
+			if !stopping && canRetest() && len(instances) >= instancesPerRetest {
+				retest := retestQueue[0]
+				retestQueue = retestQueue[1:]
+				vmIndexes := append([]int{}, instances[len(instances)-instancesPerRetest:]...)
+				instances = instances[:len(instances)-instancesPerRetest]
+				reproInstances += instancesPerRetest
+				retesting = true
+				Logf(1, "loop: starting retest of '%v' on instances %+v", retest.desc, vmIndexes)
+				mgr.setVMState(vmIndexes, vmReproducing)
+				go func() {
+					res, err := mgr.runRetest(retest, vmIndexes)
+					retestDone <- &RetestResult{vmIndexes, retest, res, err}
+				}()
+			}
 			for canRepro() && len(instances) >= instancesPerRepro {
 				last := len(reproQueue) - 1
 				crash := reproQueue[last]
@@ -413,12 +622,13 @@ func (mgr *Manager) vmLoop() {
 				instances = instances[:len(instances)-instancesPerRepro]
 				reproInstances += instancesPerRepro
 				Logf(1, "loop: starting repro of '%v' on instances %+v", crash.desc, vmIndexes)
+				mgr.setVMState(vmIndexes, vmReproducing)
 				go func() {
 					res, err := repro.Run(crash.log, mgr.cfg, mgr.vmPool, vmIndexes)
 					reproDone <- &ReproResult{vmIndexes, crash.desc, res, err, crash.hub}
 				}()
 			}
-			for !canRepro() && len(instances) != 0 {
+			for !stopping && !canRepro() && !canRetest() && len(instances) != 0 {
 				last := len(instances) - 1
 				idx := instances[last]
 				instances = instances[:last]
@@ -430,8 +640,22 @@ func (mgr *Manager) vmLoop() {
 			}
 		}
 
+		mgr.mu.Lock()
+		mgr.reproStatus = ReproStatus{}
+		for crash := range pendingRepro {
+			mgr.reproStatus.Pending = append(mgr.reproStatus.Pending, crash.desc)
+		}
+		for _, crash := range reproQueue {
+			mgr.reproStatus.Queued = append(mgr.reproStatus.Queued, crash.desc)
+		}
+		for desc := range reproducing {
+			mgr.reproStatus.Reproducing = append(mgr.reproStatus.Reproducing, desc)
+		}
+		mgr.mu.Unlock()
+
 		var stopRequest chan bool
-		if !stopPending && canRepro() {
+		if !stopPending && (canRepro() || canRetest()) ||
+			stopping && len(instances)+reproInstances < vmCount {
 			stopRequest = mgr.vmStop
 		}
 
@@ -446,6 +670,13 @@ func (mgr *Manager) vmLoop() {
 			}
 			stopPending = false
 			instances = append(instances, res.idx)
+			mgr.setVMState([]int{res.idx}, vmIdle)
+			if res.crash != nil {
+				mgr.mu.Lock()
+				mgr.vms[res.idx].Crashes++
+				mgr.vms[res.idx].LastCrash = res.crash.desc
+				mgr.mu.Unlock()
+			}
 			// On shutdown qemu crashes with "qemu: terminating on signal 2",
 			// which we detect as "lost connection". Don't save that as crash.
 			if shutdown != nil && res.crash != nil && !mgr.isSuppressed(res.crash) {
@@ -469,6 +700,7 @@ func (mgr *Manager) vmLoop() {
 			}
 			delete(reproducing, res.desc0)
 			instances = append(instances, res.instances...)
+			mgr.setVMState(res.instances, vmIdle)
 			reproInstances -= instancesPerRepro
 			if res.res == nil {
 				if !res.hub {
@@ -477,12 +709,32 @@ func (mgr *Manager) vmLoop() {
 			} else {
 				mgr.saveRepro(res.res, res.hub)
 			}
+		case res := <-retestDone:
+			Logf(1, "loop: retest on %+v finished '%v'", res.instances, res.retest.desc)
+			if res.err != nil {
+				Logf(0, "retest failed: %v", res.err)
+			} else {
+				mgr.saveRetest(res.retest, res.res)
+			}
+			retesting = false
+			instances = append(instances, res.instances...)
+			mgr.setVMState(res.instances, vmIdle)
+			reproInstances -= instancesPerRetest
 		case <-shutdown:
 			Logf(1, "loop: shutting down...")
 			shutdown = nil
+		case <-campaignStop:
+			Logf(0, "campaign: stopping fuzzing, waiting for %v repros", len(reproducing)+len(pendingRepro))
+			campaignStop = nil
+			stopping = true
 		case crash := <-mgr.hubReproQueue:
 			Logf(1, "loop: get repro from hub")
 			pendingRepro[crash] = true
+		case crash := <-mgr.leakQueue:
+			if !mgr.isSuppressed(crash) && mgr.saveCrash(crash) {
+				Logf(1, "loop: add pending repro for '%v'", crash.desc)
+				pendingRepro[crash] = true
+			}
 		case reply := <-mgr.needMoreRepros:
 			reply <- phase >= phaseTriagedHub &&
 				len(reproQueue)+len(pendingRepro)+len(reproducing) == 0
@@ -490,8 +742,18 @@ func (mgr *Manager) vmLoop() {
 	}
 }
 
+func (mgr *Manager) setVMState(indexes []int, state string) {
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	for _, idx := range indexes {
+		mgr.vms[idx].State = state
+		mgr.vms[idx].Since = time.Now()
+	}
+}
+
 func (mgr *Manager) runInstance(index int) (*Crash, error) {
 	mgr.checkUsedFiles()
+	mgr.setVMState([]int{index}, vmBooting)
 	inst, err := mgr.vmPool.Create(index)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create instance: %v", err)
@@ -525,15 +787,20 @@ func (mgr *Manager) runInstance(index int) (*Crash, error) {
 	atomic.AddUint32(&mgr.numFuzzing, 1)
 	defer atomic.AddUint32(&mgr.numFuzzing, ^uint32(0))
 	cmd := fmt.Sprintf("%v -executor=%v -name=vm-%v -arch=%v -manager=%v -procs=%v"+
-		" -leak=%v -cover=%v -sandbox=%v -debug=%v -v=%d",
+		" -leak=%v -cover=%v -signal=%v -sandbox=%v -debug=%v -v=%d"+
+		" -cgroups=%v -memory_limit=%v -pids_limit=%v -fds_limit=%v -cpu_limit=%vs -string_comps=%v"+
+		" -schedule=%v -power=%v -cache=%v -race=%v",
 		fuzzerBin, executorBin, index, mgr.cfg.TargetArch, fwdAddr, procs,
-		leak, mgr.cfg.Cover, mgr.cfg.Sandbox, *flagDebug, fuzzerV)
+		leak, mgr.cfg.Cover, mgr.cfg.Signal, mgr.cfg.Sandbox, *flagDebug, fuzzerV,
+		mgr.cfg.Cgroups, mgr.cfg.Memory_Limit, mgr.cfg.Pids_Limit, mgr.cfg.Fds_Limit, mgr.cfg.Cpu_Limit,
+		mgr.cfg.String_Comps, mgr.cfg.Schedule, mgr.cfg.Power, mgr.cfg.Fuzzer_Cache, mgr.cfg.Race)
 	outc, errc, err := inst.Run(time.Hour, mgr.vmStop, cmd)
 	if err != nil {
 		return nil, fmt.Errorf("failed to run fuzzer: %v", err)
 	}
+	mgr.setVMState([]int{index}, vmFuzzing)
 
-	desc, text, output, crashed, timedout := vm.MonitorExecution(outc, errc, true, mgr.cfg.ParsedIgnores)
+	desc, text, output, crashed, timedout := vm.MonitorExecution(outc, errc, true, mgr.getIgnores())
 	if timedout {
 		// This is the only "OK" outcome.
 		Logf(0, "vm-%v: running for %v, restarting (%v)", index, time.Since(start), desc)
@@ -543,6 +810,22 @@ func (mgr *Manager) runInstance(index int) (*Crash, error) {
 		// syz-fuzzer exited, but it should not.
 		desc = "lost connection to test machine"
 	}
+	if len(text) == 0 {
+		// No kernel report, check if fuzzer has exited due to executor failure.
+		if match := executorFailureRe.FindSubmatchIndex(output); match != nil {
+			kind := string(output[match[2]:match[3]])
+			mgr.mu.Lock()
+			mgr.stats["executor failure "+kind]++
+			mgr.mu.Unlock()
+			if !executorFailureIsCrash(kind) {
+				Logf(0, "vm-%v: executor %v failure, restarting", index, kind)
+				return nil, nil
+			}
+			desc = "executor failure: " + kind
+			// The failure message follows the marker.
+			text = output[match[0]:]
+		}
+	}
 	cash := &Crash{
 		vmIndex: index,
 		hub:     false,
@@ -553,8 +836,27 @@ func (mgr *Manager) runInstance(index int) (*Crash, error) {
 	return cash, nil
 }
 
+// executorFailureRe matches the line that syz-fuzzer prints before exiting due to an executor failure.
+var executorFailureRe = regexp.MustCompile(`SYZ-FUZZER: EXECUTOR FAILURE \(([a-z]+)\)`)
+
+// executorFailureIsCrash decides whether an executor failure of the given kind
+// is likely caused by a kernel bug, or is an infrastructure problem.
+func executorFailureIsCrash(kind string) bool {
+	switch kind {
+	case "setup", "shmem", "kcov", "sandbox":
+		// Broken image, missing devices or resource exhaustion in the VM.
+		return false
+	default:
+		// Internal asserts and executor hangs on start are frequently caused by kernel misbehavior.
+		return true
+	}
+}
+
 func (mgr *Manager) isSuppressed(crash *Crash) bool {
-	for _, re := range mgr.cfg.ParsedSuppressions {
+	mgr.mu.Lock()
+	suppressions := append(append([]*regexp.Regexp{}, mgr.cfg.ParsedSuppressions...), mgr.suppressions...)
+	mgr.mu.Unlock()
+	for _, re := range suppressions {
 		if !re.Match(crash.log) {
 			continue
 		}
@@ -569,15 +871,37 @@ func (mgr *Manager) isSuppressed(crash *Crash) bool {
 
 func (mgr *Manager) saveCrash(crash *Crash) bool {
 	Logf(0, "vm-%v: crash: %v", crash.vmIndex, crash.desc)
+	entries := mgr.target.ParseLog(crash.log)
 	mgr.mu.Lock()
 	mgr.stats["crashes"]++
+	mgr.crashClasses[crashClass(crash.desc)]++
 	if !mgr.crashTypes[crash.desc] {
 		mgr.crashTypes[crash.desc] = true
 		mgr.stats["crash types"]++
 	}
+	mgr.campaignCrash(crash.desc)
+	crashCalls := make(map[string]bool)
+	crashRaces := make(map[racePairKey]bool)
+	for _, ent := range entries {
+		for _, c := range ent.P.Calls {
+			crashCalls[c.Meta.Name] = true
+		}
+		if ent.Race && ent.RaceCall1 < len(ent.P.Calls) && ent.RaceCall2 < len(ent.P.Calls) {
+			crashRaces[racePairKey{ent.P.Calls[ent.RaceCall1].Meta.Name,
+				ent.P.Calls[ent.RaceCall2].Meta.Name}] = true
+		}
+	}
+	for call := range crashCalls {
+		mgr.getCallStats(call).Crashes++
+	}
+	for key := range crashRaces {
+		mgr.getRacePair(key.call1, key.call2).Crashes++
+	}
 	mgr.mu.Unlock()
 
 	crash.report = mgr.symbolizeReport(crash.report)
+	frames := report.StackFrames(crash.report)
+	signature := report.StackSignature(crash.report)
 	if mgr.dash != nil {
 		var maintainers []string
 		guiltyFile := report.ExtractGuiltyFile(crash.report)
@@ -591,6 +915,7 @@ func (mgr *Manager) saveCrash(crash *Crash) bool {
 		dc := &dashapi.Crash{
 			BuildID:     mgr.cfg.Tag,
 			Title:       crash.desc,
+			Signature:   signature,
 			Maintainers: maintainers,
 			Log:         crash.log,
 			Report:      crash.report,
@@ -612,13 +937,19 @@ func (mgr *Manager) saveCrash(crash *Crash) bool {
 	if err := osutil.WriteFile(filepath.Join(dir, "description"), []byte(crash.desc+"\n")); err != nil {
 		Logf(0, "failed to write crash: %v", err)
 	}
-	// Save up to 100 reports. If we already have 100, overwrite the oldest one.
+	// Save up to crash_max_logs reports. If we already have that many, overwrite the oldest one.
 	// Newer reports are generally more useful. Overwriting is also needed
 	// to be able to understand if a particular bug still happens or already fixed.
+	// Logs can be compressed by janitor.
+	mgr.crashMu.Lock()
+	defer mgr.crashMu.Unlock()
 	oldestI := 0
 	var oldestTime time.Time
-	for i := 0; i < 100; i++ {
+	for i := 0; i < mgr.cfg.Crash_Max_Logs; i++ {
 		info, err := os.Stat(filepath.Join(dir, fmt.Sprintf("log%v", i)))
+		if err != nil {
+			info, err = os.Stat(filepath.Join(dir, fmt.Sprintf("log%v.gz", i)))
+		}
 		if err != nil {
 			oldestI = i
 			break
@@ -628,6 +959,7 @@ func (mgr *Manager) saveCrash(crash *Crash) bool {
 			oldestTime = info.ModTime()
 		}
 	}
+	os.Remove(filepath.Join(dir, fmt.Sprintf("log%v.gz", oldestI)))
 	osutil.WriteFile(filepath.Join(dir, fmt.Sprintf("log%v", oldestI)), crash.log)
 	if len(mgr.cfg.Tag) > 0 {
 		osutil.WriteFile(filepath.Join(dir, fmt.Sprintf("tag%v", oldestI)), []byte(mgr.cfg.Tag))
@@ -635,6 +967,14 @@ func (mgr *Manager) saveCrash(crash *Crash) bool {
 	if len(crash.report) > 0 {
 		osutil.WriteFile(filepath.Join(dir, fmt.Sprintf("report%v", oldestI)), crash.report)
 	}
+	// Signature file contains the stack signature followed by the frames it is computed from.
+	signatureFile := filepath.Join(dir, fmt.Sprintf("signature%v", oldestI))
+	if signature != "" {
+		data := strings.Join(append([]string{signature}, frames...), "\n") + "\n"
+		osutil.WriteFile(signatureFile, []byte(data))
+	} else {
+		os.Remove(signatureFile)
+	}
 
 	return mgr.needRepro(crash.desc)
 }
@@ -740,6 +1080,7 @@ func (mgr *Manager) saveRepro(res *repro.Result, hub bool) {
 		dc := &dashapi.Crash{
 			BuildID:     mgr.cfg.Tag,
 			Title:       res.Desc,
+			Signature:   report.StackSignature(res.Report),
 			Maintainers: maintainers,
 			Log:         res.Log,
 			Report:      res.Report,
@@ -771,7 +1112,12 @@ func (mgr *Manager) minimizeCorpus() {
 		var cov []cover.Cover
 		var inputs []RpcInput
 		for _, inp := range mgr.corpus {
-			cov = append(cov, inp.Signal)
+			signal := inp.Signal
+			if len(mgr.noisySignal) != 0 {
+				// Noisy signal must not keep inputs in corpus.
+				signal = cover.SignalDiff(mgr.noisySignal, signal)
+			}
+			cov = append(cov, signal)
 			inputs = append(inputs, inp)
 		}
 		newCorpus := make(map[string]RpcInput)
@@ -793,9 +1139,65 @@ func (mgr *Manager) minimizeCorpus() {
 			}
 		}
 		mgr.corpusDB.Flush()
+		// The stats database is flushed by saveInputStats.
+		mgr.inputStatsMu.Lock()
+		for key := range mgr.inputStatsDB.Records {
+			if _, ok := mgr.corpusDB.Records[key]; !ok {
+				mgr.inputStatsDB.Delete(key)
+			}
+		}
+		mgr.inputStatsMu.Unlock()
 	}
 }
 
+// inputStats is the format of power schedule counters in the corpus stats database.
+type inputStats struct {
+	Chosen uint64
+	Found  uint64
+}
+
+func (mgr *Manager) loadInputStats(sig string) (chosen, found uint64) {
+	mgr.inputStatsMu.Lock()
+	rec, ok := mgr.inputStatsDB.Records[sig]
+	mgr.inputStatsMu.Unlock()
+	if !ok {
+		return 0, 0
+	}
+	var stats inputStats
+	if err := json.Unmarshal(rec.Val, &stats); err != nil {
+		Logf(0, "failed to parse stats of input %v: %v", sig, err)
+		return 0, 0
+	}
+	return stats.Chosen, stats.Found
+}
+
+// saveInputStats writes counters of inputs changed since the previous call to the stats database.
+// Every poll changes counters of many inputs, so flushes can compact the whole database
+// and are done periodically outside of mu. An input deleted concurrently can be saved again,
+// such records are removed by minimizeCorpus.
+func (mgr *Manager) saveInputStats() error {
+	mgr.mu.Lock()
+	stats := make(map[string]inputStats, len(mgr.inputsDirty))
+	for sig := range mgr.inputsDirty {
+		if inp, ok := mgr.corpus[sig]; ok {
+			stats[sig] = inputStats{inp.Chosen, inp.Found}
+		}
+	}
+	mgr.inputsDirty = make(map[string]bool)
+	mgr.mu.Unlock()
+
+	mgr.inputStatsMu.Lock()
+	defer mgr.inputStatsMu.Unlock()
+	for sig, st := range stats {
+		data, err := json.Marshal(st)
+		if err != nil {
+			panic(err)
+		}
+		mgr.inputStatsDB.Save(sig, data, 0)
+	}
+	return mgr.inputStatsDB.Flush()
+}
+
 func (mgr *Manager) Connect(a *ConnectArgs, r *ConnectRes) error {
 	Logf(1, "fuzzer %v connected", a.Name)
 	mgr.mu.Lock()
@@ -808,7 +1210,8 @@ func (mgr *Manager) Connect(a *ConnectArgs, r *ConnectRes) error {
 
 	mgr.stats["vm restarts"]++
 	f := &Fuzzer{
-		name: a.Name,
+		name:         a.Name,
+		callsVersion: mgr.callsVersion,
 	}
 	mgr.fuzzers[a.Name] = f
 	mgr.minimizeCorpus()
@@ -837,17 +1240,46 @@ func (mgr *Manager) Connect(a *ConnectArgs, r *ConnectRes) error {
 	}
 
 	f.inputs = nil
-	for _, inp := range mgr.corpus {
-		r.Inputs = append(r.Inputs, inp)
+	cached := make(map[string]bool, len(a.CachedInputs))
+	for _, sig := range a.CachedInputs {
+		cached[sig] = true
+		if _, ok := mgr.corpus[sig]; !ok {
+			r.DroppedInputs = append(r.DroppedInputs, sig)
+		}
+	}
+	for sig, inp := range mgr.corpus {
+		if !cached[sig] {
+			r.Inputs = append(r.Inputs, inp)
+		}
+	}
+	for point, pc := range mgr.faultPoints {
+		r.FaultSites = append(r.FaultSites, RpcFaultSite{PC: pc, Call: point.call, Nth: point.nth})
+	}
+	for site := range mgr.leakSites {
+		r.LeakSites = append(r.LeakSites, site)
+	}
+	for s := range mgr.noisySignal {
+		r.NoisySignal = append(r.NoisySignal, s)
 	}
 	r.Prios = mgr.prios
+	if mgr.focus != nil {
+		r.Prios = mgr.focus.boostPrios(mgr.target, mgr.prios, mgr.corpus)
+		r.FocusWeights = mgr.focus.weights
+		f.focusSent = true
+	}
 	r.EnabledCalls = mgr.enabledSyscalls
+	r.Paused = mgr.overrides.Paused
 	r.NeedCheck = !mgr.vmChecked
-	r.MaxSignal = make([]uint32, 0, len(mgr.maxSignal))
-	for s := range mgr.maxSignal {
-		r.MaxSignal = append(r.MaxSignal, s)
+	r.Run = mgr.startTime.UnixNano()
+	if a.CachedRun == r.Run {
+		r.MaxSignal = mgr.maxSignalSince(a.CachedMaxSignal)
+	} else {
+		r.MaxSignal = mgr.allMaxSignal()
 	}
+	r.MaxSignalLog = mgr.maxSignalStart + len(mgr.maxSignalLog)
 	f.newMaxSignal = nil
+	Logf(1, "fuzzer %v: %v cached inputs, sending %v inputs, %v max signal",
+		a.Name, len(a.CachedInputs)-len(r.DroppedInputs), len(r.Inputs), len(r.MaxSignal))
 	for i := 0; i < mgr.cfg.Procs && len(mgr.candidates) > 0; i++ {
 		last := len(mgr.candidates) - 1
 		r.Candidates = append(r.Candidates, mgr.candidates[last])
@@ -904,6 +1336,7 @@ func (mgr *Manager) NewInput(a *NewInputArgs, r *int) error {
 		Fatalf("fuzzer %v is not connected", a.Name)
 	}
 
+	a.Signal = cover.SignalDiff(mgr.noisySignal, a.Signal)
 	if !cover.SignalNew(mgr.corpusSignal, a.Signal) {
 		return nil
 	}
@@ -917,7 +1350,10 @@ func (mgr *Manager) NewInput(a *NewInputArgs, r *int) error {
 		inp.Cover = cover.Union(inp.Cover, a.RpcInput.Cover)
 		mgr.corpus[sig] = inp
 	} else {
-		mgr.corpus[sig] = a.RpcInput
+		// The input may be re-added from the persistent corpus, restore its counters.
+		inp := a.RpcInput
+		inp.Chosen, inp.Found = mgr.loadInputStats(sig)
+		mgr.corpus[sig] = inp
 		mgr.corpusDB.Save(sig, a.RpcInput.Prog, 0)
 		if err := mgr.corpusDB.Flush(); err != nil {
 			Logf(0, "failed to save corpus database: %v", err)
@@ -926,8 +1362,12 @@ func (mgr *Manager) NewInput(a *NewInputArgs, r *int) error {
 			if f1 == f {
 				continue
 			}
-			inp := a.RpcInput
+			inp := mgr.corpus[sig]
 			inp.Cover = nil // Don't send coverage back to all fuzzers.
+			if mgr.focus != nil {
+				// But fuzzers need coverage close to focus targets to weight the input.
+				inp.Cover = mgr.focus.filter(a.Cover)
+			}
 			f1.inputs = append(f1.inputs, inp)
 		}
 	}
@@ -941,6 +1381,36 @@ func (mgr *Manager) Poll(a *PollArgs, r *PollRes) error {
 	for k, v := range a.Stats {
 		mgr.stats[k] += v
 	}
+	mgr.fuzzerExecs[a.Name] += a.Stats["exec total"]
+	for _, stat := range a.InputStats {
+		inp, ok := mgr.corpus[stat.Sig]
+		if !ok {
+			continue
+		}
+		inp.Chosen += stat.Chosen
+		inp.Found += stat.Found
+		mgr.corpus[stat.Sig] = inp
+		mgr.inputsDirty[stat.Sig] = true
+	}
+	mgr.addFaultSites(a.Name, a.FaultSites)
+	mgr.addFlakySignal(a.FlakySignal)
+	mgr.addLeaks(a.Name, a.Leaks)
+	for call, stats := range a.CallStats {
+		cs := mgr.getCallStats(call)
+		cs.Execs += stats.Execs
+		cs.NewSignal += stats.NewSignal
+		for errno, n := range stats.Errnos {
+			cs.Errnos[errno] += n
+		}
+	}
+	for call, usage := range a.CallUsage {
+		mgr.addCallUsage(call, usage)
+	}
+	for _, pair := range a.RacePairs {
+		rp := mgr.getRacePair(pair.Call1, pair.Call2)
+		rp.Execs += pair.Execs
+		rp.NewSignal += pair.NewSignal
+	}
 
 	f := mgr.fuzzers[a.Name]
 	if f == nil {
@@ -948,11 +1418,9 @@ func (mgr *Manager) Poll(a *PollArgs, r *PollRes) error {
 	}
 	var newMaxSignal []uint32
 	for _, s := range a.MaxSignal {
-		if _, ok := mgr.maxSignal[s]; ok {
-			continue
+		if mgr.addMaxSignal(s) {
+			newMaxSignal = append(newMaxSignal, s)
 		}
-		mgr.maxSignal[s] = struct{}{}
-		newMaxSignal = append(newMaxSignal, s)
 	}
 	for _, f1 := range mgr.fuzzers {
 		if f1 == f {
@@ -962,6 +1430,26 @@ func (mgr *Manager) Poll(a *PollArgs, r *PollRes) error {
 	}
 	r.MaxSignal = f.newMaxSignal
 	f.newMaxSignal = nil
+	r.MaxSignalLog = mgr.maxSignalStart + len(mgr.maxSignalLog)
+	r.FaultSites = f.faultSites
+	f.faultSites = nil
+	r.NoisySignal = f.noisySignal
+	f.noisySignal = nil
+	r.LeakSites = f.leakSites
+	f.leakSites = nil
+	if mgr.focus != nil && !f.focusSent {
+		r.FocusWeights = mgr.focus.weights
+		f.focusSent = true
+	}
+	r.Paused = mgr.overrides.Paused
+	if f.callsVersion != mgr.callsVersion {
+		f.callsVersion = mgr.callsVersion
+		r.EnabledCalls = mgr.enabledSyscalls
+		r.Prios = mgr.prios
+		if mgr.focus != nil {
+			r.Prios = mgr.focus.boostPrios(mgr.target, mgr.prios, mgr.corpus)
+		}
+	}
 	for i := 0; i < 100 && len(f.inputs) > 0; i++ {
 		last := len(f.inputs) - 1
 		r.NewInputs = append(r.NewInputs, f.inputs[last])
@@ -971,7 +1459,7 @@ func (mgr *Manager) Poll(a *PollArgs, r *PollRes) error {
 		f.inputs = nil
 	}
 
-	for i := 0; i < mgr.cfg.Procs && len(mgr.candidates) > 0; i++ {
+	for i := 0; i < mgr.cfg.Procs && len(mgr.candidates) > 0 && !r.Paused; i++ {
 		last := len(mgr.candidates) - 1
 		r.Candidates = append(r.Candidates, mgr.candidates[last])
 		mgr.candidates = mgr.candidates[:last]
@@ -991,6 +1479,212 @@ func (mgr *Manager) Poll(a *PollArgs, r *PollRes) error {
 	return nil
 }
 
+// addMaxSignal adds s to max signal, returns false if it is already there.
+func (mgr *Manager) addMaxSignal(s uint32) bool {
+	if _, ok := mgr.maxSignal[s]; ok {
+		return false
+	}
+	mgr.maxSignal[s] = struct{}{}
+	mgr.maxSignalLog = append(mgr.maxSignalLog, s)
+	if len(mgr.maxSignalLog) > maxSignalLogSize {
+		// Drop the older half, fuzzers with caches older than that get full max signal on connect.
+		drop := len(mgr.maxSignalLog) - maxSignalLogSize/2
+		mgr.maxSignalLog = append([]uint32{}, mgr.maxSignalLog[drop:]...)
+		mgr.maxSignalStart += drop
+	}
+	return true
+}
+
+// maxSignalSince returns max signal added after the first n entries of the log.
+// If these entries are already dropped from the log, it returns whole max signal.
+func (mgr *Manager) maxSignalSince(n int) []uint32 {
+	if n < mgr.maxSignalStart || n > mgr.maxSignalStart+len(mgr.maxSignalLog) {
+		return mgr.allMaxSignal()
+	}
+	return append([]uint32{}, mgr.maxSignalLog[n-mgr.maxSignalStart:]...)
+}
+
+func (mgr *Manager) allMaxSignal() []uint32 {
+	signal := make([]uint32, 0, len(mgr.maxSignal))
+	for s := range mgr.maxSignal {
+		signal = append(signal, s)
+	}
+	return signal
+}
+
+func (mgr *Manager) getCallStats(call string) *CallStats {
+	stats := mgr.callStats[call]
+	if stats == nil {
+		stats = &CallStats{Errnos: make(map[int]uint64), Limits: make(map[string]uint64)}
+		mgr.callStats[call] = stats
+	}
+	return stats
+}
+
+func (mgr *Manager) addCallUsage(call string, usage RpcCallUsage) {
+	cs := mgr.getCallStats(call)
+	if cs.MaxMemory < usage.MaxMemory {
+		cs.MaxMemory = usage.MaxMemory
+	}
+	if cs.MaxFds < usage.MaxFds {
+		cs.MaxFds = usage.MaxFds
+	}
+	if cs.MaxPids < usage.MaxPids {
+		cs.MaxPids = usage.MaxPids
+	}
+	for limit, n := range usage.Limits {
+		cs.Limits[limit] += n
+	}
+}
+
+func (mgr *Manager) getRacePair(call1, call2 string) *RacePair {
+	key := racePairKey{call1, call2}
+	pair := mgr.racePairs[key]
+	if pair == nil {
+		pair = &RacePair{Call1: call1, Call2: call2}
+		mgr.racePairs[key] = pair
+	}
+	return pair
+}
+
+// addFlakySignal accounts signal that was flaky in triage and marks it as noisy
+// once it reaches the configured threshold.
+func (mgr *Manager) addFlakySignal(flaky map[uint32]uint64) {
+	var noisy []uint32
+	for s, n := range flaky {
+		mgr.flakySignal[s] += n
+		if _, ok := mgr.noisySignal[s]; ok || mgr.cfg.Noisy_Signal == 0 ||
+			mgr.flakySignal[s] < uint64(mgr.cfg.Noisy_Signal) {
+			continue
+		}
+		mgr.noisySignal[s] = struct{}{}
+		mgr.addMaxSignal(s)
+		noisy = append(noisy, s)
+	}
+	if len(noisy) == 0 {
+		return
+	}
+	Logf(1, "%v new noisy signal", len(noisy))
+	for _, f := range mgr.fuzzers {
+		f.noisySignal = append(f.noisySignal, noisy...)
+	}
+}
+
+// addFaultSites accounts fault sites hit by fuzzer name and queues fault points
+// that hit new sites for other fuzzers, so that they don't inject faults into the same points.
+func (mgr *Manager) addFaultSites(name string, sites []RpcFaultSite) {
+	for _, site := range sites {
+		mgr.faultDirty = true
+		if s := mgr.faultSites[site.PC]; s != nil {
+			s.Hits += site.Hits
+		} else {
+			mgr.faultSites[site.PC] = &FaultSite{
+				Call:  site.Call,
+				Hits:  site.Hits,
+				Found: time.Now(),
+			}
+		}
+		point := faultPoint{site.Call, site.Nth}
+		if _, ok := mgr.faultPoints[point]; ok {
+			continue
+		}
+		mgr.faultPoints[point] = site.PC
+		for name1, f1 := range mgr.fuzzers {
+			if name1 != name {
+				f1.faultSites = append(f1.faultSites, RpcFaultSite{PC: site.PC, Call: site.Call, Nth: site.Nth})
+			}
+		}
+	}
+}
+
+// savedFaultSites is the format of workdir/faultsites.json.
+type savedFaultSites struct {
+	Sites  map[uint32]*FaultSite
+	Points []RpcFaultSite
+}
+
+func (mgr *Manager) faultSitesFile() string {
+	return filepath.Join(mgr.cfg.Workdir, "faultsites.json")
+}
+
+func (mgr *Manager) loadFaultSites() {
+	data, err := ioutil.ReadFile(mgr.faultSitesFile())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			Logf(0, "failed to read fault sites: %v", err)
+		}
+		return
+	}
+	saved := savedFaultSites{}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		Logf(0, "failed to parse fault sites: %v", err)
+		return
+	}
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	for pc, site := range saved.Sites {
+		mgr.faultSites[pc] = site
+	}
+	for _, site := range saved.Points {
+		mgr.faultPoints[faultPoint{site.Call, site.Nth}] = site.PC
+	}
+	Logf(0, "loaded %v fault sites", len(mgr.faultSites))
+}
+
+// saveFaultSites persists fault sites if they have changed since the previous call.
+func (mgr *Manager) saveFaultSites() error {
+	mgr.mu.Lock()
+	if !mgr.faultDirty {
+		mgr.mu.Unlock()
+		return nil
+	}
+	mgr.faultDirty = false
+	saved := savedFaultSites{Sites: mgr.faultSites}
+	for point, pc := range mgr.faultPoints {
+		saved.Points = append(saved.Points, RpcFaultSite{PC: pc, Call: point.call, Nth: point.nth})
+	}
+	data, err := json.Marshal(saved)
+	mgr.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return osutil.WriteFile(mgr.faultSitesFile(), data)
+}
+
+// addLeaks saves memory leaks in new allocation sites reported by fuzzer name as crashes
+// and queues the sites for other fuzzers, so that they suppress leaks in the same sites.
+func (mgr *Manager) addLeaks(name string, leaks []RpcLeak) {
+	for _, leak := range leaks {
+		if mgr.leakSites[leak.Site] {
+			continue
+		}
+		vmIndex := -1
+		fmt.Sscanf(name, "vm-%d", &vmIndex)
+		text := append([]byte("BUG: memory leak\n"), leak.Report...)
+		crash := &Crash{
+			vmIndex: vmIndex,
+			desc:    "memory leak in " + leak.Site,
+			report:  text,
+			log:     append(append([]byte{}, leak.Log...), text...),
+		}
+		// Poll is called with mgr.mu held, so we can't block on vmLoop here.
+		// The site is marked as known only if the leak is saved,
+		// so that a dropped leak is reported again when it happens next time.
+		select {
+		case mgr.leakQueue <- crash:
+		default:
+			Logf(0, "%v: dropping memory leak in %v, leak queue is full", name, leak.Site)
+			continue
+		}
+		mgr.leakSites[leak.Site] = true
+		for name1, f1 := range mgr.fuzzers {
+			if name1 != name {
+				f1.leakSites = append(f1.leakSites, leak.Site)
+			}
+		}
+	}
+}
+
 func (mgr *Manager) hubSync() {
 	mgr.mu.Lock()
 	defer mgr.mu.Unlock()
@@ -1027,19 +1721,22 @@ func (mgr *Manager) hubSync() {
 		// Hub.Connect request can be very large, so do it on a transient connection
 		// (rpc connection buffers never shrink).
 		// Also don't do hub rpc's under the mutex -- hub can be slow or inaccessible.
-		if err := RpcCall(mgr.cfg.Hub_Addr, "Hub.Connect", a, nil); err != nil {
+		if err := RpcCall(mgr.cfg.Hub_Addr, false, "Hub.Connect", a, nil); err != nil {
 			mgr.mu.Lock()
 			Logf(0, "Hub.Connect rpc failed: %v", err)
+			mgr.setHubError(fmt.Errorf("Hub.Connect rpc failed: %v", err))
 			return
 		}
-		conn, err := NewRpcClient(mgr.cfg.Hub_Addr)
+		conn, err := NewRpcClient(mgr.cfg.Hub_Addr, false)
 		if err != nil {
 			mgr.mu.Lock()
 			Logf(0, "failed to connect to hub at %v: %v", mgr.cfg.Hub_Addr, err)
+			mgr.setHubError(fmt.Errorf("failed to connect to hub at %v: %v", mgr.cfg.Hub_Addr, err))
 			return
 		}
 		mgr.mu.Lock()
 		mgr.hub = conn
+		mgr.hubStatus.Connected = true
 		mgr.hubCorpus = hubCorpus
 		mgr.fresh = false
 		Logf(0, "connected to hub at %v, corpus %v", mgr.cfg.Hub_Addr, len(mgr.corpus))
@@ -1082,8 +1779,10 @@ func (mgr *Manager) hubSync() {
 		if err := mgr.hub.Call("Hub.Sync", a, r); err != nil {
 			mgr.mu.Lock()
 			Logf(0, "Hub.Sync rpc failed: %v", err)
+			mgr.setHubError(fmt.Errorf("Hub.Sync rpc failed: %v", err))
 			mgr.hub.Close()
 			mgr.hub = nil
+			mgr.hubStatus.Connected = false
 			return
 		}
 
@@ -1123,6 +1822,7 @@ func (mgr *Manager) hubSync() {
 		mgr.stats["hub new"] += uint64(len(r.Progs) - dropped)
 		mgr.stats["hub sent repros"] += uint64(len(a.Repros))
 		mgr.stats["hub recv repros"] += uint64(len(r.Repros) - reproDropped)
+		mgr.hubStatus.LastSync = time.Now()
 		Logf(0, "hub sync: send: add %v, del %v, repros %v; recv: progs: drop %v, new %v, repros: drop: %v, new %v; more %v",
 			len(a.Add), len(a.Del), len(a.Repros), dropped, len(r.Progs)-dropped, reproDropped, len(r.Repros)-reproDropped, r.More)
 		if len(r.Progs)+r.More == 0 {
@@ -1133,6 +1833,12 @@ func (mgr *Manager) hubSync() {
 	}
 }
 
+// setHubError records a failed hub sync, must be called with mgr.mu held.
+func (mgr *Manager) setHubError(err error) {
+	mgr.hubStatus.LastError = err.Error()
+	mgr.hubStatus.LastErrorTime = time.Now()
+}
+
 func (mgr *Manager) collectUsedFiles() {
 	addUsedFile := func(f string) {
 		if f == "" {
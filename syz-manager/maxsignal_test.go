@@ -0,0 +1,54 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+)
+
+func TestMaxSignalSince(t *testing.T) {
+	mgr := &Manager{maxSignal: make(map[uint32]struct{})}
+	for s := uint32(0); s < 10; s++ {
+		mgr.addMaxSignal(s)
+	}
+	if mgr.addMaxSignal(5) {
+		t.Fatalf("existing max signal is added again")
+	}
+	tests := []struct {
+		cached int
+		want   int // number of signal entries sent
+	}{
+		{0, 10},
+		{3, 7},
+		{10, 0},
+		{11, 10}, // fuzzer has more than manager, send everything
+	}
+	for _, test := range tests {
+		if got := mgr.maxSignalSince(test.cached); len(got) != test.want {
+			t.Errorf("cached %v: got %v signal, want %v", test.cached, len(got), test.want)
+		}
+	}
+	if got := mgr.maxSignalSince(3); got[0] != 3 || got[len(got)-1] != 9 {
+		t.Errorf("bad delta signal: %v", got)
+	}
+}
+
+func TestMaxSignalLogCap(t *testing.T) {
+	mgr := &Manager{maxSignal: make(map[uint32]struct{})}
+	for s := uint32(0); s <= maxSignalLogSize; s++ {
+		mgr.addMaxSignal(s)
+	}
+	total := maxSignalLogSize + 1
+	if len(mgr.maxSignalLog) > maxSignalLogSize || mgr.maxSignalStart+len(mgr.maxSignalLog) != total {
+		t.Fatalf("bad max signal log: start %v, len %v", mgr.maxSignalStart, len(mgr.maxSignalLog))
+	}
+	// Delta for entries that are still in the log.
+	if got := mgr.maxSignalSince(total - 1); len(got) != 1 || got[0] != uint32(total-1) {
+		t.Fatalf("bad delta signal: %v", got)
+	}
+	// Entries that are dropped from the log give full max signal.
+	if got := mgr.maxSignalSince(mgr.maxSignalStart - 1); len(got) != total {
+		t.Fatalf("got %v signal, want %v", len(got), total)
+	}
+}
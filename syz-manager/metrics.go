@@ -0,0 +1,49 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/syzkaller/pkg/stats"
+)
+
+func (mgr *Manager) initMetrics() *stats.Registry {
+	reg := new(stats.Registry)
+	reg.Register(mgr.collectMetrics)
+	return reg
+}
+
+func (mgr *Manager) collectMetrics(set *stats.Set) {
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	set.Add(stats.ManagerUptime, time.Since(mgr.startTime).Seconds())
+	set.Add(stats.FuzzingTime, mgr.fuzzingTime.Seconds())
+	set.Add(stats.CorpusSize, float64(len(mgr.corpus)))
+	set.Add(stats.SignalSize, float64(len(mgr.corpusSignal)))
+	set.Add(stats.CoverageSize, float64(len(mgr.corpusCover)))
+	set.Add(stats.VMRestarts, float64(mgr.stats["vm restarts"]))
+	for name, v := range mgr.stats {
+		set.Add(stats.ManagerCounter, float64(v), name)
+	}
+	for name, v := range mgr.fuzzerExecs {
+		set.Add(stats.FuzzerExecs, float64(v), name)
+	}
+	for class, v := range mgr.crashClasses {
+		set.Add(stats.Crashes, float64(v), class)
+	}
+}
+
+// crashClass returns a coarse class of a crash title that is suitable as a metric label,
+// e.g. "KASAN" for "KASAN: use-after-free Read in foo" or "WARNING" for "WARNING in foo".
+func crashClass(desc string) string {
+	if pos := strings.IndexByte(desc, ':'); pos != -1 {
+		return desc[:pos]
+	}
+	if pos := strings.Index(desc, " in "); pos != -1 {
+		return desc[:pos]
+	}
+	return desc
+}
@@ -0,0 +1,26 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+)
+
+func TestCrashClass(t *testing.T) {
+	tests := map[string]string{
+		"KASAN: use-after-free Read in foo":   "KASAN",
+		"WARNING in foo":                      "WARNING",
+		"INFO: task hung in foo":              "INFO",
+		"general protection fault in foo":     "general protection fault",
+		"memory leak in foo":                  "memory leak",
+		"lost connection to test machine":     "lost connection to test machine",
+		"unexpected kernel reboot":            "unexpected kernel reboot",
+		"BUG: unable to handle kernel paging": "BUG",
+	}
+	for desc, want := range tests {
+		if got := crashClass(desc); got != want {
+			t.Errorf("crashClass(%q) = %q, want %q", desc, got, want)
+		}
+	}
+}
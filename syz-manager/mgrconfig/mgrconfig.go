@@ -8,9 +8,11 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/google/syzkaller/pkg/config"
+	"github.com/google/syzkaller/pkg/cover"
 	"github.com/google/syzkaller/pkg/osutil"
 	"github.com/google/syzkaller/prog"
 	_ "github.com/google/syzkaller/sys"
@@ -21,6 +23,7 @@ type Config struct {
 	Name       string // Instance name (used for identification and as GCE instance prefix)
 	Target     string // Target OS/arch, e.g. "linux/arm64" or "linux/amd64/386" (amd64 OS with 386 test process)
 	Http       string // TCP address to serve HTTP stats page (e.g. "localhost:50000")
+	Http_Key   string // key for HTTP requests that access corpus or modify manager state (optional)
 	Rpc        string // TCP address to serve RPC for fuzzer processes (optional)
 	Workdir    string
 	Vmlinux    string
@@ -51,17 +54,93 @@ type Config struct {
 	Leak      bool // do memory leak checking
 	Reproduce bool // reproduce, localize and minimize crashers (on by default)
 
+	Signal string // how to derive feedback signal from coverage:
+	// "executor": code edges computed by executor, default
+	// "edge": the same edges, but computed by fuzzer from raw coverage traces
+	// "pc": basic block PCs
+	// "pc-errno": basic block PCs mixed with call errno
+	// "call-edge": code edges mixed with syscall ID
+
+	// Per-program resource limits (0 means no limit).
+	Cgroups      bool // use cgroups to account and limit memory and pids of test programs
+	Memory_Limit int  // memory limit in MB (requires cgroups, otherwise can only lower the sandbox limit)
+	Pids_Limit   int  // limit on number of tasks (requires cgroups)
+	Fds_Limit    int  // limit on number of open fds
+	Cpu_Limit    int  // CPU time limit in seconds
+
+	String_Comps string // source of byte-string comparison operands for hints:
+	// "none": don't collect them, default
+	// "kprobe": trace arguments of strcmp/strncmp/memcmp in kernel with kprobes
+
+	Schedule string // how fuzzer chooses between generation, mutation, smashing, hints and fault injection:
+	// "fixed": fixed rules, default
+	// "adaptive": multi-armed bandit rewarded with new signal per execution
+
+	Power string // power schedule, how fuzzer chooses corpus programs for mutation:
+	// "uniform": all programs with equal probability, default
+	// "fast": prefer programs whose mutants found new signal and short programs
+	// "explore": prefer programs that were chosen the least number of times
+	// "rarity": prefer programs with rare signal
+
 	Enable_Syscalls  []string
 	Disable_Syscalls []string
 	Suppressions     []string // don't save reports matching these regexps, but reboot VM after them
 	Ignores          []string // completely ignore reports matching these regexps (don't save nor reboot)
 
+	// Directed fuzzing targets, fuzzer prefers programs and syscalls with coverage close to them.
+	// Each entry is a function name ("tcp_sendmsg"), a source file ("net/ipv4/tcp.c"),
+	// a source line ("net/ipv4/tcp.c:1200") or a line range ("net/ipv4/tcp.c:1200-1250").
+	// Requires vmlinux with debug info.
+	Focus []string
+
+	// Signal that was flaky during triage (appeared in some runs of an input, but not in others)
+	// this number of times across all fuzzers is considered noisy and excluded from novelty checks
+	// (e.g. coverage of timers, RCU and workqueues). 0 disables the filter.
+	Noisy_Signal int
+
+	// Path to fuzzer-local corpus cache on the test machine (optional).
+	// Should be on a disk that persists across VM restarts (e.g. with isolated or adb VMs),
+	// then on restart manager sends fuzzer only corpus inputs and max signal it does not have yet.
+	Fuzzer_Cache string
+
+	// Execute pairs of calls that share coverage or resources concurrently
+	// with random delays and CPU pinning to trigger data races (Linux only).
+	Race bool
+
+	// Re-run stored crash reproducers on start this number of times each to check whether
+	// the crashes still reproduce on the new kernel (0 disables). Every reproducer is retested
+	// once per kernel build identified by Tag (on every start if Tag is empty).
+	Retest_Repros int
+
+	// Retention policy for workdir/crashes enforced by a background janitor (0 means no limit).
+	// The latest log of every crash is always kept.
+	Crashes_Max_Size   int // max total size of crashes in MB, the oldest logs (including repro logs) are removed first
+	Crash_Max_Logs     int // max number of logs saved per crash title (100 by default)
+	Crash_Max_Age      int // remove logs older than this number of days
+	Crash_Compress_Age int // gzip logs older than this number of days
+
+	// Save symbolized corpus coverage to workdir/coversnap every this number of minutes
+	// (0 disables, requires Vmlinux). Snapshots can be compared on /cover?diff=A..B.
+	Cover_Snapshot_Period int
+	// Max number of coverage snapshots to keep, the oldest are removed first (0 means no limit).
+	Cover_Snapshot_Max int
+
+	// Campaign mode (enabled if any of the params is set): fuzz until the budget is exhausted
+	// or a stop condition is met, wait for in-progress repros, write a report to workdir/campaign
+	// and exit with a status code that tells whether new crashes were found.
+	Campaign_Duration      int      // budget in minutes since manager start
+	Campaign_Execs         uint64   // budget in executed programs
+	Campaign_Stop_No_Cover int      // stop if corpus coverage has not grown for this number of minutes
+	Campaign_Stop_Crashes  []string // regexps of crash titles that stop the campaign
+
 	Type string          // VM type (qemu, kvm, local)
 	VM   json.RawMessage // VM-type-specific config
 
 	// Implementation details beyond this point.
 	ParsedSuppressions []*regexp.Regexp `json:"-"`
 	ParsedIgnores      []*regexp.Regexp `json:"-"`
+	ParsedStopCrashes  []*regexp.Regexp `json:"-"`
+	ParsedFocus        []FocusTarget    `json:"-"`
 	// Parsed Target:
 	TargetOS     string `json:"-"`
 	TargetArch   string `json:"-"`
@@ -86,8 +165,11 @@ func DefaultValues() *Config {
 		Cover:     true,
 		Reproduce: true,
 		Sandbox:   "setuid",
+		Signal:    cover.DefaultSignal,
 		Rpc:       ":0",
 		Procs:     1,
+
+		Crash_Max_Logs: 100,
 	}
 }
 
@@ -145,6 +227,36 @@ func load(data []byte, filename string) (*Config, error) {
 	default:
 		return nil, fmt.Errorf("config param sandbox must contain one of none/setuid/namespace")
 	}
+	if _, err := cover.MakeSignalFunc(cfg.Signal); err != nil {
+		return nil, fmt.Errorf("bad config param signal: %v", err)
+	}
+	if cfg.Memory_Limit < 0 || cfg.Pids_Limit < 0 || cfg.Fds_Limit < 0 || cfg.Cpu_Limit < 0 {
+		return nil, fmt.Errorf("resource limits can't be negative")
+	}
+	if cfg.Pids_Limit != 0 && !cfg.Cgroups {
+		return nil, fmt.Errorf("pids_limit requires cgroups")
+	}
+	switch cfg.String_Comps {
+	case "":
+		cfg.String_Comps = "none"
+	case "none", "kprobe":
+	default:
+		return nil, fmt.Errorf("config param string_comps must contain one of none/kprobe")
+	}
+	switch cfg.Schedule {
+	case "":
+		cfg.Schedule = "fixed"
+	case "fixed", "adaptive":
+	default:
+		return nil, fmt.Errorf("config param schedule must contain one of fixed/adaptive")
+	}
+	switch cfg.Power {
+	case "":
+		cfg.Power = "uniform"
+	case "uniform", "fast", "explore", "rarity":
+	default:
+		return nil, fmt.Errorf("config param power must contain one of uniform/fast/explore/rarity")
+	}
 
 	cfg.Workdir = osutil.Abs(cfg.Workdir)
 	cfg.Vmlinux = osutil.Abs(cfg.Vmlinux)
@@ -156,6 +268,27 @@ func load(data []byte, filename string) (*Config, error) {
 	if err := parseSuppressions(cfg); err != nil {
 		return nil, err
 	}
+	if err := parseFocus(cfg); err != nil {
+		return nil, err
+	}
+	if cfg.Noisy_Signal < 0 {
+		return nil, fmt.Errorf("config param noisy_signal must not be negative")
+	}
+	if cfg.Retest_Repros < 0 {
+		return nil, fmt.Errorf("config param retest_repros must not be negative")
+	}
+	if cfg.Crash_Max_Logs <= 0 {
+		return nil, fmt.Errorf("config param crash_max_logs must be positive")
+	}
+	if cfg.Crashes_Max_Size < 0 || cfg.Crash_Max_Age < 0 || cfg.Crash_Compress_Age < 0 {
+		return nil, fmt.Errorf("config params crashes_max_size/crash_max_age/crash_compress_age must not be negative")
+	}
+	if cfg.Cover_Snapshot_Period < 0 || cfg.Cover_Snapshot_Max < 0 {
+		return nil, fmt.Errorf("config params cover_snapshot_period/cover_snapshot_max must not be negative")
+	}
+	if cfg.Campaign_Duration < 0 || cfg.Campaign_Stop_No_Cover < 0 {
+		return nil, fmt.Errorf("config params campaign_duration/campaign_stop_no_cover must not be negative")
+	}
 
 	if cfg.Hub_Client != "" && (cfg.Name == "" || cfg.Hub_Addr == "" || cfg.Hub_Key == "") {
 		return nil, fmt.Errorf("hub_client is set, but name/hub_addr/hub_key is empty")
@@ -186,17 +319,20 @@ func SplitTarget(target string) (string, string, string, error) {
 	return os, vmarch, arch, nil
 }
 
-func ParseEnabledSyscalls(cfg *Config) (map[int]bool, error) {
-	match := func(call *prog.Syscall, str string) bool {
-		if str == call.CallName || str == call.Name {
-			return true
-		}
-		if len(str) > 1 && str[len(str)-1] == '*' && strings.HasPrefix(call.Name, str[:len(str)-1]) {
-			return true
-		}
-		return false
+// MatchSyscall returns whether call matches str from enable_syscalls/disable_syscalls:
+// either a syscall name, or a call name (e.g. "open" for "open$dir"),
+// or a name prefix followed by "*".
+func MatchSyscall(call *prog.Syscall, str string) bool {
+	if str == call.CallName || str == call.Name {
+		return true
 	}
+	if len(str) > 1 && str[len(str)-1] == '*' && strings.HasPrefix(call.Name, str[:len(str)-1]) {
+		return true
+	}
+	return false
+}
 
+func ParseEnabledSyscalls(cfg *Config) (map[int]bool, error) {
 	target, err := prog.GetTarget(cfg.TargetOS, cfg.TargetArch)
 	if err != nil {
 		return nil, err
@@ -207,7 +343,7 @@ func ParseEnabledSyscalls(cfg *Config) (map[int]bool, error) {
 		for _, c := range cfg.Enable_Syscalls {
 			n := 0
 			for _, call := range target.Syscalls {
-				if match(call, c) {
+				if MatchSyscall(call, c) {
 					syscalls[call.ID] = true
 					n++
 				}
@@ -224,7 +360,7 @@ func ParseEnabledSyscalls(cfg *Config) (map[int]bool, error) {
 	for _, c := range cfg.Disable_Syscalls {
 		n := 0
 		for _, call := range target.Syscalls {
-			if match(call, c) {
+			if MatchSyscall(call, c) {
 				delete(syscalls, call.ID)
 				n++
 			}
@@ -263,9 +399,92 @@ func parseSuppressions(cfg *Config) error {
 		}
 		cfg.ParsedIgnores = append(cfg.ParsedIgnores, re)
 	}
+	for _, stop := range cfg.Campaign_Stop_Crashes {
+		re, err := regexp.Compile(stop)
+		if err != nil {
+			return fmt.Errorf("failed to compile campaign stop crash '%v': %v", stop, err)
+		}
+		cfg.ParsedStopCrashes = append(cfg.ParsedStopCrashes, re)
+	}
+	return nil
+}
+
+// Campaign returns whether the manager runs in campaign mode.
+func (cfg *Config) Campaign() bool {
+	return cfg.Campaign_Duration != 0 || cfg.Campaign_Execs != 0 ||
+		cfg.Campaign_Stop_No_Cover != 0 || len(cfg.Campaign_Stop_Crashes) != 0
+}
+
+// FocusTarget is a parsed entry of Config.Focus.
+// Either Func is set, or File is set and then Line/EndLine are optionally set.
+type FocusTarget struct {
+	Func    string
+	File    string
+	Line    int
+	EndLine int
+}
+
+func (target FocusTarget) String() string {
+	switch {
+	case target.Func != "":
+		return target.Func
+	case target.Line == 0:
+		return target.File
+	case target.Line == target.EndLine:
+		return fmt.Sprintf("%v:%v", target.File, target.Line)
+	default:
+		return fmt.Sprintf("%v:%v-%v", target.File, target.Line, target.EndLine)
+	}
+}
+
+func parseFocus(cfg *Config) error {
+	if len(cfg.Focus) != 0 && cfg.Vmlinux == "" {
+		return fmt.Errorf("focus is set, but vmlinux is empty")
+	}
+	for _, focus := range cfg.Focus {
+		target, err := ParseFocusTarget(focus)
+		if err != nil {
+			return err
+		}
+		cfg.ParsedFocus = append(cfg.ParsedFocus, target)
+	}
 	return nil
 }
 
+// ParseFocusTarget parses a function name, a file name or a file:line[-line] focus entry.
+func ParseFocusTarget(focus string) (FocusTarget, error) {
+	var target FocusTarget
+	if focus == "" || strings.ContainsAny(focus, " \t") {
+		return target, fmt.Errorf("bad focus entry %q", focus)
+	}
+	colon := strings.LastIndexByte(focus, ':')
+	if colon == -1 {
+		if strings.ContainsAny(focus, "./") {
+			target.File = focus
+		} else {
+			target.Func = focus
+		}
+		return target, nil
+	}
+	target.File = focus[:colon]
+	lines := focus[colon+1:]
+	var err1, err2 error
+	if dash := strings.IndexByte(lines, '-'); dash != -1 {
+		target.Line, err1 = strconv.Atoi(lines[:dash])
+		target.EndLine, err2 = strconv.Atoi(lines[dash+1:])
+	} else {
+		target.Line, err1 = strconv.Atoi(lines)
+		target.EndLine = target.Line
+	}
+	if err1 != nil || err2 != nil {
+		return target, fmt.Errorf("bad line in focus entry %q", focus)
+	}
+	if target.File == "" || target.Line <= 0 || target.EndLine < target.Line {
+		return target, fmt.Errorf("bad focus entry %q", focus)
+	}
+	return target, nil
+}
+
 func CreateVMEnv(cfg *Config, debug bool) *vm.Env {
 	return &vm.Env{
 		Name:    cfg.Name,
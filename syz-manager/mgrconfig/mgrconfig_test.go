@@ -38,3 +38,34 @@ func TestCanned(t *testing.T) {
 		})
 	}
 }
+
+func TestParseFocusTarget(t *testing.T) {
+	tests := []struct {
+		focus  string
+		target FocusTarget
+	}{
+		{"tcp_sendmsg", FocusTarget{Func: "tcp_sendmsg"}},
+		{"net/ipv4/tcp.c", FocusTarget{File: "net/ipv4/tcp.c"}},
+		{"tcp.c", FocusTarget{File: "tcp.c"}},
+		{"net/ipv4/tcp.c:1200", FocusTarget{File: "net/ipv4/tcp.c", Line: 1200, EndLine: 1200}},
+		{"net/ipv4/tcp.c:1200-1250", FocusTarget{File: "net/ipv4/tcp.c", Line: 1200, EndLine: 1250}},
+	}
+	for _, test := range tests {
+		target, err := ParseFocusTarget(test.focus)
+		if err != nil {
+			t.Errorf("failed to parse %q: %v", test.focus, err)
+			continue
+		}
+		if target != test.target {
+			t.Errorf("%q: got %+v, want %+v", test.focus, target, test.target)
+		}
+		if target.String() != test.focus {
+			t.Errorf("%q: printed as %q", test.focus, target.String())
+		}
+	}
+	for _, focus := range []string{"", "tcp.c:", ":10", "tcp.c:x", "tcp.c:10-", "tcp.c:20-10", "tcp.c:0", "a b"} {
+		if _, err := ParseFocusTarget(focus); err == nil {
+			t.Errorf("bad focus entry %q is accepted", focus)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/google/syzkaller/dashboard/dashapi"
+	"github.com/google/syzkaller/pkg/csource"
+	. "github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/repro"
+	"github.com/google/syzkaller/prog"
+)
+
+// Regression sweep: on start manager re-runs all stored reproducers (crashes/*/repro.prog)
+// to check whether the crashes still reproduce on the new kernel.
+// Results are saved in crashes/*/repro.retest and reported to dashboard.
+
+const (
+	retestReproducing = "reproducing" // crashed with the original title in all attempts
+	retestFixed       = "fixed"       // did not crash in any attempt
+	retestFlaky       = "flaky"       // crashed in some attempts or only with other titles
+
+	// The longest duration used by pkg/repro, enough to catch races and hangs.
+	retestDuration = 5 * time.Minute
+)
+
+type RetestStatus struct {
+	Status   string
+	Crashed  int // attempts that crashed with the original title
+	Other    int // attempts that crashed with a different title
+	Attempts int
+	Tag      string // kernel build the reproducer was retested on
+	Time     time.Time
+	Desc     string // title of the crash that happened during retest (can differ from the original)
+}
+
+type Retest struct {
+	dir  string
+	desc string
+	p    *prog.Prog
+	opts csource.Options
+}
+
+type RetestResult struct {
+	instances []int
+	retest    *Retest
+	res       *repro.RetestResult
+	err       error
+}
+
+// collectRetests returns reproducers that were not yet retested on the current kernel build.
+// If tag is not configured, reproducers are retested on every manager start.
+func (mgr *Manager) collectRetests() []*Retest {
+	if mgr.cfg.Retest_Repros <= 0 {
+		return nil
+	}
+	dirs, err := osutil.ListDir(mgr.crashdir)
+	if err != nil {
+		Logf(0, "failed to list crashes: %v", err)
+		return nil
+	}
+	var retests []*Retest
+	for _, dir := range dirs {
+		dir = filepath.Join(mgr.crashdir, dir)
+		data, err := ioutil.ReadFile(filepath.Join(dir, "repro.prog"))
+		if err != nil {
+			continue
+		}
+		if st := readRetest(dir); st != nil && mgr.cfg.Tag != "" && st.Tag == mgr.cfg.Tag {
+			continue
+		}
+		desc, err := ioutil.ReadFile(filepath.Join(dir, "description"))
+		if err != nil {
+			continue
+		}
+		retest, err := mgr.parseRetest(dir, string(trimNewLines(desc)), data)
+		if err != nil {
+			Logf(0, "failed to parse reproducer %v: %v", dir, err)
+			continue
+		}
+		retests = append(retests, retest)
+	}
+	if len(retests) != 0 {
+		Logf(0, "retesting %v reproducers", len(retests))
+	}
+	return retests
+}
+
+// parseRetest parses repro.prog that is saved as "# OPTS\nPROGRAM".
+func (mgr *Manager) parseRetest(dir, desc string, data []byte) (*Retest, error) {
+	nl := bytes.IndexByte(data, '\n')
+	if !bytes.HasPrefix(data, []byte("# ")) || nl == -1 {
+		return nil, fmt.Errorf("no options header")
+	}
+	opts, err := csource.ParseOptions(data[2:nl])
+	if err != nil {
+		return nil, err
+	}
+	p, err := mgr.target.Deserialize(data[nl+1:])
+	if err != nil {
+		return nil, err
+	}
+	return &Retest{
+		dir:  dir,
+		desc: desc,
+		p:    p,
+		opts: opts,
+	}, nil
+}
+
+func (mgr *Manager) runRetest(retest *Retest, vmIndexes []int) (*repro.RetestResult, error) {
+	return repro.Retest(retest.p, retest.opts, retest.desc, retestDuration, mgr.cfg.Retest_Repros,
+		mgr.cfg, mgr.vmPool, vmIndexes)
+}
+
+func (mgr *Manager) saveRetest(retest *Retest, res *repro.RetestResult) {
+	st := &RetestStatus{
+		Status:   retestFlaky,
+		Crashed:  res.Crashed,
+		Other:    res.Other,
+		Attempts: res.Attempts,
+		Tag:      mgr.cfg.Tag,
+		Time:     time.Now(),
+		Desc:     res.Desc,
+	}
+	dashStatus := dashapi.RetestFlaky
+	// A crash with a different title (e.g. lost connection) can mask the original one,
+	// so such attempts count neither as reproduced nor as fixed.
+	switch {
+	case res.Crashed == 0 && res.Other == 0:
+		st.Status = retestFixed
+		dashStatus = dashapi.RetestFixed
+	case res.Crashed == res.Attempts:
+		st.Status = retestReproducing
+		dashStatus = dashapi.RetestReproducing
+	}
+	Logf(0, "retest of '%v': %v (crashed %v/%v, %v with other titles)",
+		retest.desc, st.Status, res.Crashed, res.Attempts, res.Other)
+	data, err := json.MarshalIndent(st, "", "\t")
+	if err != nil {
+		Logf(0, "failed to marshal retest status: %v", err)
+		return
+	}
+	if err := osutil.WriteFile(filepath.Join(retest.dir, "repro.retest"), data); err != nil {
+		Logf(0, "failed to write retest status: %v", err)
+	}
+	mgr.mu.Lock()
+	mgr.stats["retest "+st.Status]++
+	mgr.mu.Unlock()
+	if mgr.dash != nil {
+		dr := &dashapi.Retest{
+			BuildID: mgr.cfg.Tag,
+			Title:   retest.desc,
+			Status:  dashStatus,
+		}
+		if err := mgr.dash.ReportRetest(dr); err != nil {
+			Logf(0, "failed to report retest to dashboard: %v", err)
+		}
+	}
+}
+
+// readRetest returns the last retest status of the crash in dir, or nil if it was not retested.
+func readRetest(dir string) *RetestStatus {
+	data, err := ioutil.ReadFile(filepath.Join(dir, "repro.retest"))
+	if err != nil {
+		return nil
+	}
+	st := new(RetestStatus)
+	if err := json.Unmarshal(data, st); err != nil {
+		return nil
+	}
+	return st
+}
@@ -0,0 +1,143 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// syz-cover exports kernel coverage without a running manager.
+// Input files are in the format of manager /rawcover page (one hex PC per line),
+// coverage of all input files is merged.
+//
+// With -diff syz-cover compares coverage of two files (e.g. /rawcover of two managers)
+// and prints newly covered and no longer covered functions and lines.
+// The files can also be manager coverage snapshots (workdir/coversnap/*.json.gz),
+// these don't need vmlinux.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/coverreport"
+	"github.com/google/syzkaller/pkg/symbolizer"
+)
+
+var (
+	flagVmlinux    = flag.String("vmlinux", "", "path to vmlinux with debug info")
+	flagOldVmlinux = flag.String("old_vmlinux", "", "path to vmlinux for the old coverage file in -diff mode"+
+		" (if it comes from a different kernel build, -vmlinux by default)")
+	flagFormat = flag.String("format", "", "output format: "+strings.Join(coverreport.Formats, ", ")+
+		" (lcov by default), with -diff: "+strings.Join(coverreport.DiffFormats, ", ")+" (text by default)")
+	flagDiff   = flag.Bool("diff", false, "compare coverage of two files: old new")
+	flagOutput = flag.String("o", "", "output file (stdout by default)")
+)
+
+func main() {
+	flag.Parse()
+	if len(flag.Args()) == 0 || *flagDiff && len(flag.Args()) != 2 {
+		fmt.Fprintf(os.Stderr, "usage: syz-cover -vmlinux vmlinux [flags] rawcover_file...\n")
+		fmt.Fprintf(os.Stderr, "       syz-cover -diff [-vmlinux vmlinux] [flags] old_file new_file\n")
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+	if *flagDiff {
+		if *flagFormat == "" {
+			*flagFormat = "text"
+		}
+		if *flagFormat != "text" && *flagFormat != "json" {
+			failf("unknown diff format %q, supported: %v", *flagFormat, strings.Join(coverreport.DiffFormats, ", "))
+		}
+		if *flagOldVmlinux == "" {
+			*flagOldVmlinux = *flagVmlinux
+		}
+		old := loadProfile(*flagOldVmlinux, flag.Args()[:1])
+		new := loadProfile(*flagVmlinux, flag.Args()[1:])
+		diff := coverreport.MakeDiff(old, new)
+		writeOutput(func(w io.Writer) error {
+			return coverreport.ExportDiff(w, diff, *flagFormat)
+		})
+		return
+	}
+	if *flagFormat == "" {
+		*flagFormat = "lcov"
+	}
+	if !coverreport.IsFormat(*flagFormat) {
+		failf("unknown format %q, supported: %v", *flagFormat, strings.Join(coverreport.Formats, ", "))
+	}
+	profile := loadProfile(*flagVmlinux, flag.Args())
+	writeOutput(func(w io.Writer) error {
+		return coverreport.Export(w, profile, *flagFormat)
+	})
+}
+
+// loadProfile builds coverage profile for merged raw coverage files,
+// or reads the profile from a coverage snapshot.
+func loadProfile(vmlinux string, files []string) *coverreport.Profile {
+	if len(files) == 1 && strings.HasSuffix(files[0], ".json.gz") {
+		snapshot, err := coverreport.ReadSnapshot(files[0])
+		if err != nil {
+			failf("%v", err)
+		}
+		return snapshot.Profile
+	}
+	if vmlinux == "" {
+		failf("-vmlinux is required for raw coverage files")
+	}
+	var pcs []uint64
+	seen := make(map[uint64]bool)
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			failf("failed to read input file: %v", err)
+		}
+		filePCs, err := coverreport.ParsePCs(data)
+		if err != nil {
+			failf("failed to parse %v: %v", file, err)
+		}
+		for _, pc := range filePCs {
+			if !seen[pc] {
+				seen[pc] = true
+				pcs = append(pcs, pc)
+			}
+		}
+	}
+	if len(pcs) == 0 {
+		failf("no coverage in %v", strings.Join(files, ", "))
+	}
+	coverPCs, err := coverreport.CoveredPCs(vmlinux)
+	if err != nil {
+		failf("failed to run objdump: %v", err)
+	}
+	symbols, err := symbolizer.ReadSymbols(vmlinux)
+	if err != nil {
+		failf("failed to run nm: %v", err)
+	}
+	profile, err := coverreport.MakeProfile(vmlinux, coverreport.SortedSymbols(symbols), coverPCs, pcs)
+	if err != nil {
+		failf("failed to generate coverage profile: %v", err)
+	}
+	return profile
+}
+
+func writeOutput(write func(w io.Writer) error) {
+	out := os.Stdout
+	if *flagOutput != "" {
+		var err error
+		out, err = os.Create(*flagOutput)
+		if err != nil {
+			failf("failed to create output file: %v", err)
+		}
+	}
+	if err := write(out); err != nil {
+		failf("failed to write coverage: %v", err)
+	}
+	if err := out.Close(); err != nil {
+		failf("failed to write coverage: %v", err)
+	}
+}
+
+func failf(msg string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, msg+"\n", args...)
+	os.Exit(1)
+}
@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/google/syzkaller/pkg/cover"
+	"github.com/google/syzkaller/pkg/host"
 	"github.com/google/syzkaller/pkg/ipc"
 	. "github.com/google/syzkaller/pkg/log"
 	"github.com/google/syzkaller/pkg/osutil"
@@ -34,6 +35,12 @@ var (
 	flagFaultCall = flag.Int("fault_call", -1, "inject fault into this call (0-based)")
 	flagFaultNth  = flag.Int("fault_nth", 0, "inject fault on n-th operation (0-based)")
 	flagHints     = flag.Bool("hints", false, "do a hints-generation run")
+	flagLeak      = flag.Bool("leak", false, "scan for memory leaks after programs and print them")
+
+	flagRaceCall1    = flag.Int("race_call1", -1, "race this call with race_call2 (0-based)")
+	flagRaceCall2    = flag.Int("race_call2", 0, "call to start concurrently with race_call1 (0-based)")
+	flagRaceDelay    = flag.Int("race_delay", 0, "start race_call2 after this number of microseconds")
+	flagRaceAffinity = flag.Bool("race_affinity", false, "pin racing calls to different CPUs")
 )
 
 func main() {
@@ -92,6 +99,15 @@ func main() {
 		execOpts.FaultCall = *flagFaultCall
 		execOpts.FaultNth = *flagFaultNth
 	}
+	if *flagRaceCall1 >= 0 {
+		execOpts.Flags |= ipc.FlagRace
+		if *flagRaceAffinity {
+			execOpts.Flags |= ipc.FlagRaceAffinity
+		}
+		execOpts.RaceCall1 = *flagRaceCall1
+		execOpts.RaceCall2 = *flagRaceCall2
+		execOpts.RaceDelay = *flagRaceDelay
+	}
 
 	handled := make(map[string]bool)
 	for _, prog := range progs {
@@ -106,7 +122,17 @@ func main() {
 	var wg sync.WaitGroup
 	wg.Add(*flagProcs)
 	var posMu, logMu sync.Mutex
-	gate := ipc.NewGate(2**flagProcs, nil)
+	var leakCallback func()
+	if *flagLeak {
+		if err := host.KmemleakInit(true); err != nil {
+			Fatalf("%v", err)
+		}
+		if _, err := host.KmemleakScan(false); err != nil {
+			Fatalf("failed to scan for leaks: %v", err)
+		}
+		leakCallback = kmemleakScan
+	}
+	gate := ipc.NewGate(2**flagProcs, leakCallback)
 	var pos int
 	var lastPrint time.Time
 	shutdown := make(chan struct{})
@@ -144,7 +170,7 @@ func main() {
 						Logf(0, "executing program %v:\n%s", pid, data)
 						logMu.Unlock()
 					}
-					output, info, failed, hanged, err := env.Exec(execOpts, p)
+					output, info, _, failed, hanged, err := env.Exec(execOpts, p)
 					select {
 					case <-shutdown:
 						return false
@@ -178,7 +204,8 @@ func main() {
 					}
 					if *flagHints {
 						compMaps := ipc.GetCompMaps(info)
-						p.MutateWithHints(compMaps, func(p *prog.Prog) {
+						stringCompMaps := ipc.GetStringCompMaps(info)
+						p.MutateWithHints(compMaps, stringCompMaps, func(p *prog.Prog) {
 							fmt.Printf("%v\n", string(p.Serialize()))
 						})
 					}
@@ -193,4 +220,18 @@ func main() {
 
 	osutil.HandleInterrupts(shutdown)
 	wg.Wait()
+	if *flagLeak {
+		kmemleakScan()
+	}
+}
+
+func kmemleakScan() {
+	leaks, err := host.KmemleakScan(true)
+	if err != nil {
+		Fatalf("failed to scan for leaks: %v", err)
+	}
+	if len(leaks) != 0 {
+		// BUG in output should be recognized by manager.
+		Logf(0, "BUG: memory leak:\n%s\n", leaks)
+	}
 }
@@ -25,6 +25,10 @@ var (
 	flagProg       = flag.String("prog", "", "file with program to convert (required)")
 	flagFaultCall  = flag.Int("fault_call", -1, "inject fault into this call (0-based)")
 	flagFaultNth   = flag.Int("fault_nth", 0, "inject fault on n-th operation (0-based)")
+	flagRaceCall1  = flag.Int("race_call1", -1, "race this call with race_call2 (0-based)")
+	flagRaceCall2  = flag.Int("race_call2", 0, "call to start concurrently with race_call1 (0-based)")
+	flagRaceDelay  = flag.Int("race_delay", 0, "start race_call2 after this number of microseconds")
+	flagRaceAff    = flag.Bool("race_affinity", false, "pin racing calls to different CPUs")
 	flagEnableTun  = flag.Bool("tun", false, "set up TUN/TAP interface")
 	flagUseTmpDir  = flag.Bool("tmpdir", false, "create a temporary dir and execute inside it")
 	flagHandleSegv = flag.Bool("segv", false, "catch and ignore SIGSEGV")
@@ -54,20 +58,25 @@ func main() {
 		os.Exit(1)
 	}
 	opts := csource.Options{
-		Threaded:   *flagThreaded,
-		Collide:    *flagCollide,
-		Repeat:     *flagRepeat,
-		Procs:      *flagProcs,
-		Sandbox:    *flagSandbox,
-		Fault:      *flagFaultCall >= 0,
-		FaultCall:  *flagFaultCall,
-		FaultNth:   *flagFaultNth,
-		EnableTun:  *flagEnableTun,
-		UseTmpDir:  *flagUseTmpDir,
-		HandleSegv: *flagHandleSegv,
-		WaitRepeat: *flagWaitRepeat,
-		Debug:      *flagDebug,
-		Repro:      false,
+		Threaded:     *flagThreaded,
+		Collide:      *flagCollide,
+		Repeat:       *flagRepeat,
+		Procs:        *flagProcs,
+		Sandbox:      *flagSandbox,
+		Fault:        *flagFaultCall >= 0,
+		FaultCall:    *flagFaultCall,
+		FaultNth:     *flagFaultNth,
+		Race:         *flagRaceCall1 >= 0,
+		RaceCall1:    *flagRaceCall1,
+		RaceCall2:    *flagRaceCall2,
+		RaceDelay:    *flagRaceDelay,
+		RaceAffinity: *flagRaceCall1 >= 0 && *flagRaceAff,
+		EnableTun:    *flagEnableTun,
+		UseTmpDir:    *flagUseTmpDir,
+		HandleSegv:   *flagHandleSegv,
+		WaitRepeat:   *flagWaitRepeat,
+		Debug:        *flagDebug,
+		Repro:        false,
 	}
 	src, err := csource.Write(p, opts)
 	if err != nil {
@@ -102,7 +102,7 @@ func execute(pid int, env *ipc.Env, p *prog.Prog) {
 		outMu.Unlock()
 	}
 	opts := &ipc.ExecOpts{}
-	output, _, failed, hanged, err := env.Exec(opts, p)
+	output, _, _, failed, hanged, err := env.Exec(opts, p)
 	if err != nil {
 		fmt.Printf("failed to execute executor: %v\n", err)
 	}